@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store/sqlstore"
@@ -63,6 +64,8 @@ func main() {
 	
 	// STEP 7: Setup Auto Promote System (jika diaktifkan)
 	var autoPromoteService *services.AutoPromoteService
+	var sendQueueService *services.SendQueueService
+	var leaderService *services.LeaderService
 	var templateService *services.TemplateService
 	var promoteCommandHandler *handlers.PromoteCommandHandler
 	var adminCommandHandler *handlers.AdminCommandHandler
@@ -79,16 +82,83 @@ func main() {
 		defer promoteDB.Close()
 		
 		// Setup services
-		templateService = services.NewTemplateService(promoteRepo, logger)
-		autoPromoteService = services.NewAutoPromoteService(client, promoteRepo, logger)
+		// Renderer template dipakai bersama oleh pengiriman, preview, dan test
+		groupInfoCache := services.NewGroupInfoCache(client, logger)
+		templateRenderer := services.NewTemplateRenderer(promoteRepo, groupInfoCache, logger)
+		// Bahasa default untuk {DATE}/{DAY}/{MONTH} dan terjemahan, grup bisa diatur dengan .setlocale
+		if err := templateRenderer.SetDefaultLocale(promoteCfg.Locale); err != nil {
+			logger.Warningf("PROMOTE_LOCALE tidak valid, menggunakan id: %v", err)
+		}
+		templateService = services.NewTemplateService(promoteRepo, templateRenderer, logger)
+		// Read receipt dan balasan pesan promosi dicatat untuk laporan A/B (.abreport)
+		services.NewEngagementTracker(client, promoteRepo, logger)
+		// Semua pesan promosi dikirim bertahap lewat antrian persisten
+		sendQueueService = services.NewSendQueueService(client, promoteRepo, logger)
+		sendQueueService.SetLimits(promoteCfg.MaxMessagesPerMinute, promoteCfg.DailyMessageLimit)
+		sendQueueService.SetSpread(time.Duration(promoteCfg.SpreadMinutes) * time.Minute)
+		// Media template (.attachmedia) disimpan di folder lokal dan diunggah saat dikirim
+		mediaStore, err := services.NewMediaStore(promoteCfg.MediaDir, logger)
+		if err != nil {
+			logger.Warningf("Media template tidak tersedia, promosi dikirim sebagai teks: %v", err)
+		} else {
+			templateService.SetMediaStore(mediaStore)
+			sendQueueService.SetMediaStore(mediaStore)
+		}
+		// Strategi pemilihan template dipakai bersama oleh auto promote dan test grup
+		templateSelector := services.NewTemplateSelector(promoteRepo, logger)
+		if err := templateSelector.SetDefaultStrategy(promoteCfg.TemplateStrategy); err != nil {
+			logger.Warningf("PROMOTE_TEMPLATE_STRATEGY tidak valid, menggunakan random: %v", err)
+		}
+		if err := templateSelector.LoadSettings(); err != nil {
+			logger.Warningf("Gagal memuat strategi template: %v", err)
+		}
+		autoPromoteService = services.NewAutoPromoteService(client, promoteRepo, sendQueueService, templateSelector, templateRenderer, logger)
 		// Set interval dari konfigurasi
 		autoPromoteService.SetInterval(promoteCfg.AutoPromoteInterval)
+		// Interval yang diatur admin lewat .setinterval menimpa konfigurasi
+		if err := autoPromoteService.LoadSettings(); err != nil {
+			logger.Warningf("Gagal memuat pengaturan auto promote: %v", err)
+		}
+		// Timezone dan jam kirim global dari konfigurasi
+		if err := autoPromoteService.SetTimezone(promoteCfg.Timezone); err != nil {
+			logger.Warningf("Timezone tidak valid, menggunakan waktu lokal: %v", err)
+		}
+		if err := autoPromoteService.SetDefaultSendWindow(promoteCfg.SendWindow); err != nil {
+			logger.Warningf("PROMOTE_SEND_WINDOW tidak valid, promosi dikirim 24 jam: %v", err)
+		}
+		if err := autoPromoteService.SetMissedSlotPolicy(promoteCfg.MissedSlotPolicy); err != nil {
+			logger.Warningf("PROMOTE_MISSED_POLICY tidak valid, menggunakan once: %v", err)
+		}
+		// Campaign mendapat prioritas di atas rotasi biasa selama periode berjalan
+		campaignService := services.NewCampaignService(promoteRepo, logger)
+		autoPromoteService.SetCampaignService(campaignService)
+		// Broadcast sekali jalan (.schedule) dijalankan oleh scheduler yang sama
+		scheduledJobService := services.NewScheduledJobService(promoteRepo, logger)
+		autoPromoteService.SetScheduledJobService(scheduledJobService)
+		// Template kedaluwarsa dinonaktifkan scheduler dan admin diberi tahu lewat chat pribadi
+		autoPromoteService.SetTemplateService(templateService)
+		autoPromoteService.SetAdminNotifier(services.NewAdminNotifier(client, promoteCfg.AdminNumbers, logger))
+		// Hanya instance pemegang lease yang menjalankan scheduler dan antrian kirim
+		leaderService = services.NewLeaderService(promoteRepo, promoteCfg.InstanceID, logger)
+		autoPromoteService.SetLeaderService(leaderService)
+		leaderService.OnElected(func() {
+			sendQueueService.Start()
+			autoPromoteService.StartScheduler()
+		})
+		leaderService.OnDemoted(func() {
+			autoPromoteService.StopScheduler()
+			sendQueueService.Stop()
+		})
 		apiProductService := services.NewAPIProductService(templateService, logger)
-		groupManagerService := services.NewGroupManagerService(client, promoteRepo, logger)
+		groupManagerService := services.NewGroupManagerService(client, promoteRepo, sendQueueService, templateSelector, templateRenderer, logger)
+		groupManagerService.SetLocation(autoPromoteService.GetTimezone())
 		
 		// Setup command handlers
 		promoteCommandHandler = handlers.NewPromoteCommandHandler(autoPromoteService, templateService, logger)
 		adminCommandHandler = handlers.NewAdminCommandHandler(autoPromoteService, templateService, apiProductService, groupManagerService, logger, promoteCfg.AdminNumbers)
+		adminCommandHandler.SetCampaignService(campaignService)
+		adminCommandHandler.SetScheduledJobService(scheduledJobService)
+		adminCommandHandler.SetClient(client)
 		
 		logger.Success("Auto Promote System initialized!")
 	}
@@ -131,10 +201,14 @@ func main() {
 	// STEP 11: Start Auto Promote Scheduler (jika diaktifkan)
 	if autoPromoteService != nil {
 		logger.Info("Starting Auto Promote Scheduler...")
-		autoPromoteService.StartScheduler()
+		// Scheduler dan antrian kirim dijalankan oleh callback saat lease didapat
+		leaderService.Start()
+		if !leaderService.IsLeader() {
+			logger.Warning("Instance lain memegang lock scheduler, berjalan sebagai standby")
+		}
 		
 		// Log konfigurasi auto promote
-		logger.Infof("Auto Promote Config: %d admin(s), %d hour interval", 
+		logger.Infof("Auto Promote Config: %d admin(s), %v interval", 
 			len(promoteCfg.AdminNumbers), promoteCfg.AutoPromoteInterval)
 	}
 	
@@ -160,7 +234,8 @@ func main() {
 	// Stop auto promote scheduler jika berjalan
 	if autoPromoteService != nil {
 		logger.Info("Stopping Auto Promote Scheduler...")
-		autoPromoteService.StopScheduler()
+		// Melepas lease juga menghentikan scheduler dan antrian kirim
+		leaderService.Stop()
 	}
 	
 	client.Disconnect()
@@ -4,7 +4,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // PromoteConfig berisi konfigurasi untuk fitur auto promote
@@ -12,11 +14,41 @@ type PromoteConfig struct {
 	// DatabasePath untuk database auto promote (terpisah dari session)
 	PromoteDatabasePath string
 
+	// MediaDir adalah folder penyimpanan media template (default: data/media)
+	MediaDir string
+
 	// AdminNumbers adalah daftar nomor WhatsApp admin yang bisa mengelola template
 	AdminNumbers []string
 
-	// AutoPromoteInterval dalam jam (default: 4 jam)
-	AutoPromoteInterval int
+	// AutoPromoteInterval default (default: 4 jam). Bisa ditimpa admin lewat .setinterval
+	AutoPromoteInterval time.Duration
+
+	// Timezone untuk jadwal dan jam kirim (default: Asia/Jakarta)
+	Timezone string
+
+	// Locale adalah bahasa default promosi untuk grup tanpa .setlocale: id, en, ms (default: id)
+	Locale string
+
+	// SendWindow adalah jam kirim global, misal "08:00-21:00" (kosong = 24 jam)
+	SendWindow string
+
+	// MaxMessagesPerMinute batas global pesan promosi per menit (default: 4)
+	MaxMessagesPerMinute int
+
+	// DailyMessageLimit batas pesan promosi per hari untuk akun ini (default: 200)
+	DailyMessageLimit int
+
+	// SpreadMinutes rentang jitter promosi terjadwal dalam menit (default: 30)
+	SpreadMinutes int
+
+	// MissedSlotPolicy perlakuan jadwal terlewat saat startup: once, skip, spread (default: once)
+	MissedSlotPolicy string
+
+	// TemplateStrategy strategi pemilihan template: random, roundrobin, leastrecent, norepeat:N (default: random)
+	TemplateStrategy string
+
+	// InstanceID nama instance untuk lease scheduler (default: hostname-PID)
+	InstanceID string
 
 	// MaxTemplatesPerCategory maksimal template per kategori
 	MaxTemplatesPerCategory int
@@ -34,11 +66,37 @@ func NewPromoteConfig() *PromoteConfig {
 		// Database terpisah untuk auto promote
 		PromoteDatabasePath: getEnvOrDefault("PROMOTE_DB_PATH", "data/promote.db"),
 
+		// Media template (gambar/video/dokumen) disimpan di samping database
+		MediaDir: getEnvOrDefault("PROMOTE_MEDIA_DIR", "data/media"),
+
 		// Admin numbers dari environment variable (pisahkan dengan koma)
 		AdminNumbers: getAdminNumbers(),
 
-		// Interval default 4 jam
-		AutoPromoteInterval: getEnvIntOrDefault("AUTO_PROMOTE_INTERVAL", 4),
+		// Interval default 4 jam (angka polos = jam, atau durasi seperti "90m")
+		AutoPromoteInterval: getEnvDurationOrDefault("AUTO_PROMOTE_INTERVAL", 4*time.Hour),
+
+		// Timezone eksplisit agar jadwal tidak bergantung pada TZ container
+		Timezone: getEnvOrDefault("PROMOTE_TIMEZONE", "Asia/Jakarta"),
+
+		// Bahasa default untuk {DATE}, {DAY}, {MONTH} dan terjemahan template
+		Locale: getEnvOrDefault("PROMOTE_LOCALE", "id"),
+
+		// Jam kirim global, default tanpa batasan
+		SendWindow: getEnvOrDefault("PROMOTE_SEND_WINDOW", ""),
+
+		// Rate limit antrian kirim untuk menghindari burst
+		MaxMessagesPerMinute: getEnvIntOrDefault("PROMOTE_MAX_PER_MINUTE", 4),
+		DailyMessageLimit:    getEnvIntOrDefault("PROMOTE_DAILY_LIMIT", 200),
+		SpreadMinutes:        getEnvIntOrDefault("PROMOTE_SPREAD_MINUTES", 30),
+
+		// Grup yang terlewat saat bot mati dikirimi sekali secara default
+		MissedSlotPolicy: getEnvOrDefault("PROMOTE_MISSED_POLICY", "once"),
+
+		// Weighted random sesuai bobot template
+		TemplateStrategy: getEnvOrDefault("PROMOTE_TEMPLATE_STRATEGY", "random"),
+
+		// Kosong berarti ID dibuat dari hostname dan PID
+		InstanceID: getEnvOrDefault("PROMOTE_INSTANCE_ID", ""),
 
 		// Maksimal 20 template per kategori
 		MaxTemplatesPerCategory: getEnvIntOrDefault("MAX_TEMPLATES_PER_CATEGORY", 20),
@@ -79,31 +137,32 @@ func getAdminNumbers() []string {
 // getEnvIntOrDefault mengambil nilai integer dari environment variable
 func getEnvIntOrDefault(key string, defaultValue int) int {
 	if value := os.Getenv(key); value != "" {
-		// Simple parsing (bisa diperbaiki dengan strconv.Atoi)
-		switch value {
-		case "1":
-			return 1
-		case "2":
-			return 2
-		case "3":
-			return 3
-		case "4":
-			return 4
-		case "6":
-			return 6
-		case "8":
-			return 8
-		case "12":
-			return 12
-		case "24":
-			return 24
-		default:
-			return defaultValue
+		if parsed, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return parsed
 		}
 	}
 	return defaultValue
 }
 
+// getEnvDurationOrDefault mengambil durasi dari environment variable.
+// Angka tanpa satuan dianggap jam agar konfigurasi lama tetap berlaku.
+func getEnvDurationOrDefault(key string, defaultValue time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	if hours, err := strconv.Atoi(value); err == nil {
+		return time.Duration(hours) * time.Hour
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil {
+		return duration
+	}
+
+	return defaultValue
+}
+
 // IsAdmin mengecek apakah nomor adalah admin
 func (c *PromoteConfig) IsAdmin(phoneNumber string) bool {
 	for _, admin := range c.AdminNumbers {
@@ -163,8 +222,30 @@ func (c *PromoteConfig) ValidateConfig() []string {
 		errors = append(errors, "Minimal harus ada 1 admin")
 	}
 
-	if c.AutoPromoteInterval < 1 || c.AutoPromoteInterval > 24 {
-		errors = append(errors, "Interval auto promote harus antara 1-24 jam")
+	if c.AutoPromoteInterval < time.Minute || c.AutoPromoteInterval > 24*time.Hour {
+		errors = append(errors, "Interval auto promote harus antara 1 menit - 24 jam")
+	}
+
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		errors = append(errors, fmt.Sprintf("Timezone tidak valid: %s", c.Timezone))
+	}
+
+	if c.MaxMessagesPerMinute < 1 {
+		errors = append(errors, "Batas pesan per menit minimal 1")
+	}
+
+	if c.DailyMessageLimit < 1 {
+		errors = append(errors, "Batas pesan harian minimal 1")
+	}
+
+	if c.SpreadMinutes < 0 {
+		errors = append(errors, "Rentang jitter tidak boleh negatif")
+	}
+
+	switch strings.ToLower(c.MissedSlotPolicy) {
+	case "once", "skip", "spread":
+	default:
+		errors = append(errors, "Missed slot policy harus once, skip, atau spread")
 	}
 
 	if c.MaxTemplatesPerCategory < 1 {
@@ -179,8 +260,15 @@ func (c *PromoteConfig) GetConfigInfo() string {
 	return fmt.Sprintf(`⚙️ **KONFIGURASI AUTO PROMOTE**
 
 📁 **Database:** %s
+🖼️ **Folder Media:** %s
 👑 **Admin:** %d orang
-⏰ **Interval:** %d jam
+⏰ **Interval:** %v
+🌏 **Timezone:** %s
+🗣️ **Bahasa Default:** %s
+🕘 **Jam Kirim:** %s
+🚦 **Batas Kirim:** %d/menit, %d/hari
+🎲 **Jitter:** %d menit
+⏮️ **Jadwal Terlewat:** %s
 📝 **Max Template/Kategori:** %d
 🤖 **Status:** %s
 📊 **Logging:** %s
@@ -189,13 +277,31 @@ func (c *PromoteConfig) GetConfigInfo() string {
 
 💡 **Environment Variables:**
 • PROMOTE_DB_PATH - Path database
+• PROMOTE_MEDIA_DIR - Folder media template
 • ADMIN_NUMBERS - Nomor admin (pisah koma)
-• AUTO_PROMOTE_INTERVAL - Interval jam
+• AUTO_PROMOTE_INTERVAL - Interval (jam, atau misal 90m)
+• PROMOTE_TIMEZONE - Timezone jadwal (misal Asia/Jakarta)
+• PROMOTE_LOCALE - Bahasa default (id/en/ms)
+• PROMOTE_SEND_WINDOW - Jam kirim (misal 08:00-21:00)
+• PROMOTE_MAX_PER_MINUTE - Batas pesan per menit
+• PROMOTE_DAILY_LIMIT - Batas pesan per hari
+• PROMOTE_SPREAD_MINUTES - Rentang jitter (menit)
+• PROMOTE_MISSED_POLICY - once/skip/spread
+• PROMOTE_TEMPLATE_STRATEGY - random/roundrobin/leastrecent/norepeat
+• PROMOTE_INSTANCE_ID - Nama instance untuk lock scheduler
 • ENABLE_AUTO_PROMOTE - true/false
 • LOG_AUTO_PROMOTE - true/false`,
 		c.PromoteDatabasePath,
+		c.MediaDir,
 		len(c.AdminNumbers),
 		c.AutoPromoteInterval,
+		c.Timezone,
+		c.Locale,
+		getSendWindowText(c.SendWindow),
+		c.MaxMessagesPerMinute,
+		c.DailyMessageLimit,
+		c.SpreadMinutes,
+		c.MissedSlotPolicy,
 		c.MaxTemplatesPerCategory,
 		getBoolText(c.EnableAutoPromote),
 		getBoolText(c.LogAutoPromote),
@@ -210,11 +316,29 @@ func getBoolText(value bool) string {
 	return "Tidak Aktif ❌"
 }
 
+// getSendWindowText mengkonversi jam kirim ke teks
+func getSendWindowText(window string) string {
+	if window == "" {
+		return "24 jam"
+	}
+	return window
+}
+
 // UpdateConfig memperbarui konfigurasi dari environment variables
 func (c *PromoteConfig) UpdateConfig() {
 	c.PromoteDatabasePath = getEnvOrDefault("PROMOTE_DB_PATH", c.PromoteDatabasePath)
+	c.MediaDir = getEnvOrDefault("PROMOTE_MEDIA_DIR", c.MediaDir)
 	c.AdminNumbers = getAdminNumbers()
-	c.AutoPromoteInterval = getEnvIntOrDefault("AUTO_PROMOTE_INTERVAL", c.AutoPromoteInterval)
+	c.AutoPromoteInterval = getEnvDurationOrDefault("AUTO_PROMOTE_INTERVAL", c.AutoPromoteInterval)
+	c.Timezone = getEnvOrDefault("PROMOTE_TIMEZONE", c.Timezone)
+	c.Locale = getEnvOrDefault("PROMOTE_LOCALE", c.Locale)
+	c.SendWindow = getEnvOrDefault("PROMOTE_SEND_WINDOW", c.SendWindow)
+	c.MaxMessagesPerMinute = getEnvIntOrDefault("PROMOTE_MAX_PER_MINUTE", c.MaxMessagesPerMinute)
+	c.DailyMessageLimit = getEnvIntOrDefault("PROMOTE_DAILY_LIMIT", c.DailyMessageLimit)
+	c.SpreadMinutes = getEnvIntOrDefault("PROMOTE_SPREAD_MINUTES", c.SpreadMinutes)
+	c.MissedSlotPolicy = getEnvOrDefault("PROMOTE_MISSED_POLICY", c.MissedSlotPolicy)
+	c.TemplateStrategy = getEnvOrDefault("PROMOTE_TEMPLATE_STRATEGY", c.TemplateStrategy)
+	c.InstanceID = getEnvOrDefault("PROMOTE_INSTANCE_ID", c.InstanceID)
 	c.MaxTemplatesPerCategory = getEnvIntOrDefault("MAX_TEMPLATES_PER_CATEGORY", c.MaxTemplatesPerCategory)
 	c.EnableAutoPromote = getEnvBoolOrDefault("ENABLE_AUTO_PROMOTE", c.EnableAutoPromote)
 	c.LogAutoPromote = getEnvBoolOrDefault("LOG_AUTO_PROMOTE", c.LogAutoPromote)
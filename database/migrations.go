@@ -13,6 +13,18 @@ func RunMigrations(db *sql.DB) error {
 		createPromoteTemplatesTable,
 		createPromoteLogsTable,
 		createPromoteStatsTable,
+		createPromoteQueueTable,
+		createPromoteSettingsTable,
+		createCampaignsTable,
+		createScheduledJobsTable,
+		createPromoteLeasesTable,
+		createTemplateVariablesTable,
+		createGroupAttributesTable,
+		createTemplateRevisionsTable,
+		createGroupCategoriesTable,
+		createTemplateVariantsTable,
+		createLogEngagementTable,
+		createTemplateTranslationsTable,
 		// insertDefaultTemplates, // Dinonaktifkan - admin akan isi manual
 	}
 
@@ -24,11 +36,104 @@ func RunMigrations(db *sql.DB) error {
 		}
 	}
 
+	// Tambahkan kolom baru ke tabel yang sudah ada (database lama)
+	for _, column := range columnMigrations {
+		if err := addColumnIfNotExists(db, column.table, column.name, column.definition); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %v", column.table, column.name, err)
+		}
+	}
+
+	// Index untuk kolom tambahan baru bisa dibuat setelah kolomnya ada
+	for _, index := range columnIndexes {
+		if _, err := db.Exec(index); err != nil {
+			return fmt.Errorf("failed to create index: %v", err)
+		}
+	}
+
 	fmt.Println("✅ All migrations completed successfully!")
 	fmt.Println("💡 Template database ready - admin can add templates manually")
 	return nil
 }
 
+// columnMigration mendefinisikan kolom tambahan untuk tabel yang sudah ada
+type columnMigration struct {
+	table      string
+	name       string
+	definition string
+}
+
+// columnMigrations berisi kolom yang ditambahkan setelah tabel awal dibuat.
+// SQLite tidak mendukung ADD COLUMN IF NOT EXISTS, jadi dicek via PRAGMA table_info.
+var columnMigrations = []columnMigration{
+	{"auto_promote_groups", "schedule", "TEXT NOT NULL DEFAULT ''"},
+	{"auto_promote_groups", "next_promote_at", "DATETIME"},
+	{"auto_promote_groups", "send_window", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_logs", "campaign_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"promote_queue", "campaign_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"promote_templates", "weight", "INTEGER NOT NULL DEFAULT 1"},
+	{"auto_promote_groups", "template_strategy", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "ab_split", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_queue", "variant_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"promote_logs", "variant_id", "INTEGER NOT NULL DEFAULT 0"},
+	{"promote_logs", "message_id", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "media_type", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "media_path", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "media_mimetype", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "media_name", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "valid_from", "DATETIME"},
+	{"promote_templates", "valid_until", "DATETIME"},
+	{"promote_templates", "valid_days", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_templates", "valid_hours", "TEXT NOT NULL DEFAULT ''"},
+	{"auto_promote_groups", "locale", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "media_type", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "media_path", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "media_mimetype", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "media_name", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "valid_from", "DATETIME"},
+	{"promote_template_revisions", "valid_until", "DATETIME"},
+	{"promote_template_revisions", "valid_days", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "valid_hours", "TEXT NOT NULL DEFAULT ''"},
+	{"promote_template_revisions", "has_details", "BOOLEAN NOT NULL DEFAULT FALSE"},
+}
+
+// columnIndexes berisi index untuk kolom dari columnMigrations
+var columnIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_promote_logs_message ON promote_logs(message_id)`,
+}
+
+// addColumnIfNotExists menambahkan kolom jika belum ada di tabel
+func addColumnIfNotExists(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			columnType string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultVal, &primaryKey); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
 // SQL untuk membuat tabel auto_promote_groups
 const createAutoPromoteGroupsTable = `
 CREATE TABLE IF NOT EXISTS auto_promote_groups (
@@ -94,6 +199,185 @@ CREATE TABLE IF NOT EXISTS promote_stats (
 CREATE INDEX IF NOT EXISTS idx_promote_stats_date ON promote_stats(date);
 `
 
+// SQL untuk membuat tabel promote_queue (antrian kirim yang bertahan saat restart)
+const createPromoteQueueTable = `
+CREATE TABLE IF NOT EXISTS promote_queue (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_jid TEXT NOT NULL,
+    template_id INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT 'auto',
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    scheduled_at DATETIME NOT NULL,
+    sent_at DATETIME,
+    last_error TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_promote_queue_status ON promote_queue(status, scheduled_at);
+CREATE INDEX IF NOT EXISTS idx_promote_queue_group ON promote_queue(group_jid);
+CREATE INDEX IF NOT EXISTS idx_promote_queue_sent_at ON promote_queue(sent_at);
+`
+
+// SQL untuk membuat tabel promote_settings (pengaturan runtime yang diubah admin)
+const createPromoteSettingsTable = `
+CREATE TABLE IF NOT EXISTS promote_settings (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// SQL untuk membuat tabel campaigns dan grup targetnya
+const createCampaignsTable = `
+CREATE TABLE IF NOT EXISTS campaigns (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    start_at DATETIME NOT NULL,
+    end_at DATETIME NOT NULL,
+    template_ids TEXT NOT NULL DEFAULT '',
+    category TEXT NOT NULL DEFAULT '',
+    target_all BOOLEAN DEFAULT FALSE,
+    interval_minutes INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS campaign_groups (
+    campaign_id INTEGER NOT NULL,
+    group_jid TEXT NOT NULL,
+    PRIMARY KEY (campaign_id, group_jid),
+    FOREIGN KEY (campaign_id) REFERENCES campaigns(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_campaigns_status ON campaigns(status);
+`
+
+// SQL untuk membuat tabel scheduled_jobs (broadcast sekali jalan pada waktu tertentu)
+const createScheduledJobsTable = `
+CREATE TABLE IF NOT EXISTS scheduled_jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id INTEGER NOT NULL,
+    group_jids TEXT NOT NULL DEFAULT '',
+    target_all BOOLEAN DEFAULT FALSE,
+    run_at DATETIME NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    queued_count INTEGER NOT NULL DEFAULT 0,
+    executed_at DATETIME,
+    error_msg TEXT,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_jobs_status ON scheduled_jobs(status, run_at);
+`
+
+// SQL untuk membuat tabel promote_leases (lock leader agar hanya satu proses menjalankan scheduler)
+const createPromoteLeasesTable = `
+CREATE TABLE IF NOT EXISTS promote_leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    heartbeat_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+`
+
+// SQL untuk membuat tabel template_variables (variabel global seperti {STORE_WA})
+const createTemplateVariablesTable = `
+CREATE TABLE IF NOT EXISTS template_variables (
+    name TEXT PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// SQL untuk membuat tabel group_attributes (variabel template khusus per grup)
+const createGroupAttributesTable = `
+CREATE TABLE IF NOT EXISTS group_attributes (
+    group_jid TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_jid, name)
+);
+`
+
+// SQL untuk membuat tabel promote_template_revisions (riwayat perubahan template)
+const createTemplateRevisionsTable = `
+CREATE TABLE IF NOT EXISTS promote_template_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id INTEGER NOT NULL,
+    revision INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    content TEXT NOT NULL,
+    category TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    weight INTEGER NOT NULL DEFAULT 1,
+    action TEXT NOT NULL,
+    author TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (template_id, revision)
+);
+`
+
+// SQL untuk membuat tabel group_categories (kategori template yang diikuti grup)
+const createGroupCategoriesTable = `
+CREATE TABLE IF NOT EXISTS group_categories (
+    group_jid TEXT NOT NULL,
+    category TEXT NOT NULL,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_jid, category)
+);
+`
+
+// SQL untuk membuat tabel promote_template_variants (variasi konten untuk A/B test)
+const createTemplateVariantsTable = `
+CREATE TABLE IF NOT EXISTS promote_template_variants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    template_id INTEGER NOT NULL,
+    label TEXT NOT NULL,
+    content TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (template_id, label)
+);
+`
+
+// SQL untuk membuat tabel promote_log_engagement (read receipt dan balasan per pesan terkirim)
+const createLogEngagementTable = `
+CREATE TABLE IF NOT EXISTS promote_log_engagement (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    log_id INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    participant TEXT NOT NULL,
+    ref_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (log_id, kind, participant, ref_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_promote_log_engagement_log ON promote_log_engagement(log_id);
+`
+
+// SQL untuk membuat tabel promote_template_translations (konten template per bahasa)
+const createTemplateTranslationsTable = `
+CREATE TABLE IF NOT EXISTS promote_template_translations (
+    template_id INTEGER NOT NULL,
+    locale TEXT NOT NULL,
+    content TEXT NOT NULL,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (template_id, locale)
+);
+`
+
 // SQL untuk insert template default
 const insertDefaultTemplates = `
 INSERT OR IGNORE INTO promote_templates (title, content, category, is_active) VALUES
@@ -12,19 +12,110 @@ type AutoPromoteGroup struct {
 	IsActive      bool      `json:"is_active" db:"is_active"`           // Status aktif/tidak
 	StartedAt     *time.Time `json:"started_at" db:"started_at"`        // Waktu mulai auto promote
 	LastPromoteAt *time.Time `json:"last_promote_at" db:"last_promote_at"` // Waktu terakhir kirim promosi
+	Schedule      string     `json:"schedule" db:"schedule"`               // Ekspresi jadwal grup (kosong = interval default)
+	NextPromoteAt *time.Time `json:"next_promote_at" db:"next_promote_at"` // Jadwal promosi berikutnya (nil = dihitung dari promosi terakhir)
+	SendWindow    string     `json:"send_window" db:"send_window"`         // Jam kirim yang diizinkan (kosong = window global)
+	TemplateStrategy string  `json:"template_strategy" db:"template_strategy"` // Strategi pemilihan template (kosong = strategi global)
+	Locale        string     `json:"locale" db:"locale"`                   // Bahasa promosi grup: id, en, ms (kosong = bahasa default)
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`         // Waktu dibuat
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`         // Waktu diupdate
 }
 
 // PromoteTemplate menyimpan template promosi bisnis
 type PromoteTemplate struct {
-	ID        int       `json:"id" db:"id"`
-	Title     string    `json:"title" db:"title"`         // Judul template (misal: "Produk Unggulan")
-	Content   string    `json:"content" db:"content"`     // Isi template promosi
-	Category  string    `json:"category" db:"category"`   // Kategori (produk, diskon, testimoni, dll)
-	IsActive  bool      `json:"is_active" db:"is_active"` // Status aktif/tidak
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID            int        `json:"id" db:"id"`
+	Title         string     `json:"title" db:"title"`                   // Judul template (misal: "Produk Unggulan")
+	Content       string     `json:"content" db:"content"`               // Isi template promosi
+	Category      string     `json:"category" db:"category"`             // Kategori (produk, diskon, testimoni, dll)
+	IsActive      bool       `json:"is_active" db:"is_active"`           // Status aktif/tidak
+	Weight        int        `json:"weight" db:"weight"`                 // Bobot untuk strategi weighted random (minimal 1)
+	ABSplit       string     `json:"ab_split" db:"ab_split"`             // Pembagian varian A/B: per kiriman (kosong) atau per grup
+	MediaType     string     `json:"media_type" db:"media_type"`         // image, video, document (kosong = teks saja)
+	MediaPath     string     `json:"media_path" db:"media_path"`         // Nama file di folder media (data/media)
+	MediaMimetype string     `json:"media_mimetype" db:"media_mimetype"` // Misal image/jpeg
+	MediaName     string     `json:"media_name" db:"media_name"`         // Nama file asli, dipakai untuk dokumen
+	ValidFrom     *time.Time `json:"valid_from" db:"valid_from"`         // Mulai berlaku (nil = sejak dibuat)
+	ValidUntil    *time.Time `json:"valid_until" db:"valid_until"`       // Berakhir, lalu dinonaktifkan otomatis (nil = tanpa batas)
+	ValidDays     string     `json:"valid_days" db:"valid_days"`         // Hari tayang, misal "sen,sel,rab" (kosong = setiap hari)
+	ValidHours    string     `json:"valid_hours" db:"valid_hours"`       // Jam tayang format send window (kosong = 24 jam)
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Cara membagi varian A/B sebuah template
+const (
+	ABSplitSend  = "send"  // Varian dipilih acak setiap kiriman
+	ABSplitGroup = "group" // Setiap grup selalu mendapat varian yang sama
+)
+
+// Jenis media yang bisa dilampirkan ke template
+const (
+	MediaImage    = "image"
+	MediaVideo    = "video"
+	MediaDocument = "document"
+)
+
+// TemplateVariant adalah variasi konten template untuk A/B test.
+// Konten asli template selalu menjadi varian A (VariantID 0).
+type TemplateVariant struct {
+	ID         int       `json:"id" db:"id"`
+	TemplateID int       `json:"template_id" db:"template_id"`
+	Label      string    `json:"label" db:"label"`           // B, C, D, ...
+	Content    string    `json:"content" db:"content"`       // Konten varian (syntax sama dengan template)
+	IsActive   bool      `json:"is_active" db:"is_active"`   // Varian nonaktif tidak dikirim, tapi tetap muncul di laporan
+	CreatedBy  string    `json:"created_by" db:"created_by"` // Admin yang menambahkan
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TemplateTranslation adalah konten template dalam bahasa tertentu.
+// Grup dengan locale yang sama menerima terjemahan ini, grup lain menerima konten asli.
+type TemplateTranslation struct {
+	TemplateID int       `json:"template_id" db:"template_id"`
+	Locale     string    `json:"locale" db:"locale"`         // id, en, ms
+	Content    string    `json:"content" db:"content"`       // Konten terjemahan (syntax sama dengan template)
+	UpdatedBy  string    `json:"updated_by" db:"updated_by"` // Admin yang terakhir mengubah
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TranslatedVariantID adalah VariantID untuk kiriman terjemahan di log dan antrian.
+// Terjemahan tidak ikut A/B test sehingga tidak dihitung sebagai varian mana pun.
+const TranslatedVariantID = -1
+
+// Jenis perubahan pada riwayat template
+const (
+	RevisionBaseline = "baseline" // Kondisi template sebelum riwayat mulai dicatat
+	RevisionCreate   = "create"   // Template dibuat
+	RevisionEdit     = "edit"     // Judul/kategori/konten diubah
+	RevisionStatus   = "status"   // Diaktifkan/dinonaktifkan
+	RevisionWeight   = "weight"   // Bobot diubah
+	RevisionValidity = "validity" // Masa berlaku diubah
+	RevisionDelete   = "delete"   // Template dihapus (isi terakhir tetap tersimpan)
+	RevisionRollback = "rollback" // Dikembalikan ke revisi sebelumnya
+	RevisionRestore  = "restore"  // Template yang terhapus dipulihkan
+)
+
+// TemplateRevision menyimpan salinan template setelah setiap perubahan
+type TemplateRevision struct {
+	ID            int        `json:"id" db:"id"`
+	TemplateID    int        `json:"template_id" db:"template_id"` // ID template (tetap ada walau template dihapus)
+	Revision      int        `json:"revision" db:"revision"`       // Nomor revisi per template, mulai dari 1
+	Title         string     `json:"title" db:"title"`
+	Content       string     `json:"content" db:"content"`
+	Category      string     `json:"category" db:"category"`
+	IsActive      bool       `json:"is_active" db:"is_active"`
+	Weight        int        `json:"weight" db:"weight"`
+	MediaType     string     `json:"media_type" db:"media_type"`
+	MediaPath     string     `json:"media_path" db:"media_path"`
+	MediaMimetype string     `json:"media_mimetype" db:"media_mimetype"`
+	MediaName     string     `json:"media_name" db:"media_name"`
+	ValidFrom     *time.Time `json:"valid_from" db:"valid_from"`
+	ValidUntil    *time.Time `json:"valid_until" db:"valid_until"`
+	ValidDays     string     `json:"valid_days" db:"valid_days"`
+	ValidHours    string     `json:"valid_hours" db:"valid_hours"`
+	HasDetails    bool       `json:"has_details" db:"has_details"` // false untuk revisi lama yang belum menyimpan media dan masa berlaku
+	Action        string     `json:"action" db:"action"`           // Jenis perubahan (create, edit, delete, ...)
+	Author        string     `json:"author" db:"author"`           // Nomor admin yang mengubah
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
 }
 
 // PromoteLog menyimpan log pengiriman promosi untuk tracking
@@ -36,6 +127,25 @@ type PromoteLog struct {
 	SentAt     time.Time `json:"sent_at" db:"sent_at"`         // Waktu pengiriman
 	Success    bool      `json:"success" db:"success"`         // Status berhasil/gagal
 	ErrorMsg   *string   `json:"error_msg" db:"error_msg"`     // Pesan error jika gagal
+	CampaignID int       `json:"campaign_id" db:"campaign_id"` // ID campaign (0 = rotasi default)
+	VariantID  int       `json:"variant_id" db:"variant_id"`   // ID varian A/B (0 = konten asli template)
+	MessageID  string    `json:"message_id" db:"message_id"`   // ID pesan WhatsApp untuk melacak read receipt dan balasan
+}
+
+// Jenis interaksi terhadap pesan promosi
+const (
+	EngagementRead  = "read"  // Anggota grup membaca pesan
+	EngagementReply = "reply" // Anggota grup membalas (quote) pesan
+)
+
+// VariantStats adalah ringkasan kiriman dan interaksi satu varian template
+type VariantStats struct {
+	VariantID    int // 0 = konten asli template
+	Sent         int // Jumlah percobaan kirim yang tercatat
+	Delivered    int // Berhasil diterima server WhatsApp
+	ReadMessages int // Pesan yang dibaca minimal satu anggota
+	ReadReceipts int // Total read receipt dari semua anggota
+	Replies      int // Total balasan yang mengutip pesan
 }
 
 // PromoteStats menyimpan statistik promosi untuk monitoring
@@ -49,6 +159,131 @@ type PromoteStats struct {
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 }
 
+// Status item di antrian kirim
+const (
+	QueueStatusPending   = "pending"   // Menunggu dikirim
+	QueueStatusSent      = "sent"      // Berhasil dikirim
+	QueueStatusFailed    = "failed"    // Gagal setelah semua percobaan
+	QueueStatusCancelled = "cancelled" // Dibatalkan sebelum terkirim
+)
+
+// Sumber item di antrian kirim
+const (
+	QueueSourceAuto     = "auto"     // Promosi terjadwal
+	QueueSourceManual   = "manual"   // .testpromo dari grup
+	QueueSourceTest     = "test"     // .testgroup dari admin
+	QueueSourceCampaign = "campaign" // Promosi campaign
+	QueueSourceSchedule = "schedule" // Broadcast terjadwal (.schedule)
+)
+
+// QueuedMessage menyimpan pesan promosi di antrian kirim
+type QueuedMessage struct {
+	ID          int        `json:"id" db:"id"`
+	GroupJID    string     `json:"group_jid" db:"group_jid"`         // JID grup tujuan
+	TemplateID  int        `json:"template_id" db:"template_id"`     // ID template yang digunakan
+	Content     string     `json:"content" db:"content"`             // Isi pesan yang sudah diproses
+	Source      string     `json:"source" db:"source"`               // Asal pesan (auto, manual, test)
+	Status      string     `json:"status" db:"status"`               // pending, sent, failed, cancelled
+	Attempts    int        `json:"attempts" db:"attempts"`           // Jumlah percobaan kirim
+	MaxAttempts int        `json:"max_attempts" db:"max_attempts"`   // Batas percobaan kirim
+	ScheduledAt time.Time  `json:"scheduled_at" db:"scheduled_at"`   // Waktu paling cepat dikirim
+	SentAt      *time.Time `json:"sent_at" db:"sent_at"`             // Waktu berhasil dikirim
+	LastError   *string    `json:"last_error" db:"last_error"`       // Error percobaan terakhir
+	CampaignID  int        `json:"campaign_id" db:"campaign_id"`     // ID campaign (0 = bukan campaign)
+	VariantID   int        `json:"variant_id" db:"variant_id"`       // ID varian A/B (0 = konten asli template)
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Status campaign
+const (
+	CampaignStatusActive = "active" // Berjalan sesuai periode
+	CampaignStatusPaused = "paused" // Dijeda admin
+	CampaignStatusEnded  = "ended"  // Periode sudah selesai
+)
+
+// Campaign menyimpan promosi berbatas waktu (flash sale, Ramadan, dll)
+type Campaign struct {
+	ID              int       `json:"id" db:"id"`
+	Name            string    `json:"name" db:"name"`                         // Nama campaign
+	StartAt         time.Time `json:"start_at" db:"start_at"`                 // Waktu mulai
+	EndAt           time.Time `json:"end_at" db:"end_at"`                     // Waktu selesai
+	TemplateIDs     string    `json:"template_ids" db:"template_ids"`         // Daftar ID template (pisah koma)
+	Category        string    `json:"category" db:"category"`                 // Kategori template (jika tidak pakai ID)
+	TargetAll       bool      `json:"target_all" db:"target_all"`             // Target semua grup aktif
+	IntervalMinutes int       `json:"interval_minutes" db:"interval_minutes"` // Interval kirim campaign
+	Status          string    `json:"status" db:"status"`                     // active, paused, ended
+	GroupJIDs       []string  `json:"group_jids" db:"-"`                      // Grup target (dari campaign_groups)
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Status scheduled job
+const (
+	ScheduledJobPending   = "pending"   // Menunggu waktu kirim
+	ScheduledJobDone      = "done"      // Sudah dimasukkan ke antrian kirim
+	ScheduledJobCancelled = "cancelled" // Dibatalkan admin
+	ScheduledJobMissed    = "missed"    // Terlewat terlalu lama (bot mati)
+	ScheduledJobFailed    = "failed"    // Gagal dijalankan
+)
+
+// ScheduledJob menyimpan broadcast sekali jalan ke grup tertentu pada waktu tertentu
+type ScheduledJob struct {
+	ID          int        `json:"id" db:"id"`
+	TemplateID  int        `json:"template_id" db:"template_id"`   // Template yang dikirim
+	GroupJIDs   []string   `json:"group_jids" db:"group_jids"`     // Grup target (disimpan pisah koma)
+	TargetAll   bool       `json:"target_all" db:"target_all"`     // Target semua grup aktif saat dijalankan
+	RunAt       time.Time  `json:"run_at" db:"run_at"`             // Waktu kirim
+	Status      string     `json:"status" db:"status"`             // pending, done, cancelled, missed, failed
+	QueuedCount int        `json:"queued_count" db:"queued_count"` // Jumlah pesan yang masuk antrian
+	ExecutedAt  *time.Time `json:"executed_at" db:"executed_at"`   // Waktu job dijalankan
+	ErrorMsg    *string    `json:"error_msg" db:"error_msg"`       // Alasan gagal/terlewat
+	CreatedBy   string     `json:"created_by" db:"created_by"`     // Nomor admin pembuat
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// PromoteLease menyimpan lock leader antar proses yang memakai database yang sama
+type PromoteLease struct {
+	Name        string    `json:"name" db:"name"`                 // Nama lease (misal "scheduler")
+	Holder      string    `json:"holder" db:"holder"`             // ID instance pemegang lease
+	AcquiredAt  time.Time `json:"acquired_at" db:"acquired_at"`   // Kapan holder mulai memegang lease
+	HeartbeatAt time.Time `json:"heartbeat_at" db:"heartbeat_at"` // Heartbeat terakhir dari holder
+	ExpiresAt   time.Time `json:"expires_at" db:"expires_at"`     // Lease bisa diambil alih setelah waktu ini
+}
+
+// PromoteSetting menyimpan pengaturan runtime yang bertahan saat restart
+type PromoteSetting struct {
+	Key       string    `json:"key" db:"key"`     // Nama pengaturan
+	Value     string    `json:"value" db:"value"` // Nilai pengaturan
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateVariable adalah variabel global yang bisa dipakai di semua template, misal {STORE_WA}
+type TemplateVariable struct {
+	Name      string    `json:"name" db:"name"`             // Nama variabel (huruf besar)
+	Value     string    `json:"value" db:"value"`           // Nilai pengganti saat template dirender
+	UpdatedBy string    `json:"updated_by" db:"updated_by"` // Admin yang terakhir mengubah
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupAttribute adalah variabel template khusus satu grup, misal nomor reseller lokal.
+// Nilainya menimpa variabel global dengan nama yang sama saat dikirim ke grup tersebut.
+type GroupAttribute struct {
+	GroupJID  string    `json:"group_jid" db:"group_jid"`
+	Name      string    `json:"name" db:"name"`
+	Value     string    `json:"value" db:"value"`
+	UpdatedBy string    `json:"updated_by" db:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GroupCategory adalah kategori template yang diikuti grup. Grup tanpa kategori
+// menerima template dari semua kategori.
+type GroupCategory struct {
+	GroupJID  string    `json:"group_jid" db:"group_jid"`
+	Category  string    `json:"category" db:"category"`     // Sama dengan PromoteTemplate.Category (huruf kecil)
+	CreatedBy string    `json:"created_by" db:"created_by"` // Admin yang menambahkan
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
 // DefaultPromoteTemplates berisi template default untuk promosi bisnis
 var DefaultPromoteTemplates = []PromoteTemplate{
 	{
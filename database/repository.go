@@ -4,6 +4,7 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -22,14 +23,94 @@ type Repository interface {
 	CreateTemplate(template *PromoteTemplate) error
 	UpdateTemplate(template *PromoteTemplate) error
 	DeleteTemplate(id int) error
+	RestoreTemplate(template *PromoteTemplate) error
+	SetTemplateABSplit(id int, split string) error
+	SetTemplateMedia(id int, mediaType, path, mimetype, name string) (bool, error)
+	SetTemplateValidity(id int, from, until *time.Time, days, hours string) (bool, error)
+	
+	// Template Revisions
+	CreateTemplateRevision(revision *TemplateRevision) error
+	GetTemplateRevisions(templateID int) ([]TemplateRevision, error)
+	GetTemplateRevision(templateID, revision int) (*TemplateRevision, error)
+	
+	// Template Variants (A/B test)
+	CreateTemplateVariant(variant *TemplateVariant) error
+	GetTemplateVariants(templateID int) ([]TemplateVariant, error)
+	SetTemplateVariantActive(templateID int, label string, active bool) (bool, error)
+	
+	// Template Translations
+	GetTemplateTranslations(templateID int) ([]TemplateTranslation, error)
+	GetTemplateTranslation(templateID int, locale string) (*TemplateTranslation, error)
+	SetTemplateTranslation(translation *TemplateTranslation) error
+	DeleteTemplateTranslation(templateID int, locale string) (bool, error)
 	
 	// Promote Logs
 	CreateLog(log *PromoteLog) error
 	GetLogsByGroup(groupJID string, limit int) ([]PromoteLog, error)
+	GetLogsByCampaign(campaignID int) ([]PromoteLog, error)
+	GetLogByMessageID(messageID string) (*PromoteLog, error)
+	GetRecentTemplateIDs(groupJID string, limit int) ([]int, error)
+	GetTemplateLastSent(groupJID string) (map[int]time.Time, error)
+	
+	// Engagement (read receipt dan balasan)
+	RecordEngagement(logID int, kind, participant, refID string) (bool, error)
+	GetVariantStats(templateID int) ([]VariantStats, error)
 	
 	// Stats
 	UpdateStats(date string, totalGroups, totalMessages, successMessages, failedMessages int) error
+	IncrementStats(date string, success bool) error
 	GetStats(date string) (*PromoteStats, error)
+	
+	// Send Queue
+	EnqueueMessage(msg *QueuedMessage) error
+	GetDueQueuedMessages(now time.Time, limit int) ([]QueuedMessage, error)
+	UpdateQueuedMessage(msg *QueuedMessage) error
+	HasPendingQueuedMessage(groupJID string) (bool, error)
+	CancelQueuedMessages(groupJID string) (int, error)
+	CountQueuedMessages(status string) (int, error)
+	CountSentMessagesSince(since time.Time) (int, error)
+	MarkGroupPromoted(groupJID string, at time.Time) error
+	
+	// Settings
+	GetSetting(key string) (*PromoteSetting, error)
+	SetSetting(key, value string) error
+	DeleteSetting(key string) error
+	
+	// Template Variables
+	GetTemplateVariables() ([]TemplateVariable, error)
+	SetTemplateVariable(name, value, updatedBy string) error
+	DeleteTemplateVariable(name string) (bool, error)
+	
+	// Group Attributes
+	GetGroupAttributes(groupJID string) ([]GroupAttribute, error)
+	GetAllGroupAttributes() ([]GroupAttribute, error)
+	SetGroupAttribute(groupJID, name, value, updatedBy string) error
+	DeleteGroupAttribute(groupJID, name string) (bool, error)
+	
+	// Group Categories
+	GetGroupCategories(groupJID string) ([]GroupCategory, error)
+	GetAllGroupCategories() ([]GroupCategory, error)
+	SetGroupCategories(groupJID string, categories []string, createdBy string) error
+	
+	// Campaigns
+	CreateCampaign(campaign *Campaign) error
+	GetCampaignByID(id int) (*Campaign, error)
+	GetAllCampaigns() ([]Campaign, error)
+	UpdateCampaignStatus(id int, status string) error
+	GetLastCampaignEnqueue(campaignID int, groupJID string) (*time.Time, error)
+	CancelCampaignQueuedMessages(campaignID int) (int, error)
+	
+	// Scheduled Jobs
+	CreateScheduledJob(job *ScheduledJob) error
+	GetScheduledJobByID(id int) (*ScheduledJob, error)
+	GetScheduledJobs(includeFinished bool) ([]ScheduledJob, error)
+	GetDueScheduledJobs(now time.Time) ([]ScheduledJob, error)
+	UpdateScheduledJob(job *ScheduledJob) error
+	
+	// Leases
+	AcquireLease(name, holder string, now time.Time, ttl time.Duration) (bool, error)
+	ReleaseLease(name, holder string) error
+	GetLease(name string) (*PromoteLease, error)
 }
 
 // SQLiteRepository implementasi repository untuk SQLite
@@ -44,22 +125,23 @@ func NewSQLiteRepository(db *sql.DB) Repository {
 
 // === AUTO PROMOTE GROUPS ===
 
-func (r *SQLiteRepository) GetAutoPromoteGroup(groupJID string) (*AutoPromoteGroup, error) {
-	query := `SELECT id, group_jid, is_active, started_at, last_promote_at, created_at, updated_at 
-			  FROM auto_promote_groups WHERE group_jid = ?`
-	
-	row := r.db.QueryRow(query, groupJID)
-	
+// groupColumns adalah kolom yang dibaca untuk setiap AutoPromoteGroup
+const groupColumns = `id, group_jid, is_active, started_at, last_promote_at, schedule, next_promote_at, send_window, template_strategy, locale, created_at, updated_at`
+
+// rowScanner diimplementasikan oleh *sql.Row dan *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanGroup membaca satu baris auto_promote_groups
+func scanGroup(row rowScanner) (*AutoPromoteGroup, error) {
 	var group AutoPromoteGroup
-	var startedAt, lastPromoteAt sql.NullTime
+	var startedAt, lastPromoteAt, nextPromoteAt sql.NullTime
 	
 	err := row.Scan(&group.ID, &group.GroupJID, &group.IsActive, 
-		&startedAt, &lastPromoteAt, &group.CreatedAt, &group.UpdatedAt)
-	
+		&startedAt, &lastPromoteAt, &group.Schedule, &nextPromoteAt, &group.SendWindow,
+		&group.TemplateStrategy, &group.Locale, &group.CreatedAt, &group.UpdatedAt)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Group tidak ditemukan
-		}
 		return nil, err
 	}
 	
@@ -69,10 +151,27 @@ func (r *SQLiteRepository) GetAutoPromoteGroup(groupJID string) (*AutoPromoteGro
 	if lastPromoteAt.Valid {
 		group.LastPromoteAt = &lastPromoteAt.Time
 	}
+	if nextPromoteAt.Valid {
+		group.NextPromoteAt = &nextPromoteAt.Time
+	}
 	
 	return &group, nil
 }
 
+func (r *SQLiteRepository) GetAutoPromoteGroup(groupJID string) (*AutoPromoteGroup, error) {
+	query := `SELECT ` + groupColumns + ` FROM auto_promote_groups WHERE group_jid = ?`
+	
+	group, err := scanGroup(r.db.QueryRow(query, groupJID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Group tidak ditemukan
+		}
+		return nil, err
+	}
+	
+	return group, nil
+}
+
 func (r *SQLiteRepository) CreateAutoPromoteGroup(groupJID string) (*AutoPromoteGroup, error) {
 	query := `INSERT INTO auto_promote_groups (group_jid, is_active, created_at, updated_at) 
 			  VALUES (?, ?, ?, ?)`
@@ -99,20 +198,19 @@ func (r *SQLiteRepository) CreateAutoPromoteGroup(groupJID string) (*AutoPromote
 
 func (r *SQLiteRepository) UpdateAutoPromoteGroup(group *AutoPromoteGroup) error {
 	query := `UPDATE auto_promote_groups 
-			  SET is_active = ?, started_at = ?, last_promote_at = ?, updated_at = ? 
+			  SET is_active = ?, started_at = ?, last_promote_at = ?, schedule = ?, next_promote_at = ?, send_window = ?, template_strategy = ?, locale = ?, updated_at = ? 
 			  WHERE id = ?`
 	
 	group.UpdatedAt = time.Now()
 	
 	_, err := r.db.Exec(query, group.IsActive, group.StartedAt, 
-		group.LastPromoteAt, group.UpdatedAt, group.ID)
+		group.LastPromoteAt, group.Schedule, group.NextPromoteAt, group.SendWindow, group.TemplateStrategy, group.Locale, group.UpdatedAt, group.ID)
 	
 	return err
 }
 
 func (r *SQLiteRepository) GetActiveGroups() ([]AutoPromoteGroup, error) {
-	query := `SELECT id, group_jid, is_active, started_at, last_promote_at, created_at, updated_at 
-			  FROM auto_promote_groups WHERE is_active = true`
+	query := `SELECT ` + groupColumns + ` FROM auto_promote_groups WHERE is_active = true`
 	
 	rows, err := r.db.Query(query)
 	if err != nil {
@@ -123,23 +221,12 @@ func (r *SQLiteRepository) GetActiveGroups() ([]AutoPromoteGroup, error) {
 	var groups []AutoPromoteGroup
 	
 	for rows.Next() {
-		var group AutoPromoteGroup
-		var startedAt, lastPromoteAt sql.NullTime
-		
-		err := rows.Scan(&group.ID, &group.GroupJID, &group.IsActive,
-			&startedAt, &lastPromoteAt, &group.CreatedAt, &group.UpdatedAt)
+		group, err := scanGroup(rows)
 		if err != nil {
 			return nil, err
 		}
 		
-		if startedAt.Valid {
-			group.StartedAt = &startedAt.Time
-		}
-		if lastPromoteAt.Valid {
-			group.LastPromoteAt = &lastPromoteAt.Time
-		}
-		
-		groups = append(groups, group)
+		groups = append(groups, *group)
 	}
 	
 	return groups, nil
@@ -147,15 +234,44 @@ func (r *SQLiteRepository) GetActiveGroups() ([]AutoPromoteGroup, error) {
 
 // === PROMOTE TEMPLATES ===
 
+// templateColumns adalah kolom yang dibaca untuk setiap PromoteTemplate
+const templateColumns = `id, title, content, category, is_active, weight, ab_split, 
+	media_type, media_path, media_mimetype, media_name, 
+	valid_from, valid_until, valid_days, valid_hours, created_at, updated_at`
+
+// scanTemplate membaca satu baris promote_templates
+func scanTemplate(row rowScanner) (*PromoteTemplate, error) {
+	var template PromoteTemplate
+	var validFrom, validUntil sql.NullTime
+	
+	err := row.Scan(&template.ID, &template.Title, &template.Content,
+		&template.Category, &template.IsActive, &template.Weight, &template.ABSplit,
+		&template.MediaType, &template.MediaPath, &template.MediaMimetype, &template.MediaName,
+		&validFrom, &validUntil, &template.ValidDays, &template.ValidHours,
+		&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	
+	if validFrom.Valid {
+		template.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		template.ValidUntil = &validUntil.Time
+	}
+	
+	return &template, nil
+}
+
 func (r *SQLiteRepository) GetAllTemplates() ([]PromoteTemplate, error) {
-	query := `SELECT id, title, content, category, is_active, created_at, updated_at 
+	query := `SELECT ` + templateColumns + ` 
 			  FROM promote_templates ORDER BY created_at DESC`
 	
 	return r.queryTemplates(query)
 }
 
 func (r *SQLiteRepository) GetActiveTemplates() ([]PromoteTemplate, error) {
-	query := `SELECT id, title, content, category, is_active, created_at, updated_at 
+	query := `SELECT ` + templateColumns + ` 
 			  FROM promote_templates WHERE is_active = true ORDER BY created_at DESC`
 	
 	return r.queryTemplates(query)
@@ -171,28 +287,21 @@ func (r *SQLiteRepository) queryTemplates(query string, args ...interface{}) ([]
 	var templates []PromoteTemplate
 	
 	for rows.Next() {
-		var template PromoteTemplate
-		err := rows.Scan(&template.ID, &template.Title, &template.Content,
-			&template.Category, &template.IsActive, &template.CreatedAt, &template.UpdatedAt)
+		template, err := scanTemplate(rows)
 		if err != nil {
 			return nil, err
 		}
-		templates = append(templates, template)
+		templates = append(templates, *template)
 	}
 	
 	return templates, nil
 }
 
 func (r *SQLiteRepository) GetTemplateByID(id int) (*PromoteTemplate, error) {
-	query := `SELECT id, title, content, category, is_active, created_at, updated_at 
+	query := `SELECT ` + templateColumns + ` 
 			  FROM promote_templates WHERE id = ?`
 	
-	row := r.db.QueryRow(query, id)
-	
-	var template PromoteTemplate
-	err := row.Scan(&template.ID, &template.Title, &template.Content,
-		&template.Category, &template.IsActive, &template.CreatedAt, &template.UpdatedAt)
-	
+	template, err := scanTemplate(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -200,19 +309,25 @@ func (r *SQLiteRepository) GetTemplateByID(id int) (*PromoteTemplate, error) {
 		return nil, err
 	}
 	
-	return &template, nil
+	return template, nil
 }
 
 func (r *SQLiteRepository) CreateTemplate(template *PromoteTemplate) error {
-	query := `INSERT INTO promote_templates (title, content, category, is_active, created_at, updated_at) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO promote_templates (title, content, category, is_active, weight, 
+			  valid_from, valid_until, valid_days, valid_hours, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	now := time.Now()
 	template.CreatedAt = now
 	template.UpdatedAt = now
+	if template.Weight < 1 {
+		template.Weight = 1
+	}
 	
 	result, err := r.db.Exec(query, template.Title, template.Content, 
-		template.Category, template.IsActive, template.CreatedAt, template.UpdatedAt)
+		template.Category, template.IsActive, template.Weight,
+		template.ValidFrom, template.ValidUntil, template.ValidDays, template.ValidHours,
+		template.CreatedAt, template.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -226,33 +341,377 @@ func (r *SQLiteRepository) CreateTemplate(template *PromoteTemplate) error {
 	return nil
 }
 
+// UpdateTemplate menyimpan isi, status, bobot dan masa berlaku template.
+// Media tidak ikut diubah karena filenya dikelola lewat SetTemplateMedia.
 func (r *SQLiteRepository) UpdateTemplate(template *PromoteTemplate) error {
 	query := `UPDATE promote_templates 
-			  SET title = ?, content = ?, category = ?, is_active = ?, updated_at = ? 
+			  SET title = ?, content = ?, category = ?, is_active = ?, weight = ?, 
+			      valid_from = ?, valid_until = ?, valid_days = ?, valid_hours = ?, updated_at = ? 
 			  WHERE id = ?`
 	
 	template.UpdatedAt = time.Now()
+	if template.Weight < 1 {
+		template.Weight = 1
+	}
 	
 	_, err := r.db.Exec(query, template.Title, template.Content, 
-		template.Category, template.IsActive, template.UpdatedAt, template.ID)
+		template.Category, template.IsActive, template.Weight,
+		template.ValidFrom, template.ValidUntil, template.ValidDays, template.ValidHours,
+		template.UpdatedAt, template.ID)
 	
 	return err
 }
 
+// DeleteTemplate menghapus template beserta varian dan terjemahannya, lalu membatalkan
+// antrian kirim template tersebut yang belum terkirim. Revisi dan log tidak dihapus.
 func (r *SQLiteRepository) DeleteTemplate(id int) error {
-	query := `DELETE FROM promote_templates WHERE id = ?`
-	_, err := r.db.Exec(query, id)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	queries := []string{
+		`DELETE FROM promote_template_variants WHERE template_id = ?`,
+		`DELETE FROM promote_template_translations WHERE template_id = ?`,
+		`DELETE FROM promote_templates WHERE id = ?`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query, id); err != nil {
+			return err
+		}
+	}
+	
+	_, err = tx.Exec(`UPDATE promote_queue SET status = ?, last_error = ? WHERE template_id = ? AND status = ?`,
+		QueueStatusCancelled, "template sudah dihapus", id, QueueStatusPending)
+	if err != nil {
+		return err
+	}
+	
+	return tx.Commit()
+}
+
+// RestoreTemplate memasukkan kembali template yang terhapus dengan ID aslinya,
+// termasuk media dan masa berlakunya
+func (r *SQLiteRepository) RestoreTemplate(template *PromoteTemplate) error {
+	query := `INSERT INTO promote_templates (id, title, content, category, is_active, weight, 
+			  media_type, media_path, media_mimetype, media_name, 
+			  valid_from, valid_until, valid_days, valid_hours, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	
+	template.UpdatedAt = time.Now()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = template.UpdatedAt
+	}
+	if template.Weight < 1 {
+		template.Weight = 1
+	}
+	
+	_, err := r.db.Exec(query, template.ID, template.Title, template.Content, 
+		template.Category, template.IsActive, template.Weight,
+		template.MediaType, template.MediaPath, template.MediaMimetype, template.MediaName,
+		template.ValidFrom, template.ValidUntil, template.ValidDays, template.ValidHours,
+		template.CreatedAt, template.UpdatedAt)
+	return err
+}
+
+// SetTemplateABSplit mengatur cara pembagian varian A/B template
+func (r *SQLiteRepository) SetTemplateABSplit(id int, split string) error {
+	_, err := r.db.Exec(`UPDATE promote_templates SET ab_split = ?, updated_at = ? WHERE id = ?`, split, time.Now(), id)
+	return err
+}
+
+// SetTemplateMedia mengatur media template (mediaType kosong = tanpa media)
+func (r *SQLiteRepository) SetTemplateMedia(id int, mediaType, path, mimetype, name string) (bool, error) {
+	query := `UPDATE promote_templates 
+			  SET media_type = ?, media_path = ?, media_mimetype = ?, media_name = ?, updated_at = ? 
+			  WHERE id = ?`
+	
+	result, err := r.db.Exec(query, mediaType, path, mimetype, name, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// SetTemplateValidity mengatur masa berlaku template (nil/kosong = tanpa batasan)
+func (r *SQLiteRepository) SetTemplateValidity(id int, from, until *time.Time, days, hours string) (bool, error) {
+	query := `UPDATE promote_templates 
+			  SET valid_from = ?, valid_until = ?, valid_days = ?, valid_hours = ?, updated_at = ? 
+			  WHERE id = ?`
+	
+	result, err := r.db.Exec(query, from, until, days, hours, time.Now(), id)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// === TEMPLATE REVISIONS ===
+
+// revisionColumns adalah kolom yang dibaca untuk setiap TemplateRevision
+const revisionColumns = `id, template_id, revision, title, content, category, is_active, weight, 
+	media_type, media_path, media_mimetype, media_name, 
+	valid_from, valid_until, valid_days, valid_hours, has_details, action, author, created_at`
+
+// scanRevision membaca satu baris promote_template_revisions
+func scanRevision(row rowScanner) (*TemplateRevision, error) {
+	var rev TemplateRevision
+	var validFrom, validUntil sql.NullTime
+	
+	err := row.Scan(&rev.ID, &rev.TemplateID, &rev.Revision, &rev.Title, &rev.Content,
+		&rev.Category, &rev.IsActive, &rev.Weight,
+		&rev.MediaType, &rev.MediaPath, &rev.MediaMimetype, &rev.MediaName,
+		&validFrom, &validUntil, &rev.ValidDays, &rev.ValidHours, &rev.HasDetails,
+		&rev.Action, &rev.Author, &rev.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	
+	if validFrom.Valid {
+		rev.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		rev.ValidUntil = &validUntil.Time
+	}
+	
+	return &rev, nil
+}
+
+// CreateTemplateRevision menyimpan revisi baru dengan nomor revisi berikutnya untuk template tersebut
+func (r *SQLiteRepository) CreateTemplateRevision(revision *TemplateRevision) error {
+	query := `INSERT INTO promote_template_revisions 
+			  (template_id, revision, title, content, category, is_active, weight, 
+			   media_type, media_path, media_mimetype, media_name, 
+			   valid_from, valid_until, valid_days, valid_hours, has_details, action, author, created_at) 
+			  SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, TRUE, ?, ?, ? 
+			  FROM promote_template_revisions WHERE template_id = ?`
+	
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	revision.HasDetails = true
+	
+	result, err := r.db.Exec(query, revision.TemplateID, revision.Title, revision.Content, revision.Category,
+		revision.IsActive, revision.Weight,
+		revision.MediaType, revision.MediaPath, revision.MediaMimetype, revision.MediaName,
+		revision.ValidFrom, revision.ValidUntil, revision.ValidDays, revision.ValidHours,
+		revision.Action, revision.Author, revision.CreatedAt, revision.TemplateID)
+	if err != nil {
+		return err
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	revision.ID = int(id)
+	
+	return r.db.QueryRow(`SELECT revision FROM promote_template_revisions WHERE id = ?`, id).Scan(&revision.Revision)
+}
+
+func (r *SQLiteRepository) GetTemplateRevisions(templateID int) ([]TemplateRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM promote_template_revisions 
+			  WHERE template_id = ? ORDER BY revision DESC`
+	
+	rows, err := r.db.Query(query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var revisions []TemplateRevision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *rev)
+	}
+	
+	return revisions, rows.Err()
+}
+
+func (r *SQLiteRepository) GetTemplateRevision(templateID, revision int) (*TemplateRevision, error) {
+	query := `SELECT ` + revisionColumns + ` FROM promote_template_revisions 
+			  WHERE template_id = ? AND revision = ?`
+	
+	rev, err := scanRevision(r.db.QueryRow(query, templateID, revision))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return rev, nil
+}
+
+// === TEMPLATE VARIANTS ===
+
+// CreateTemplateVariant menyimpan varian baru dengan label berikutnya (B, C, ...).
+// Label varian yang sudah dinonaktifkan tidak dipakai ulang agar laporan tidak tercampur.
+func (r *SQLiteRepository) CreateTemplateVariant(variant *TemplateVariant) error {
+	var count int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM promote_template_variants WHERE template_id = ?`, variant.TemplateID).Scan(&count); err != nil {
+		return err
+	}
+	
+	variant.Label = string(rune('B' + count))
+	variant.IsActive = true
+	variant.CreatedAt = time.Now()
+	
+	query := `INSERT INTO promote_template_variants (template_id, label, content, is_active, created_by, created_at) 
+			  VALUES (?, ?, ?, ?, ?, ?)`
+	
+	result, err := r.db.Exec(query, variant.TemplateID, variant.Label, variant.Content,
+		variant.IsActive, variant.CreatedBy, variant.CreatedAt)
+	if err != nil {
+		return err
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	
+	variant.ID = int(id)
+	return nil
+}
+
+// GetTemplateVariants mengambil semua varian template (termasuk yang nonaktif) urut label
+func (r *SQLiteRepository) GetTemplateVariants(templateID int) ([]TemplateVariant, error) {
+	query := `SELECT id, template_id, label, content, is_active, created_by, created_at 
+			  FROM promote_template_variants WHERE template_id = ? ORDER BY label`
+	
+	rows, err := r.db.Query(query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var variants []TemplateVariant
+	for rows.Next() {
+		var variant TemplateVariant
+		err := rows.Scan(&variant.ID, &variant.TemplateID, &variant.Label, &variant.Content,
+			&variant.IsActive, &variant.CreatedBy, &variant.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		variants = append(variants, variant)
+	}
+	
+	return variants, rows.Err()
+}
+
+// SetTemplateVariantActive mengaktifkan atau menonaktifkan satu varian template
+func (r *SQLiteRepository) SetTemplateVariantActive(templateID int, label string, active bool) (bool, error) {
+	result, err := r.db.Exec(`UPDATE promote_template_variants SET is_active = ? WHERE template_id = ? AND label = ?`,
+		active, templateID, label)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// === TEMPLATE TRANSLATIONS ===
+
+// translationColumns adalah kolom yang dibaca untuk setiap TemplateTranslation
+const translationColumns = `template_id, locale, content, updated_by, updated_at`
+
+// GetTemplateTranslations mengambil semua terjemahan template urut locale
+func (r *SQLiteRepository) GetTemplateTranslations(templateID int) ([]TemplateTranslation, error) {
+	query := `SELECT ` + translationColumns + ` FROM promote_template_translations WHERE template_id = ? ORDER BY locale`
+	
+	rows, err := r.db.Query(query, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var translations []TemplateTranslation
+	for rows.Next() {
+		var translation TemplateTranslation
+		err := rows.Scan(&translation.TemplateID, &translation.Locale, &translation.Content,
+			&translation.UpdatedBy, &translation.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	
+	return translations, rows.Err()
+}
+
+// GetTemplateTranslation mengambil terjemahan template untuk satu locale (nil jika belum ada)
+func (r *SQLiteRepository) GetTemplateTranslation(templateID int, locale string) (*TemplateTranslation, error) {
+	query := `SELECT ` + translationColumns + ` FROM promote_template_translations WHERE template_id = ? AND locale = ?`
+	
+	var translation TemplateTranslation
+	err := r.db.QueryRow(query, templateID, locale).Scan(&translation.TemplateID, &translation.Locale,
+		&translation.Content, &translation.UpdatedBy, &translation.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	return &translation, nil
+}
+
+// SetTemplateTranslation membuat atau mengganti terjemahan template
+func (r *SQLiteRepository) SetTemplateTranslation(translation *TemplateTranslation) error {
+	query := `INSERT INTO promote_template_translations (template_id, locale, content, updated_by, updated_at) 
+			  VALUES (?, ?, ?, ?, ?) 
+			  ON CONFLICT(template_id, locale) DO UPDATE SET content = excluded.content, 
+			  updated_by = excluded.updated_by, updated_at = excluded.updated_at`
+	
+	translation.UpdatedAt = time.Now()
+	
+	_, err := r.db.Exec(query, translation.TemplateID, translation.Locale, translation.Content,
+		translation.UpdatedBy, translation.UpdatedAt)
 	return err
 }
 
+// DeleteTemplateTranslation menghapus terjemahan template untuk satu locale
+func (r *SQLiteRepository) DeleteTemplateTranslation(templateID int, locale string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM promote_template_translations WHERE template_id = ? AND locale = ?`,
+		templateID, locale)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
 // === PROMOTE LOGS ===
 
 func (r *SQLiteRepository) CreateLog(log *PromoteLog) error {
-	query := `INSERT INTO promote_logs (group_jid, template_id, content, sent_at, success, error_msg) 
-			  VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO promote_logs (group_jid, template_id, content, sent_at, success, error_msg, campaign_id, variant_id, message_id) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
 	
 	result, err := r.db.Exec(query, log.GroupJID, log.TemplateID, 
-		log.Content, log.SentAt, log.Success, log.ErrorMsg)
+		log.Content, log.SentAt, log.Success, log.ErrorMsg, log.CampaignID, log.VariantID, log.MessageID)
 	if err != nil {
 		return err
 	}
@@ -266,8 +725,11 @@ func (r *SQLiteRepository) CreateLog(log *PromoteLog) error {
 	return nil
 }
 
+// logColumns adalah kolom yang dibaca untuk setiap PromoteLog
+const logColumns = `id, group_jid, template_id, content, sent_at, success, error_msg, campaign_id, variant_id, message_id`
+
 func (r *SQLiteRepository) GetLogsByGroup(groupJID string, limit int) ([]PromoteLog, error) {
-	query := `SELECT id, group_jid, template_id, content, sent_at, success, error_msg 
+	query := `SELECT ` + logColumns + ` 
 			  FROM promote_logs WHERE group_jid = ? 
 			  ORDER BY sent_at DESC LIMIT ?`
 	
@@ -277,6 +739,43 @@ func (r *SQLiteRepository) GetLogsByGroup(groupJID string, limit int) ([]Promote
 	}
 	defer rows.Close()
 	
+	return scanLogs(rows)
+}
+
+func (r *SQLiteRepository) GetLogsByCampaign(campaignID int) ([]PromoteLog, error) {
+	query := `SELECT ` + logColumns + ` 
+			  FROM promote_logs WHERE campaign_id = ? 
+			  ORDER BY sent_at ASC`
+	
+	rows, err := r.db.Query(query, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	return scanLogs(rows)
+}
+
+// GetLogByMessageID mencari log pengiriman berdasarkan ID pesan WhatsApp
+func (r *SQLiteRepository) GetLogByMessageID(messageID string) (*PromoteLog, error) {
+	query := `SELECT ` + logColumns + ` FROM promote_logs WHERE message_id = ? LIMIT 1`
+	
+	rows, err := r.db.Query(query, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	logs, err := scanLogs(rows)
+	if err != nil || len(logs) == 0 {
+		return nil, err
+	}
+	
+	return &logs[0], nil
+}
+
+// scanLogs membaca semua baris promote_logs
+func scanLogs(rows *sql.Rows) ([]PromoteLog, error) {
 	var logs []PromoteLog
 	
 	for rows.Next() {
@@ -284,7 +783,7 @@ func (r *SQLiteRepository) GetLogsByGroup(groupJID string, limit int) ([]Promote
 		var errorMsg sql.NullString
 		
 		err := rows.Scan(&log.ID, &log.GroupJID, &log.TemplateID,
-			&log.Content, &log.SentAt, &log.Success, &errorMsg)
+			&log.Content, &log.SentAt, &log.Success, &errorMsg, &log.CampaignID, &log.VariantID, &log.MessageID)
 		if err != nil {
 			return nil, err
 		}
@@ -299,9 +798,120 @@ func (r *SQLiteRepository) GetLogsByGroup(groupJID string, limit int) ([]Promote
 	return logs, nil
 }
 
-// === STATS ===
-
-func (r *SQLiteRepository) UpdateStats(date string, totalGroups, totalMessages, successMessages, failedMessages int) error {
+// GetRecentTemplateIDs mengambil ID template yang terakhir berhasil dikirim ke grup (terbaru dulu)
+func (r *SQLiteRepository) GetRecentTemplateIDs(groupJID string, limit int) ([]int, error) {
+	query := `SELECT template_id FROM promote_logs 
+			  WHERE group_jid = ? AND success = true 
+			  ORDER BY sent_at DESC, id DESC LIMIT ?`
+	
+	rows, err := r.db.Query(query, groupJID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	
+	return ids, nil
+}
+
+// GetTemplateLastSent mengambil waktu terakhir setiap template berhasil dikirim ke grup
+func (r *SQLiteRepository) GetTemplateLastSent(groupJID string) (map[int]time.Time, error) {
+	query := `SELECT template_id, MAX(sent_at) FROM promote_logs 
+			  WHERE group_jid = ? AND success = true 
+			  GROUP BY template_id`
+	
+	rows, err := r.db.Query(query, groupJID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	lastSent := make(map[int]time.Time)
+	for rows.Next() {
+		var id int
+		var sentAt string
+		if err := rows.Scan(&id, &sentAt); err != nil {
+			return nil, err
+		}
+		
+		// MAX() mengembalikan teks, bukan DATETIME, sehingga perlu diparse manual
+		t, err := parseSQLiteTime(sentAt)
+		if err != nil {
+			return nil, err
+		}
+		lastSent[id] = t
+	}
+	
+	return lastSent, nil
+}
+
+// === ENGAGEMENT ===
+
+// RecordEngagement mencatat read receipt atau balasan untuk pesan promosi.
+// Mengembalikan false jika interaksi yang sama sudah pernah dicatat.
+func (r *SQLiteRepository) RecordEngagement(logID int, kind, participant, refID string) (bool, error) {
+	query := `INSERT OR IGNORE INTO promote_log_engagement (log_id, kind, participant, ref_id, created_at) 
+			  VALUES (?, ?, ?, ?, ?)`
+	
+	result, err := r.db.Exec(query, logID, kind, participant, refID, time.Now())
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// GetVariantStats merangkum kiriman, read receipt dan balasan template per varian
+func (r *SQLiteRepository) GetVariantStats(templateID int) ([]VariantStats, error) {
+	query := `SELECT l.variant_id, COUNT(*), 
+			      COALESCE(SUM(CASE WHEN l.success THEN 1 ELSE 0 END), 0),
+			      COALESCE(SUM(CASE WHEN e.reads > 0 THEN 1 ELSE 0 END), 0),
+			      COALESCE(SUM(e.reads), 0),
+			      COALESCE(SUM(e.replies), 0)
+			  FROM promote_logs l
+			  LEFT JOIN (
+			      SELECT log_id, 
+			          SUM(CASE WHEN kind = ? THEN 1 ELSE 0 END) AS reads,
+			          SUM(CASE WHEN kind = ? THEN 1 ELSE 0 END) AS replies
+			      FROM promote_log_engagement GROUP BY log_id
+			  ) e ON e.log_id = l.id
+			  WHERE l.template_id = ?
+			  GROUP BY l.variant_id ORDER BY l.variant_id`
+	
+	rows, err := r.db.Query(query, EngagementRead, EngagementReply, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var stats []VariantStats
+	for rows.Next() {
+		var s VariantStats
+		err := rows.Scan(&s.VariantID, &s.Sent, &s.Delivered, &s.ReadMessages, &s.ReadReceipts, &s.Replies)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	
+	return stats, rows.Err()
+}
+
+// === STATS ===
+
+func (r *SQLiteRepository) UpdateStats(date string, totalGroups, totalMessages, successMessages, failedMessages int) error {
 	query := `INSERT OR REPLACE INTO promote_stats 
 			  (date, total_groups, total_messages, success_messages, failed_messages, created_at) 
 			  VALUES (?, ?, ?, ?, ?, ?)`
@@ -312,6 +922,25 @@ func (r *SQLiteRepository) UpdateStats(date string, totalGroups, totalMessages,
 	return err
 }
 
+// IncrementStats menambah satu pesan ke statistik harian tanpa menimpa data yang sudah ada
+func (r *SQLiteRepository) IncrementStats(date string, success bool) error {
+	successCount, failedCount := 0, 1
+	if success {
+		successCount, failedCount = 1, 0
+	}
+	
+	query := `INSERT INTO promote_stats 
+			  (date, total_messages, success_messages, failed_messages, created_at) 
+			  VALUES (?, 1, ?, ?, ?)
+			  ON CONFLICT(date) DO UPDATE SET 
+			  total_messages = total_messages + 1,
+			  success_messages = success_messages + excluded.success_messages,
+			  failed_messages = failed_messages + excluded.failed_messages`
+	
+	_, err := r.db.Exec(query, date, successCount, failedCount, time.Now())
+	return err
+}
+
 func (r *SQLiteRepository) GetStats(date string) (*PromoteStats, error) {
 	query := `SELECT id, date, total_groups, total_messages, success_messages, failed_messages, created_at 
 			  FROM promote_stats WHERE date = ?`
@@ -332,12 +961,706 @@ func (r *SQLiteRepository) GetStats(date string) (*PromoteStats, error) {
 	return &stats, nil
 }
 
+// === SEND QUEUE ===
+// Waktu antrian disimpan dalam UTC (presisi detik) agar perbandingan di SQLite konsisten
+
+// queueColumns adalah kolom yang dibaca untuk setiap QueuedMessage
+const queueColumns = `id, group_jid, template_id, content, source, status, attempts, max_attempts, scheduled_at, sent_at, last_error, campaign_id, variant_id, created_at`
+
+// queueTime menormalkan waktu sebelum disimpan atau dibandingkan di promote_queue
+func queueTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+func (r *SQLiteRepository) EnqueueMessage(msg *QueuedMessage) error {
+	query := `INSERT INTO promote_queue 
+			  (group_jid, template_id, content, source, status, attempts, max_attempts, scheduled_at, campaign_id, variant_id, created_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	
+	if msg.Status == "" {
+		msg.Status = QueueStatusPending
+	}
+	msg.ScheduledAt = queueTime(msg.ScheduledAt)
+	msg.CreatedAt = time.Now()
+	
+	result, err := r.db.Exec(query, msg.GroupJID, msg.TemplateID, msg.Content, msg.Source,
+		msg.Status, msg.Attempts, msg.MaxAttempts, msg.ScheduledAt, msg.CampaignID, msg.VariantID, msg.CreatedAt)
+	if err != nil {
+		return err
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	
+	msg.ID = int(id)
+	return nil
+}
+
+func (r *SQLiteRepository) GetDueQueuedMessages(now time.Time, limit int) ([]QueuedMessage, error) {
+	query := `SELECT ` + queueColumns + ` FROM promote_queue 
+			  WHERE status = ? AND scheduled_at <= ? 
+			  ORDER BY scheduled_at ASC, id ASC LIMIT ?`
+	
+	rows, err := r.db.Query(query, QueueStatusPending, queueTime(now), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var messages []QueuedMessage
+	
+	for rows.Next() {
+		var msg QueuedMessage
+		var sentAt sql.NullTime
+		var lastError sql.NullString
+		
+		err := rows.Scan(&msg.ID, &msg.GroupJID, &msg.TemplateID, &msg.Content, &msg.Source,
+			&msg.Status, &msg.Attempts, &msg.MaxAttempts, &msg.ScheduledAt, &sentAt, &lastError, &msg.CampaignID, &msg.VariantID, &msg.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		
+		if sentAt.Valid {
+			msg.SentAt = &sentAt.Time
+		}
+		if lastError.Valid {
+			msg.LastError = &lastError.String
+		}
+		
+		messages = append(messages, msg)
+	}
+	
+	return messages, nil
+}
+
+func (r *SQLiteRepository) UpdateQueuedMessage(msg *QueuedMessage) error {
+	query := `UPDATE promote_queue 
+			  SET status = ?, attempts = ?, scheduled_at = ?, sent_at = ?, last_error = ? 
+			  WHERE id = ?`
+	
+	msg.ScheduledAt = queueTime(msg.ScheduledAt)
+	var sentAt interface{}
+	if msg.SentAt != nil {
+		sentAt = queueTime(*msg.SentAt)
+	}
+	
+	_, err := r.db.Exec(query, msg.Status, msg.Attempts, msg.ScheduledAt, sentAt, msg.LastError, msg.ID)
+	return err
+}
+
+func (r *SQLiteRepository) HasPendingQueuedMessage(groupJID string) (bool, error) {
+	query := `SELECT COUNT(*) FROM promote_queue WHERE group_jid = ? AND status = ?`
+	
+	var count int
+	if err := r.db.QueryRow(query, groupJID, QueueStatusPending).Scan(&count); err != nil {
+		return false, err
+	}
+	
+	return count > 0, nil
+}
+
+// CancelQueuedMessages membatalkan semua pesan grup yang belum terkirim
+func (r *SQLiteRepository) CancelQueuedMessages(groupJID string) (int, error) {
+	query := `UPDATE promote_queue SET status = ? WHERE group_jid = ? AND status = ?`
+	
+	result, err := r.db.Exec(query, QueueStatusCancelled, groupJID, QueueStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func (r *SQLiteRepository) CountQueuedMessages(status string) (int, error) {
+	query := `SELECT COUNT(*) FROM promote_queue WHERE status = ?`
+	
+	var count int
+	err := r.db.QueryRow(query, status).Scan(&count)
+	return count, err
+}
+
+func (r *SQLiteRepository) CountSentMessagesSince(since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM promote_queue WHERE status = ? AND sent_at >= ?`
+	
+	var count int
+	err := r.db.QueryRow(query, QueueStatusSent, queueTime(since)).Scan(&count)
+	return count, err
+}
+
+// MarkGroupPromoted mencatat waktu promosi terakhir grup setelah pesan benar-benar terkirim
+func (r *SQLiteRepository) MarkGroupPromoted(groupJID string, at time.Time) error {
+	query := `UPDATE auto_promote_groups SET last_promote_at = ?, updated_at = ? WHERE group_jid = ?`
+	
+	_, err := r.db.Exec(query, at, time.Now(), groupJID)
+	return err
+}
+
+// === CAMPAIGNS ===
+
+// campaignColumns adalah kolom yang dibaca untuk setiap Campaign
+const campaignColumns = `id, name, start_at, end_at, template_ids, category, target_all, interval_minutes, status, created_at, updated_at`
+
+func scanCampaign(row rowScanner) (*Campaign, error) {
+	var campaign Campaign
+	
+	err := row.Scan(&campaign.ID, &campaign.Name, &campaign.StartAt, &campaign.EndAt,
+		&campaign.TemplateIDs, &campaign.Category, &campaign.TargetAll, &campaign.IntervalMinutes,
+		&campaign.Status, &campaign.CreatedAt, &campaign.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &campaign, nil
+}
+
+// loadCampaignGroups mengisi GroupJIDs dari tabel campaign_groups
+func (r *SQLiteRepository) loadCampaignGroups(campaign *Campaign) error {
+	rows, err := r.db.Query(`SELECT group_jid FROM campaign_groups WHERE campaign_id = ? ORDER BY group_jid`, campaign.ID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	campaign.GroupJIDs = nil
+	for rows.Next() {
+		var groupJID string
+		if err := rows.Scan(&groupJID); err != nil {
+			return err
+		}
+		campaign.GroupJIDs = append(campaign.GroupJIDs, groupJID)
+	}
+	
+	return nil
+}
+
+func (r *SQLiteRepository) CreateCampaign(campaign *Campaign) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	query := `INSERT INTO campaigns 
+			  (name, start_at, end_at, template_ids, category, target_all, interval_minutes, status, created_at, updated_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	
+	now := time.Now()
+	if campaign.Status == "" {
+		campaign.Status = CampaignStatusActive
+	}
+	
+	result, err := tx.Exec(query, campaign.Name, campaign.StartAt.UTC(), campaign.EndAt.UTC(),
+		campaign.TemplateIDs, campaign.Category, campaign.TargetAll, campaign.IntervalMinutes,
+		campaign.Status, now, now)
+	if err != nil {
+		return err
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	
+	for _, groupJID := range campaign.GroupJIDs {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO campaign_groups (campaign_id, group_jid) VALUES (?, ?)`, id, groupJID)
+		if err != nil {
+			return err
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	
+	campaign.ID = int(id)
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+	return nil
+}
+
+func (r *SQLiteRepository) GetCampaignByID(id int) (*Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns WHERE id = ?`
+	
+	campaign, err := scanCampaign(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Campaign tidak ditemukan
+		}
+		return nil, err
+	}
+	
+	if err := r.loadCampaignGroups(campaign); err != nil {
+		return nil, err
+	}
+	
+	return campaign, nil
+}
+
+func (r *SQLiteRepository) GetAllCampaigns() ([]Campaign, error) {
+	query := `SELECT ` + campaignColumns + ` FROM campaigns ORDER BY id ASC`
+	
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	
+	var campaigns []Campaign
+	for rows.Next() {
+		campaign, err := scanCampaign(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		campaigns = append(campaigns, *campaign)
+	}
+	rows.Close()
+	
+	// Grup dimuat setelah rows ditutup agar tidak ada query bersarang
+	for i := range campaigns {
+		if err := r.loadCampaignGroups(&campaigns[i]); err != nil {
+			return nil, err
+		}
+	}
+	
+	return campaigns, nil
+}
+
+func (r *SQLiteRepository) UpdateCampaignStatus(id int, status string) error {
+	query := `UPDATE campaigns SET status = ?, updated_at = ? WHERE id = ?`
+	
+	_, err := r.db.Exec(query, status, time.Now(), id)
+	return err
+}
+
+// GetLastCampaignEnqueue mengembalikan kapan campaign terakhir mengantrikan pesan ke grup
+func (r *SQLiteRepository) GetLastCampaignEnqueue(campaignID int, groupJID string) (*time.Time, error) {
+	query := `SELECT created_at FROM promote_queue 
+			  WHERE campaign_id = ? AND group_jid = ? AND status != ? 
+			  ORDER BY id DESC LIMIT 1`
+	
+	var createdAt time.Time
+	err := r.db.QueryRow(query, campaignID, groupJID, QueueStatusCancelled).Scan(&createdAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Belum pernah dikirim
+		}
+		return nil, err
+	}
+	
+	return &createdAt, nil
+}
+
+// CancelCampaignQueuedMessages membatalkan pesan campaign yang belum terkirim
+func (r *SQLiteRepository) CancelCampaignQueuedMessages(campaignID int) (int, error) {
+	query := `UPDATE promote_queue SET status = ? WHERE campaign_id = ? AND status = ?`
+	
+	result, err := r.db.Exec(query, QueueStatusCancelled, campaignID, QueueStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+// === SCHEDULED JOBS ===
+// run_at disimpan dalam UTC seperti waktu antrian
+
+// scheduledJobColumns adalah kolom yang dibaca untuk setiap ScheduledJob
+const scheduledJobColumns = `id, template_id, group_jids, target_all, run_at, status, queued_count, executed_at, error_msg, created_by, created_at`
+
+func scanScheduledJob(row rowScanner) (*ScheduledJob, error) {
+	var job ScheduledJob
+	var groupJIDs string
+	var executedAt sql.NullTime
+	var errorMsg sql.NullString
+	
+	err := row.Scan(&job.ID, &job.TemplateID, &groupJIDs, &job.TargetAll, &job.RunAt, &job.Status,
+		&job.QueuedCount, &executedAt, &errorMsg, &job.CreatedBy, &job.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	
+	if groupJIDs != "" {
+		job.GroupJIDs = strings.Split(groupJIDs, ",")
+	}
+	if executedAt.Valid {
+		job.ExecutedAt = &executedAt.Time
+	}
+	if errorMsg.Valid {
+		job.ErrorMsg = &errorMsg.String
+	}
+	
+	return &job, nil
+}
+
+func (r *SQLiteRepository) queryScheduledJobs(query string, args ...interface{}) ([]ScheduledJob, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var jobs []ScheduledJob
+	for rows.Next() {
+		job, err := scanScheduledJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	
+	return jobs, nil
+}
+
+func (r *SQLiteRepository) CreateScheduledJob(job *ScheduledJob) error {
+	query := `INSERT INTO scheduled_jobs 
+			  (template_id, group_jids, target_all, run_at, status, created_by, created_at) 
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	
+	if job.Status == "" {
+		job.Status = ScheduledJobPending
+	}
+	job.RunAt = queueTime(job.RunAt)
+	job.CreatedAt = time.Now()
+	
+	result, err := r.db.Exec(query, job.TemplateID, strings.Join(job.GroupJIDs, ","), job.TargetAll,
+		job.RunAt, job.Status, job.CreatedBy, job.CreatedAt)
+	if err != nil {
+		return err
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	
+	job.ID = int(id)
+	return nil
+}
+
+func (r *SQLiteRepository) GetScheduledJobByID(id int) (*ScheduledJob, error) {
+	query := `SELECT ` + scheduledJobColumns + ` FROM scheduled_jobs WHERE id = ?`
+	
+	job, err := scanScheduledJob(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Job tidak ditemukan
+		}
+		return nil, err
+	}
+	
+	return job, nil
+}
+
+// GetScheduledJobs mengambil job yang menunggu, atau semua job jika includeFinished true
+func (r *SQLiteRepository) GetScheduledJobs(includeFinished bool) ([]ScheduledJob, error) {
+	if includeFinished {
+		return r.queryScheduledJobs(`SELECT ` + scheduledJobColumns + ` FROM scheduled_jobs ORDER BY run_at ASC, id ASC`)
+	}
+	
+	query := `SELECT ` + scheduledJobColumns + ` FROM scheduled_jobs WHERE status = ? ORDER BY run_at ASC, id ASC`
+	return r.queryScheduledJobs(query, ScheduledJobPending)
+}
+
+func (r *SQLiteRepository) GetDueScheduledJobs(now time.Time) ([]ScheduledJob, error) {
+	query := `SELECT ` + scheduledJobColumns + ` FROM scheduled_jobs 
+			  WHERE status = ? AND run_at <= ? ORDER BY run_at ASC, id ASC`
+	
+	return r.queryScheduledJobs(query, ScheduledJobPending, queueTime(now))
+}
+
+func (r *SQLiteRepository) UpdateScheduledJob(job *ScheduledJob) error {
+	query := `UPDATE scheduled_jobs SET 
+			  status = ?, queued_count = ?, executed_at = ?, error_msg = ? 
+			  WHERE id = ?`
+	
+	_, err := r.db.Exec(query, job.Status, job.QueuedCount, job.ExecutedAt, job.ErrorMsg, job.ID)
+	return err
+}
+
+// === SETTINGS ===
+
+func (r *SQLiteRepository) GetSetting(key string) (*PromoteSetting, error) {
+	query := `SELECT key, value, updated_at FROM promote_settings WHERE key = ?`
+	
+	var setting PromoteSetting
+	err := r.db.QueryRow(query, key).Scan(&setting.Key, &setting.Value, &setting.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Pengaturan belum pernah diubah
+		}
+		return nil, err
+	}
+	
+	return &setting, nil
+}
+
+func (r *SQLiteRepository) SetSetting(key, value string) error {
+	query := `INSERT INTO promote_settings (key, value, updated_at) VALUES (?, ?, ?)
+			  ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`
+	
+	_, err := r.db.Exec(query, key, value, time.Now())
+	return err
+}
+
+func (r *SQLiteRepository) DeleteSetting(key string) error {
+	_, err := r.db.Exec(`DELETE FROM promote_settings WHERE key = ?`, key)
+	return err
+}
+
+// === TEMPLATE VARIABLES ===
+
+func (r *SQLiteRepository) GetTemplateVariables() ([]TemplateVariable, error) {
+	query := `SELECT name, value, updated_by, updated_at FROM template_variables ORDER BY name`
+	
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var variables []TemplateVariable
+	for rows.Next() {
+		var variable TemplateVariable
+		if err := rows.Scan(&variable.Name, &variable.Value, &variable.UpdatedBy, &variable.UpdatedAt); err != nil {
+			return nil, err
+		}
+		variables = append(variables, variable)
+	}
+	
+	return variables, rows.Err()
+}
+
+func (r *SQLiteRepository) SetTemplateVariable(name, value, updatedBy string) error {
+	query := `INSERT INTO template_variables (name, value, updated_by, updated_at) VALUES (?, ?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET value = excluded.value, 
+			  updated_by = excluded.updated_by, updated_at = excluded.updated_at`
+	
+	_, err := r.db.Exec(query, name, value, updatedBy, time.Now())
+	return err
+}
+
+func (r *SQLiteRepository) DeleteTemplateVariable(name string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM template_variables WHERE name = ?`, name)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// === GROUP ATTRIBUTES ===
+
+// groupAttributeColumns adalah kolom yang dibaca untuk setiap GroupAttribute
+const groupAttributeColumns = `group_jid, name, value, updated_by, updated_at`
+
+func (r *SQLiteRepository) GetGroupAttributes(groupJID string) ([]GroupAttribute, error) {
+	query := `SELECT ` + groupAttributeColumns + ` FROM group_attributes WHERE group_jid = ? ORDER BY name`
+	return r.queryGroupAttributes(query, groupJID)
+}
+
+func (r *SQLiteRepository) GetAllGroupAttributes() ([]GroupAttribute, error) {
+	query := `SELECT ` + groupAttributeColumns + ` FROM group_attributes ORDER BY group_jid, name`
+	return r.queryGroupAttributes(query)
+}
+
+// queryGroupAttributes menjalankan query dan membaca hasilnya sebagai GroupAttribute
+func (r *SQLiteRepository) queryGroupAttributes(query string, args ...interface{}) ([]GroupAttribute, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var attributes []GroupAttribute
+	for rows.Next() {
+		var attr GroupAttribute
+		if err := rows.Scan(&attr.GroupJID, &attr.Name, &attr.Value, &attr.UpdatedBy, &attr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, attr)
+	}
+	
+	return attributes, rows.Err()
+}
+
+func (r *SQLiteRepository) SetGroupAttribute(groupJID, name, value, updatedBy string) error {
+	query := `INSERT INTO group_attributes (group_jid, name, value, updated_by, updated_at) VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(group_jid, name) DO UPDATE SET value = excluded.value, 
+			  updated_by = excluded.updated_by, updated_at = excluded.updated_at`
+	
+	_, err := r.db.Exec(query, groupJID, name, value, updatedBy, time.Now())
+	return err
+}
+
+func (r *SQLiteRepository) DeleteGroupAttribute(groupJID, name string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM group_attributes WHERE group_jid = ? AND name = ?`, groupJID, name)
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// === GROUP CATEGORIES ===
+
+// groupCategoryColumns adalah kolom yang dibaca untuk setiap GroupCategory
+const groupCategoryColumns = `group_jid, category, created_by, created_at`
+
+func (r *SQLiteRepository) GetGroupCategories(groupJID string) ([]GroupCategory, error) {
+	query := `SELECT ` + groupCategoryColumns + ` FROM group_categories WHERE group_jid = ? ORDER BY category`
+	return r.queryGroupCategories(query, groupJID)
+}
+
+func (r *SQLiteRepository) GetAllGroupCategories() ([]GroupCategory, error) {
+	query := `SELECT ` + groupCategoryColumns + ` FROM group_categories ORDER BY group_jid, category`
+	return r.queryGroupCategories(query)
+}
+
+// queryGroupCategories menjalankan query dan membaca hasilnya sebagai GroupCategory
+func (r *SQLiteRepository) queryGroupCategories(query string, args ...interface{}) ([]GroupCategory, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	
+	var categories []GroupCategory
+	for rows.Next() {
+		var category GroupCategory
+		if err := rows.Scan(&category.GroupJID, &category.Category, &category.CreatedBy, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	
+	return categories, rows.Err()
+}
+
+// SetGroupCategories mengganti seluruh kategori grup. Daftar kosong berarti grup
+// kembali menerima semua kategori.
+func (r *SQLiteRepository) SetGroupCategories(groupJID string, categories []string, createdBy string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	
+	if _, err := tx.Exec(`DELETE FROM group_categories WHERE group_jid = ?`, groupJID); err != nil {
+		return err
+	}
+	
+	now := time.Now()
+	for _, category := range categories {
+		_, err := tx.Exec(`INSERT OR IGNORE INTO group_categories (group_jid, category, created_by, created_at) VALUES (?, ?, ?, ?)`,
+			groupJID, category, createdBy, now)
+		if err != nil {
+			return err
+		}
+	}
+	
+	return tx.Commit()
+}
+
+// === LEASES ===
+// Waktu lease disimpan dalam UTC seperti waktu antrian agar perbandingan antar proses konsisten
+
+// AcquireLease mengambil atau memperpanjang lease secara atomik.
+// Berhasil jika lease belum ada, sudah dipegang holder yang sama, atau sudah kedaluwarsa.
+func (r *SQLiteRepository) AcquireLease(name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	query := `INSERT INTO promote_leases (name, holder, acquired_at, heartbeat_at, expires_at) 
+			  VALUES (?, ?, ?, ?, ?)
+			  ON CONFLICT(name) DO UPDATE SET 
+			      acquired_at = CASE WHEN promote_leases.holder = excluded.holder 
+			                         THEN promote_leases.acquired_at ELSE excluded.acquired_at END,
+			      holder = excluded.holder,
+			      heartbeat_at = excluded.heartbeat_at,
+			      expires_at = excluded.expires_at
+			  WHERE promote_leases.holder = excluded.holder OR promote_leases.expires_at <= excluded.heartbeat_at`
+	
+	at := queueTime(now)
+	result, err := r.db.Exec(query, name, holder, at, at, queueTime(now.Add(ttl)))
+	if err != nil {
+		return false, err
+	}
+	
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	
+	return affected > 0, nil
+}
+
+// ReleaseLease melepas lease agar instance standby bisa langsung mengambil alih
+func (r *SQLiteRepository) ReleaseLease(name, holder string) error {
+	query := `UPDATE promote_leases SET expires_at = ? WHERE name = ? AND holder = ?`
+	
+	_, err := r.db.Exec(query, queueTime(time.Now()), name, holder)
+	return err
+}
+
+func (r *SQLiteRepository) GetLease(name string) (*PromoteLease, error) {
+	query := `SELECT name, holder, acquired_at, heartbeat_at, expires_at FROM promote_leases WHERE name = ?`
+	
+	var lease PromoteLease
+	err := r.db.QueryRow(query, name).Scan(&lease.Name, &lease.Holder, &lease.AcquiredAt, &lease.HeartbeatAt, &lease.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil // Belum ada instance yang memegang lease
+		}
+		return nil, err
+	}
+	
+	return &lease, nil
+}
+
 // === UTILITY FUNCTIONS ===
 
+// sqliteTimeFormats adalah format waktu yang ditulis driver go-sqlite3
+var sqliteTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+// parseSQLiteTime memparse waktu hasil agregasi SQLite (MAX/MIN) yang dikembalikan sebagai teks
+func parseSQLiteTime(value string) (time.Time, error) {
+	value = strings.TrimSuffix(value, "Z")
+	for _, layout := range sqliteTimeFormats {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time value: %s", value)
+}
+
 // InitializeDatabase menginisialisasi database dan menjalankan migrasi
 func InitializeDatabase(dbPath string) (*sql.DB, Repository, error) {
+	// Busy timeout agar dua proses yang berbagi database tidak langsung gagal "database is locked"
+	dsn := dbPath
+	if !strings.Contains(dsn, "?") {
+		dsn += "?_busy_timeout=5000"
+	}
+	
 	// Buka koneksi database
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to open database: %v", err)
 	}
@@ -0,0 +1,314 @@
+// Package handlers - Command admin untuk varian template (A/B test) dan laporannya
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// variantSnippetLength adalah panjang cuplikan konten varian di daftar dan laporan
+const variantSnippetLength = 80
+
+// HandleAddVariantCommand menangani command .addvariant [ID] [konten].
+// Konten diambil dari teks asli agar baris baru tetap terjaga.
+func (h *AdminCommandHandler) HandleAddVariantCommand(evt *events.Message, messageText string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	args := strings.Fields(messageText)
+	templateID := 0
+	if len(args) >= 2 {
+		templateID, _ = strconv.Atoi(args[1])
+	}
+
+	var content string
+	if len(args) >= 3 {
+		rest := strings.TrimSpace(messageText[len(args[0]):])
+		content = strings.TrimSpace(rest[len(args[1]):])
+	}
+
+	if templateID == 0 || content == "" {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .addvariant [ID] [konten]
+
+📋 *Contoh:*
+• .addvariant 5 🔥 PROMO {DATE}! Chat {STORE_WA} sekarang
+
+💡 *Keterangan:*
+• Konten asli template adalah varian *A*, varian baru diberi label B, C, dst
+• Konten boleh beberapa baris dan memakai variabel/spintax seperti template biasa
+• Kiriman dibagi rata antara varian aktif, atur dengan *.absplit*`
+	}
+
+	variant, err := h.templateService.AddTemplateVariant(templateID, content, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENAMBAH VARIAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *VARIAN DITAMBAHKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+🔤 *Varian:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 *.variants %d* - Lihat semua varian
+📊 *.abreport %d* - Bandingkan hasil varian`,
+		templateID, variant.Label, templateID, templateID)
+}
+
+// HandleVariantsCommand menangani command .variants [ID]
+func (h *AdminCommandHandler) HandleVariantsCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .variants [ID]
+
+📋 *Contoh:*
+• .variants 5`
+	}
+
+	template, variants, err := h.templateService.GetTemplateVariants(templateID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGAMBIL VARIAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🔤 *VARIAN TEMPLATE ID %d*\n\n", template.ID))
+	result.WriteString(fmt.Sprintf("🏷️ *Judul:* %s\n", template.Title))
+	result.WriteString(fmt.Sprintf("⚖️ *Pembagian:* %s\n\n", abSplitLabel(template.ABSplit)))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	result.WriteString("🅰️ *Varian A* (konten asli)\n")
+	result.WriteString(fmt.Sprintf("   %s\n\n", variantSnippet(template.Content)))
+
+	activeCount := 0
+	for _, variant := range variants {
+		status := "✅"
+		if variant.IsActive {
+			activeCount++
+		} else {
+			status = "⏸️ nonaktif"
+		}
+		result.WriteString(fmt.Sprintf("🔤 *Varian %s* %s\n", variant.Label, status))
+		result.WriteString(fmt.Sprintf("   %s\n", variantSnippet(variant.Content)))
+		result.WriteString(fmt.Sprintf("   👤 %s | 🕒 %s\n\n", formatRevisionAuthor(variant.CreatedBy), variant.CreatedAt.Format("02 Jan 2006, 15:04")))
+	}
+
+	if activeCount == 0 {
+		result.WriteString("ℹ️ Belum ada varian aktif, semua kiriman memakai konten asli.\n\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("➕ *.addvariant %d [konten]* - Tambah varian\n", template.ID))
+	result.WriteString(fmt.Sprintf("⏸️ *.delvariant %d [label]* - Hentikan varian\n", template.ID))
+	result.WriteString(fmt.Sprintf("📊 *.abreport %d* - Bandingkan hasil", template.ID))
+
+	return result.String()
+}
+
+// HandleDelVariantCommand menangani command .delvariant [ID] [label]
+func (h *AdminCommandHandler) HandleDelVariantCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok || len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .delvariant [ID] [label]
+
+📋 *Contoh:*
+• .delvariant 5 B
+
+💡 Varian berhenti dikirim, tapi hasilnya tetap muncul di *.abreport*.`
+	}
+
+	label := strings.ToUpper(args[2])
+	if err := h.templateService.DisableTemplateVariant(templateID, label); err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGHENTIKAN VARIAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`⏸️ *VARIAN DIHENTIKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+🔤 *Varian:* %s
+
+💡 Hasil varian ini tetap bisa dilihat di *.abreport %d*.`, templateID, label, templateID)
+}
+
+// HandleABSplitCommand menangani command .absplit [ID] [send|group]
+func (h *AdminCommandHandler) HandleABSplitCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok || len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .absplit [ID] [send|group]
+
+📋 *Contoh:*
+• .absplit 5 send
+• .absplit 5 group
+
+💡 *Keterangan:*
+• *send* = varian dipilih acak setiap kiriman (default)
+• *group* = setiap grup selalu mendapat varian yang sama`
+	}
+
+	if err := h.templateService.SetABSplit(templateID, args[2]); err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGATUR PEMBAGIAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *PEMBAGIAN VARIAN DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+⚖️ *Pembagian:* %s`, templateID, abSplitLabel(strings.ToLower(args[2])))
+}
+
+// HandleABReportCommand menangani command .abreport [ID]
+func (h *AdminCommandHandler) HandleABReportCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .abreport [ID]
+
+📋 *Contoh:*
+• .abreport 5`
+	}
+
+	report, err := h.templateService.GetABReport(templateID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMBUAT LAPORAN A/B*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("📊 *LAPORAN A/B TEMPLATE ID %d*\n\n", report.Template.ID))
+	result.WriteString(fmt.Sprintf("🏷️ *Judul:* %s\n", report.Template.Title))
+	result.WriteString(fmt.Sprintf("⚖️ *Pembagian:* %s\n\n", abSplitLabel(report.Template.ABSplit)))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for _, variant := range report.Variants {
+		title := fmt.Sprintf("🔤 *Varian %s*", variant.Label)
+		if variant.VariantID == 0 {
+			title += " (konten asli)"
+		}
+		if !variant.IsActive {
+			title += " ⏸️ nonaktif"
+		}
+		result.WriteString(title + "\n")
+		result.WriteString(fmt.Sprintf("   %s\n", variantSnippet(variant.Content)))
+
+		stats := variant.Stats
+		if stats.Sent == 0 {
+			result.WriteString("   📭 Belum pernah dikirim\n\n")
+			continue
+		}
+
+		result.WriteString(fmt.Sprintf("   📤 Dikirim: %d\n", stats.Sent))
+		result.WriteString(fmt.Sprintf("   ✅ Terkirim: %d (%s)\n", stats.Delivered, formatPercent(stats.Delivered, stats.Sent)))
+		result.WriteString(fmt.Sprintf("   👀 Dibaca: %d pesan (%s), %d read receipt\n",
+			stats.ReadMessages, formatPercent(stats.ReadMessages, stats.Delivered), stats.ReadReceipts))
+		result.WriteString(fmt.Sprintf("   💬 Balasan: %d (%s per pesan terkirim)\n\n",
+			stats.Replies, formatRatio(stats.Replies, stats.Delivered)))
+	}
+
+	if report.Translated.Sent > 0 {
+		result.WriteString(fmt.Sprintf("🌐 Kiriman terjemahan: %d (tidak dihitung di varian mana pun)\n\n", report.Translated.Sent))
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 Read receipt hanya dari anggota yang mengaktifkan laporan dibaca.\n")
+	result.WriteString("💬 Balasan = pesan grup yang me-reply pesan promosi.")
+
+	return result.String()
+}
+
+// parseVariantTemplateID membaca ID template dari argumen kedua
+func parseVariantTemplateID(args []string) (int, bool) {
+	if len(args) < 2 {
+		return 0, false
+	}
+
+	templateID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, false
+	}
+	return templateID, true
+}
+
+// abSplitLabel menampilkan cara pembagian varian
+func abSplitLabel(split string) string {
+	if split == database.ABSplitGroup {
+		return "Per grup (grup selalu dapat varian yang sama)"
+	}
+	return "Per kiriman (acak setiap kirim)"
+}
+
+// variantSnippet meringkas konten varian menjadi satu baris pendek
+func variantSnippet(content string) string {
+	snippet := strings.Join(strings.Fields(content), " ")
+	runes := []rune(snippet)
+	if len(runes) > variantSnippetLength {
+		snippet = string(runes[:variantSnippetLength]) + "..."
+	}
+	return "_" + snippet + "_"
+}
+
+// formatPercent menampilkan persentase part dari total
+func formatPercent(part, total int) string {
+	if total == 0 {
+		return "0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// formatRatio menampilkan rata-rata part per total dengan dua desimal
+func formatRatio(part, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%.2f", float64(part)/float64(total))
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
 
+	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types/events"
 
+	"github.com/nabilulilalbab/promote/database"
 	"github.com/nabilulilalbab/promote/services"
 	"github.com/nabilulilalbab/promote/utils"
 )
@@ -18,8 +21,14 @@ type AdminCommandHandler struct {
 	templateService     *services.TemplateService
 	apiProductService   *services.APIProductService
 	groupManagerService *services.GroupManagerService
+	campaignService     *services.CampaignService
+	scheduledJobService *services.ScheduledJobService
+	client              *whatsmeow.Client // Untuk mengirim/mengunduh file (ekspor/impor template)
 	logger              *utils.Logger
 	adminNumbers        []string // Daftar nomor admin yang bisa menggunakan command admin
+
+	draftMutex sync.Mutex
+	drafts     map[string]*templateDraft // Sesi .newtemplate per nomor admin
 }
 
 // NewAdminCommandHandler membuat handler baru
@@ -38,9 +47,25 @@ func NewAdminCommandHandler(
 		groupManagerService: groupManagerService,
 		logger:              logger,
 		adminNumbers:        adminNumbers,
+		drafts:              make(map[string]*templateDraft),
 	}
 }
 
+// SetCampaignService mengatur service campaign untuk command campaign
+func (h *AdminCommandHandler) SetCampaignService(campaignService *services.CampaignService) {
+	h.campaignService = campaignService
+}
+
+// SetScheduledJobService mengatur service broadcast terjadwal untuk command .schedule
+func (h *AdminCommandHandler) SetScheduledJobService(scheduledJobService *services.ScheduledJobService) {
+	h.scheduledJobService = scheduledJobService
+}
+
+// SetClient mengatur client WhatsApp untuk command yang mengirim atau mengunduh file
+func (h *AdminCommandHandler) SetClient(client *whatsmeow.Client) {
+	h.client = client
+}
+
 // isAdmin mengecek apakah user adalah admin dengan validasi ketat
 func (h *AdminCommandHandler) isAdmin(userNumber string) bool {
 	// Validasi input
@@ -115,7 +140,8 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 💡 *TIPS PENTING*
 • Gunakan tanda kutip untuk teks spasi
 • Kategori: produk, diskon, testimoni, flashsale
-• Konten bisa pakai emoji dan formatting WhatsApp`
+• Konten bisa pakai emoji dan formatting WhatsApp
+• Konten multi-baris? Gunakan *.newtemplate*`
 	}
 
 	// Parse arguments (simplified parsing)
@@ -148,7 +174,7 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 	content := parts[2]
 
 	// Buat template
-	template, err := h.templateService.CreateTemplate(title, content, category)
+	template, err := h.templateService.CreateTemplate(title, content, category, evt.Info.Sender.User)
 	if err != nil {
 		h.logger.Errorf("Failed to create template: %v", err)
 		return fmt.Sprintf(`❌ *GAGAL MEMBUAT TEMPLATE*
@@ -172,6 +198,16 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 🔄 *Coba lagi atau hubungi admin*`, err.Error())
 	}
 
+	return h.templateCreatedMessage(template)
+}
+
+// templateCreatedMessage menyusun response setelah template baru tersimpan
+func (h *AdminCommandHandler) templateCreatedMessage(template *database.PromoteTemplate) string {
+	mediaLine := ""
+	if template.MediaType != "" {
+		mediaLine = fmt.Sprintf("📎 *Media:* %s\n", services.MediaTypeLabel(template.MediaType))
+	}
+
 	return fmt.Sprintf(`✅ *TEMPLATE BERHASIL DIBUAT!*
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -183,7 +219,7 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 🏷️ *Judul:* %s
 📂 *Kategori:* %s
 ✅ *Status:* Aktif
-
+%s
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 📝 *KONTEN TEMPLATE*
@@ -191,7 +227,7 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-💡 *COMMANDS SELANJUTNYA*
+%s💡 *COMMANDS SELANJUTNYA*
 • *.previewtemplate %d* - Preview template
 • *.edittemplate %d* - Edit template
 • *.listtemplates* - Lihat semua template
@@ -199,7 +235,7 @@ func (h *AdminCommandHandler) HandleAddTemplateCommand(evt *events.Message, args
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 🎉 *Template siap digunakan!*`,
-		template.ID, template.Title, template.Category, template.Content, template.ID, template.ID)
+		template.ID, template.Title, template.Category, mediaLine, template.Content, h.lintWarnings(template.Content), template.ID, template.ID)
 }
 
 // HandleEditTemplateCommand menangani command .edittemplate
@@ -303,7 +339,7 @@ func (h *AdminCommandHandler) HandleEditTemplateCommand(evt *events.Message, arg
 	content := parts[2]
 
 	// Update template
-	err = h.templateService.UpdateTemplate(templateID, title, content, category, true)
+	err = h.templateService.UpdateTemplate(templateID, title, content, category, true, evt.Info.Sender.User)
 	if err != nil {
 		h.logger.Errorf("Failed to update template %d: %v", templateID, err)
 		return fmt.Sprintf(`❌ *GAGAL MENGUPDATE TEMPLATE*
@@ -346,7 +382,7 @@ func (h *AdminCommandHandler) HandleEditTemplateCommand(evt *events.Message, arg
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-💡 *COMMANDS SELANJUTNYA*
+%s💡 *COMMANDS SELANJUTNYA*
 • *.previewtemplate %d* - Preview template
 • *.listtemplates* - Lihat semua template
 • *.deletetemplate %d* - Hapus template
@@ -354,7 +390,7 @@ func (h *AdminCommandHandler) HandleEditTemplateCommand(evt *events.Message, arg
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 🎉 *Perubahan langsung berlaku untuk auto promote!*`,
-		templateID, title, category, content, templateID, templateID)
+		templateID, title, category, content, h.lintWarnings(content), templateID, templateID)
 }
 
 // HandleDeleteTemplateCommand menangani command .deletetemplate
@@ -401,7 +437,7 @@ func (h *AdminCommandHandler) HandleDeleteTemplateCommand(evt *events.Message, a
 💡 *TIPS PENTING*
 • Gunakan .listtemplates untuk melihat ID template
 • ID harus berupa angka yang valid
-• Template yang dihapus tidak bisa dikembalikan`
+• Template yang dihapus bisa dipulihkan dengan .rollback`
 	}
 
 	// Parse ID
@@ -479,7 +515,7 @@ func (h *AdminCommandHandler) HandleDeleteTemplateCommand(evt *events.Message, a
 	}
 
 	// Hapus template
-	err = h.templateService.DeleteTemplate(templateID)
+	err = h.templateService.DeleteTemplate(templateID, evt.Info.Sender.User)
 	if err != nil {
 		h.logger.Errorf("Failed to delete template %d: %v", templateID, err)
 		return fmt.Sprintf(`❌ *GAGAL MENGHAPUS TEMPLATE*
@@ -517,9 +553,13 @@ func (h *AdminCommandHandler) HandleDeleteTemplateCommand(evt *events.Message, a
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 ⚠️ *PERINGATAN*
-• Template telah dihapus permanen
-• Tidak bisa dikembalikan lagi
+• Template tidak akan dikirim lagi
 • Auto promote akan menggunakan template lain
+• Isi terakhir tetap tersimpan di riwayat
+
+♻️ *PULIHKAN*
+• *.templatehistory %d* - Lihat riwayat
+• *.rollback %d [revisi]* - Pulihkan template
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
@@ -531,7 +571,7 @@ func (h *AdminCommandHandler) HandleDeleteTemplateCommand(evt *events.Message, a
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 ✅ *Template berhasil dihapus!*`,
-		templateID, template.Title, template.Category)
+		templateID, template.Title, template.Category, templateID, templateID)
 }
 
 // HandleTemplateStatsCommand menangani command .templatestats
@@ -1041,7 +1081,7 @@ func (h *AdminCommandHandler) HandleDeleteAllTemplatesCommand(evt *events.Messag
 	var errors []string
 
 	for _, template := range templates {
-		err := h.templateService.DeleteTemplate(template.ID)
+		err := h.templateService.DeleteTemplate(template.ID, evt.Info.Sender.User)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("ID %d: %v", template.ID, err))
 		} else {
@@ -1063,8 +1103,8 @@ func (h *AdminCommandHandler) HandleDeleteAllTemplatesCommand(evt *events.Messag
 	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
 	result.WriteString("           *PERINGATAN PENTING*\n")
 	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
-	result.WriteString("• Tindakan ini *tidak dapat* dibatalkan.\n")
-	result.WriteString("• Semua template telah dihapus permanen.\n")
+	result.WriteString("• Semua template tidak akan dikirim lagi.\n")
+	result.WriteString("• Isi terakhir tetap tersimpan di riwayat, pulihkan dengan *.rollback [ID] [revisi]*.\n")
 	result.WriteString("• Auto promote mungkin berhenti jika kehabisan template.\n")
 
 	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
@@ -1121,7 +1161,7 @@ func (h *AdminCommandHandler) HandleDeleteMultipleTemplatesCommand(evt *events.M
 • Pisahkan ID dengan koma tanpa spasi
 • Gunakan .alltemplates untuk melihat ID
 • Maksimal 20 ID sekaligus
-• Template yang dihapus tidak bisa dikembalikan`
+• Template yang dihapus bisa dipulihkan dengan .rollback`
 	}
 
 	// Parse ID dari argument
@@ -1214,7 +1254,7 @@ func (h *AdminCommandHandler) HandleDeleteMultipleTemplatesCommand(evt *events.M
 			continue
 		}
 
-		err = h.templateService.DeleteTemplate(id)
+		err = h.templateService.DeleteTemplate(id, evt.Info.Sender.User)
 		if err != nil {
 			errors = append(errors, fmt.Sprintf("ID %d: %v", id, err))
 		} else {
@@ -1852,9 +1892,25 @@ func (h *AdminCommandHandler) HandleGroupStatusCommand(evt *events.Message, args
 		lastPromoteInfo = "Belum pernah"
 	}
 
-	// Ambil jumlah template aktif
-	templates, _ := h.templateService.GetActiveTemplates()
+	scheduleInfo := "Interval default"
+	windowInfo := "Default"
+	nextPromoteInfo := "-"
+	strategyInfo := h.autoPromoteService.GetTemplateSelector().GetStrategyDescription(dbGroup)
+	if dbGroup != nil {
+		scheduleInfo = h.autoPromoteService.GetScheduleDescription(dbGroup)
+		windowInfo = h.autoPromoteService.GetSendWindowDescription(dbGroup)
+		if dbGroup.IsActive {
+			nextPromoteInfo = formatNextPromoteTime(h.autoPromoteService.NextPromoteTime(dbGroup))
+		}
+	}
+
+	// Ambil jumlah template aktif yang boleh dikirim ke grup ini
+	templates, categories, _ := h.autoPromoteService.GetTemplatesForGroup(groupInfo.JID)
 	templateCount := len(templates)
+	categoryInfo := "Semua kategori"
+	if len(categories) > 0 {
+		categoryInfo = strings.Join(categories, ", ")
+	}
 
 	return fmt.Sprintf(`📊 *STATUS GRUP AUTO PROMOTE*
 
@@ -1873,6 +1929,11 @@ func (h *AdminCommandHandler) HandleGroupStatusCommand(evt *events.Message, args
 🎯 *Status Auto Promote:* %s
 📅 *Promote Dimulai:* %s
 ⏰ *Promosi Terakhir:* %s
+🗓️ *Jadwal:* %s
+🕘 *Jam Kirim:* %s
+🎲 *Pilih Template:* %s
+📂 *Kategori:* %s
+⏭️ *Promosi Berikutnya:* %s
 📝 *Total Template Aktif:* %d template
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -1895,11 +1956,23 @@ func (h *AdminCommandHandler) HandleGroupStatusCommand(evt *events.Message, args
 • *.testgroup %d*
 	 _Kirim promosi test_
 
+• *.setschedule %d [jadwal]*
+	 _Atur jadwal grup_
+
+• *.setwindow %d [jam]*
+	 _Atur jam kirim grup_
+
+• *.setstrategy %d [strategi]*
+	 _Atur cara memilih template_
+
+• *.setcategories %d [kategori]*
+	 _Atur kategori template grup_
+
 • *.listgroups*
 	 _Kembali ke daftar grup_`,
 		groupInfo.Name, groupInfo.ID, groupInfo.MemberCount, status,
-		startedInfo, lastPromoteInfo, templateCount, groupInfo.JID,
-		groupID, groupID, groupID)
+		startedInfo, lastPromoteInfo, scheduleInfo, windowInfo, strategyInfo, categoryInfo, nextPromoteInfo, templateCount, groupInfo.JID,
+		groupID, groupID, groupID, groupID, groupID, groupID, groupID)
 }
 
 // HandleTestGroupCommand menangani command .testgroup [ID]
@@ -2008,23 +2081,23 @@ func (h *AdminCommandHandler) HandleTestGroupCommand(evt *events.Message, args [
 🔄 *Coba lagi atau hubungi admin*`, err.Error())
 	}
 
-	return fmt.Sprintf(`🚀 *PROMOSI BERHASIL DIKIRIM!*
+	return fmt.Sprintf(`🚀 *PROMOSI MASUK ANTRIAN!*
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-          *BERHASIL TERKIRIM*
+          *SIAP DIKIRIM*
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 🎯 *DETAIL PENGIRIMAN*
 👥 *Grup:* %s
 🆔 *ID:* %d
-📤 *Status:* TERKIRIM
+📤 *Status:* DALAM ANTRIAN
 🎲 *Template:* Random
-⏰ *Waktu:* Sekarang
+⏰ *Waktu:* Segera (mengikuti batas kirim)
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
 📋 *INFORMASI*
-• Promosi telah dikirim ke grup
+• Promosi dikirim oleh antrian dalam beberapa detik
 • Template dipilih secara otomatis
 • Tidak mempengaruhi jadwal rutin
 • Silakan cek grup untuk melihat
@@ -2111,16 +2184,152 @@ func (h *AdminCommandHandler) HandleAdminCommands(evt *events.Message, messageTe
 	case ".testgroup":
 		return h.HandleTestGroupCommand(evt, args)
 
+	case ".setschedule":
+		return h.HandleSetScheduleCommand(evt, args)
+
+	case ".setwindow":
+		return h.HandleSetWindowCommand(evt, args)
+
+	case ".setinterval":
+		return h.HandleSetIntervalCommand(evt, args)
+
+	case ".setstrategy":
+		return h.HandleSetStrategyCommand(evt, args)
+
+	case ".setweight":
+		return h.HandleSetWeightCommand(evt, args)
+
+	case ".setcategories":
+		return h.HandleSetCategoriesCommand(evt, args)
+
+	case ".groupcategories":
+		return h.HandleGroupCategoriesCommand(evt, args)
+
+	case ".forecast":
+		return h.HandleForecastCommand(evt, args)
+
+	// Campaign Commands
+	case ".createcampaign":
+		return h.HandleCreateCampaignCommand(evt, args)
+
+	case ".listcampaigns":
+		return h.HandleListCampaignsCommand(evt)
+
+	case ".pausecampaign":
+		return h.HandlePauseCampaignCommand(evt, args)
+
+	case ".resumecampaign":
+		return h.HandleResumeCampaignCommand(evt, args)
+
+	case ".campaignreport":
+		return h.HandleCampaignReportCommand(evt, args)
+
+	// Scheduled Broadcast Commands
+	case ".schedule":
+		return h.HandleScheduleCommand(evt, args)
+
+	case ".listschedules":
+		return h.HandleListSchedulesCommand(evt, args)
+
+	case ".cancelschedule":
+		return h.HandleCancelScheduleCommand(evt, args)
+
+	// Template Variable Commands
+	case ".setvar":
+		return h.HandleSetVarCommand(evt, args)
+
+	case ".listvars":
+		return h.HandleListVarsCommand(evt)
+
+	case ".delvar":
+		return h.HandleDelVarCommand(evt, args)
+
+	case ".setgroupvar":
+		return h.HandleSetGroupVarCommand(evt, args)
+
+	case ".groupvars":
+		return h.HandleGroupVarsCommand(evt, args)
+
+	case ".delgroupvar":
+		return h.HandleDelGroupVarCommand(evt, args)
+
+	// Locale Commands
+	case ".setlocale":
+		return h.HandleSetLocaleCommand(evt, args)
+
+	case ".translate":
+		return h.HandleTranslateCommand(evt, messageText)
+
+	case ".translations":
+		return h.HandleTranslationsCommand(evt, args)
+
+	case ".deltranslation":
+		return h.HandleDelTranslationCommand(evt, args)
+
 	// Template Management Commands
 	case ".addtemplate":
 		return h.HandleAddTemplateCommand(evt, args)
 
+	case ".newtemplate":
+		return h.HandleNewTemplateCommand(evt)
+
+	case ".canceltemplate":
+		return h.HandleCancelTemplateCommand(evt)
+
+	case ".savetemplate":
+		return h.HandleSaveTemplateCommand(evt, messageText)
+
 	case ".edittemplate":
 		return h.HandleEditTemplateCommand(evt, args)
 
 	case ".deletetemplate":
 		return h.HandleDeleteTemplateCommand(evt, args)
 
+	case ".templatehistory":
+		return h.HandleTemplateHistoryCommand(evt, args)
+
+	case ".diff":
+		return h.HandleDiffTemplateCommand(evt, args)
+
+	case ".rollback":
+		return h.HandleRollbackTemplateCommand(evt, args)
+
+	// A/B Test Commands
+	case ".addvariant":
+		return h.HandleAddVariantCommand(evt, messageText)
+
+	case ".variants":
+		return h.HandleVariantsCommand(evt, args)
+
+	case ".delvariant":
+		return h.HandleDelVariantCommand(evt, args)
+
+	case ".absplit":
+		return h.HandleABSplitCommand(evt, args)
+
+	case ".abreport":
+		return h.HandleABReportCommand(evt, args)
+
+	case ".validity":
+		return h.HandleValidityCommand(evt, args)
+
+	case ".linttemplates":
+		return h.HandleLintTemplatesCommand(evt)
+
+	// Template Import/Export Commands
+	case ".exporttemplates":
+		return h.HandleExportTemplatesCommand(evt, args)
+
+	case ".importtemplates":
+		return h.HandleImportTemplatesCommand(evt, args)
+
+	// Template Media Commands
+	case ".attachmedia":
+		return h.HandleAttachMediaCommand(evt, args)
+
+	case ".detachmedia":
+		return h.HandleDetachMediaCommand(evt, args)
+
 	case ".templatestats":
 		return h.HandleTemplateStatsCommand(evt)
 
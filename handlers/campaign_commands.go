@@ -0,0 +1,387 @@
+// Package handlers - Command admin untuk mengelola campaign promosi berbatas waktu
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// campaignServiceUnavailableMessage adalah response saat service campaign tidak tersedia
+const campaignServiceUnavailableMessage = `❌ *SERVICE TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *KESALAHAN SISTEM*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Service campaign tidak dikonfigurasi.`
+
+// HandleCreateCampaignCommand menangani command .createcampaign
+// Format: .createcampaign "Nama" "Mulai" "Selesai" "Template" "Grup" "Interval"
+func (h *AdminCommandHandler) HandleCreateCampaignCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.campaignService == nil || h.groupManagerService == nil {
+		return campaignServiceUnavailableMessage
+	}
+
+	parts := h.parseQuotedArgs(strings.Join(args[1:], " "))
+	if len(parts) < 6 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:*
+.createcampaign "Nama" "Mulai" "Selesai" "Template" "Grup" "Interval"
+
+📋 *Contoh:*
+.createcampaign "Flash Sale 10.10" "2026-10-10 08:00" "2026-10-10 22:00" "3,4,5" "1,5,8" "2h"
+.createcampaign "Ramadan" "2027-02-08 04:00" "2027-03-09 23:00" "kategori:ramadan" "all" "6h"
+
+💡 *Keterangan:*
+• *Waktu* - format YYYY-MM-DD HH:MM
+• *Template* - ID template (pisah koma) atau kategori:nama
+• *Grup* - ID dari .listgroups (pisah koma) atau *all* (semua grup aktif)
+• Template campaign boleh nonaktif agar tidak ikut rotasi biasa`
+	}
+
+	name, start, end, templateSpec, groupSpec, interval := parts[0], parts[1], parts[2], parts[3], parts[4], parts[5]
+
+	groupJIDs, groupNames, targetAll, errResponse := h.resolveGroupTargets(groupSpec)
+	if errResponse != "" {
+		return errResponse
+	}
+
+	campaign, err := h.campaignService.CreateCampaign(name, start, end, templateSpec, groupJIDs, targetAll, interval)
+	if err != nil {
+		h.logger.Errorf("Failed to create campaign: %v", err)
+		return fmt.Sprintf(`❌ *GAGAL MEMBUAT CAMPAIGN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.createcampaign* tanpa parameter untuk contoh format.`, err.Error())
+	}
+
+	targetInfo := "Semua grup aktif"
+	if !targetAll {
+		targetInfo = strings.Join(groupNames, ", ")
+	}
+
+	return fmt.Sprintf(`✅ *CAMPAIGN DIBUAT*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *DETAIL CAMPAIGN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID:* %d
+🏷️ *Nama:* %s
+📅 *Periode:* %s s/d %s
+📝 *Template:* %s
+👥 *Target:* %s
+⏰ *Interval:* %s
+📊 *Status:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Grup target memakai jadwal campaign selama periode berjalan.
+📈 Gunakan *.campaignreport %d* untuk melihat hasil.`,
+		campaign.ID, campaign.Name,
+		h.campaignService.FormatTime(campaign.StartAt), h.campaignService.FormatTime(campaign.EndAt),
+		formatCampaignTemplates(campaign), targetInfo,
+		formatInterval(time.Duration(campaign.IntervalMinutes)*time.Minute),
+		h.campaignService.GetCampaignState(campaign, time.Now()),
+		campaign.ID)
+}
+
+// HandleListCampaignsCommand menangani command .listcampaigns
+func (h *AdminCommandHandler) HandleListCampaignsCommand(evt *events.Message) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.campaignService == nil {
+		return campaignServiceUnavailableMessage
+	}
+
+	campaigns, err := h.campaignService.GetAllCampaigns()
+	if err != nil {
+		h.logger.Errorf("Failed to get campaigns: %v", err)
+		return `❌ *GAGAL MENGAMBIL CAMPAIGN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	if len(campaigns) == 0 {
+		return `📭 *BELUM ADA CAMPAIGN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.createcampaign* untuk membuat campaign baru.`
+	}
+
+	now := time.Now()
+	var result strings.Builder
+	result.WriteString("🎯 *DAFTAR CAMPAIGN*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for _, campaign := range campaigns {
+		target := fmt.Sprintf("%d grup", len(campaign.GroupJIDs))
+		if campaign.TargetAll {
+			target = "Semua grup aktif"
+		}
+
+		result.WriteString(fmt.Sprintf("🆔 *%d* - %s\n", campaign.ID, campaign.Name))
+		result.WriteString(fmt.Sprintf("   %s\n", h.campaignService.GetCampaignState(&campaign, now)))
+		result.WriteString(fmt.Sprintf("   📅 %s s/d %s\n",
+			h.campaignService.FormatTime(campaign.StartAt), h.campaignService.FormatTime(campaign.EndAt)))
+		result.WriteString(fmt.Sprintf("   👥 %s | ⏰ %s\n\n", target,
+			formatInterval(time.Duration(campaign.IntervalMinutes)*time.Minute)))
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 *.pausecampaign [ID]* | *.resumecampaign [ID]* | *.campaignreport [ID]*")
+
+	return result.String()
+}
+
+// HandlePauseCampaignCommand menangani command .pausecampaign [ID]
+func (h *AdminCommandHandler) HandlePauseCampaignCommand(evt *events.Message, args []string) string {
+	return h.handleCampaignStatusChange(evt, args, ".pausecampaign", true)
+}
+
+// HandleResumeCampaignCommand menangani command .resumecampaign [ID]
+func (h *AdminCommandHandler) HandleResumeCampaignCommand(evt *events.Message, args []string) string {
+	return h.handleCampaignStatusChange(evt, args, ".resumecampaign", false)
+}
+
+// handleCampaignStatusChange menjeda atau melanjutkan campaign
+func (h *AdminCommandHandler) handleCampaignStatusChange(evt *events.Message, args []string, command string, pause bool) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.campaignService == nil {
+		return campaignServiceUnavailableMessage
+	}
+
+	id, ok := parseCampaignID(args)
+	if !ok {
+		return fmt.Sprintf(`❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* %s [ID]
+📋 Gunakan .listcampaigns untuk melihat ID.`, command)
+	}
+
+	var campaign *database.Campaign
+	var err error
+	if pause {
+		campaign, err = h.campaignService.PauseCampaign(id)
+	} else {
+		campaign, err = h.campaignService.ResumeCampaign(id)
+	}
+
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGUBAH CAMPAIGN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	action := "DILANJUTKAN"
+	note := "Campaign kembali mengirim sesuai interval."
+	if pause {
+		action = "DIJEDA"
+		note = "Pesan campaign di antrian dibatalkan, grup kembali ke rotasi biasa."
+	}
+
+	return fmt.Sprintf(`✅ *CAMPAIGN %s*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID:* %d
+🏷️ *Nama:* %s
+📊 *Status:* %s
+
+💡 %s`, action, campaign.ID, campaign.Name,
+		h.campaignService.GetCampaignState(campaign, time.Now()), note)
+}
+
+// HandleCampaignReportCommand menangani command .campaignreport [ID]
+func (h *AdminCommandHandler) HandleCampaignReportCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.campaignService == nil {
+		return campaignServiceUnavailableMessage
+	}
+
+	id, ok := parseCampaignID(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .campaignreport [ID]
+📋 Gunakan .listcampaigns untuk melihat ID.`
+	}
+
+	report, err := h.campaignService.GetCampaignReport(id)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMBUAT LAPORAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	campaign := report.Campaign
+	successRate := 0.0
+	if report.Total > 0 {
+		successRate = float64(report.Success) / float64(report.Total) * 100
+	}
+
+	firstSent, lastSent := "-", "-"
+	if report.FirstSentAt != nil {
+		firstSent = h.campaignService.FormatTime(*report.FirstSentAt)
+		lastSent = h.campaignService.FormatTime(*report.LastSentAt)
+	}
+
+	var result strings.Builder
+	result.WriteString("📈 *LAPORAN CAMPAIGN*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("           *%s*\n", campaign.Name))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	result.WriteString(fmt.Sprintf("📊 *Status:* %s\n", report.State))
+	result.WriteString(fmt.Sprintf("📅 *Periode:* %s s/d %s\n",
+		h.campaignService.FormatTime(campaign.StartAt), h.campaignService.FormatTime(campaign.EndAt)))
+	result.WriteString(fmt.Sprintf("📝 *Template:* %s\n\n", formatCampaignTemplates(campaign)))
+	result.WriteString(fmt.Sprintf("📤 *Total Kirim:* %d\n", report.Total))
+	result.WriteString(fmt.Sprintf("✅ *Berhasil:* %d\n", report.Success))
+	result.WriteString(fmt.Sprintf("❌ *Gagal:* %d\n", report.Failed))
+	result.WriteString(fmt.Sprintf("📈 *Success Rate:* %.1f%%\n", successRate))
+	result.WriteString(fmt.Sprintf("⏮️ *Kirim Pertama:* %s\n", firstSent))
+	result.WriteString(fmt.Sprintf("⏭️ *Kirim Terakhir:* %s\n", lastSent))
+
+	if len(report.ByGroup) > 0 {
+		names := h.groupNamesByJID()
+
+		result.WriteString("\n*👥 PER GRUP*\n")
+		for _, groupJID := range sortedKeysByCount(report.ByGroup) {
+			name, ok := names[groupJID]
+			if !ok {
+				name = h.formatGroupJID(groupJID)
+			}
+			result.WriteString(fmt.Sprintf("• %s: %d pesan\n", name, report.ByGroup[groupJID]))
+		}
+	}
+
+	if len(report.ByTemplate) > 0 {
+		result.WriteString("\n*📝 PER TEMPLATE*\n")
+
+		var templateIDs []int
+		for id := range report.ByTemplate {
+			templateIDs = append(templateIDs, id)
+		}
+		sort.Ints(templateIDs)
+
+		for _, templateID := range templateIDs {
+			title := fmt.Sprintf("Template %d", templateID)
+			if template, err := h.templateService.GetTemplateByID(templateID); err == nil && template != nil {
+				title = template.Title
+			}
+			result.WriteString(fmt.Sprintf("• [%d] %s: %d pesan\n", templateID, title, report.ByTemplate[templateID]))
+		}
+	}
+
+	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 Data diambil dari log pengiriman promosi.")
+
+	return result.String()
+}
+
+// resolveGroupTargets mengubah daftar ID grup dari .listgroups ("1,5,8") menjadi JID.
+// Nilai "all" berarti semua grup aktif; errResponse berisi pesan untuk admin jika input tidak valid.
+func (h *AdminCommandHandler) resolveGroupTargets(groupSpec string) (groupJIDs, groupNames []string, targetAll bool, errResponse string) {
+	if strings.EqualFold(strings.TrimSpace(groupSpec), "all") {
+		return nil, nil, true, ""
+	}
+
+	for _, idStr := range strings.Split(groupSpec, ",") {
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			return nil, nil, false, fmt.Sprintf(`❌ *ID GRUP TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID '%s' bukan angka.
+📝 Gunakan .listgroups untuk melihat ID.`, idStr)
+		}
+
+		groupInfo, err := h.groupManagerService.GetGroupByID(id)
+		if err != nil {
+			return nil, nil, false, fmt.Sprintf(`❌ *GRUP TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+📝 Gunakan .listgroups untuk melihat ID yang valid.`, err.Error())
+		}
+
+		groupJIDs = append(groupJIDs, groupInfo.JID)
+		groupNames = append(groupNames, groupInfo.Name)
+	}
+
+	return groupJIDs, groupNames, false, ""
+}
+
+// groupNamesByJID memetakan JID grup ke nama grup yang diikuti bot
+func (h *AdminCommandHandler) groupNamesByJID() map[string]string {
+	names := make(map[string]string)
+	if h.groupManagerService == nil {
+		return names
+	}
+
+	groups, err := h.groupManagerService.GetAllJoinedGroups()
+	if err != nil {
+		h.logger.Warningf("Failed to get group names: %v", err)
+		return names
+	}
+
+	for _, group := range groups {
+		names[group.JID] = group.Name
+	}
+	return names
+}
+
+// parseCampaignID membaca ID campaign dari argumen command
+func parseCampaignID(args []string) (int, bool) {
+	if len(args) < 2 {
+		return 0, false
+	}
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// formatCampaignTemplates memformat sumber template campaign
+func formatCampaignTemplates(campaign *database.Campaign) string {
+	if campaign.Category != "" {
+		return "Kategori " + campaign.Category
+	}
+	return "ID " + campaign.TemplateIDs
+}
+
+// sortedKeysByCount mengurutkan key map berdasarkan jumlah terbesar
+func sortedKeysByCount(counts map[string]int) []string {
+	var keys []string
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] == counts[keys[j]] {
+			return keys[i] < keys[j]
+		}
+		return counts[keys[i]] > counts[keys[j]]
+	})
+	return keys
+}
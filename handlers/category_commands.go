@@ -0,0 +1,274 @@
+// Package handlers - Command admin untuk kategori template yang diikuti tiap grup
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// HandleSetCategoriesCommand menangani command .setcategories [ID Grup|all] [kategori1,kategori2|all]
+func (h *AdminCommandHandler) HandleSetCategoriesCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setcategories [ID Grup] [kategori]
+
+📋 *Contoh:*
+• .setcategories 3 vpn,produk_api_group
+• .setcategories 1,5 diskon
+• .setcategories all diskon
+• .setcategories 3 all
+
+💡 *Keterangan:*
+• Grup hanya menerima template dari kategori yang dipilih
+• *all* sebagai kategori = kembali menerima semua kategori
+• *all* sebagai grup = semua grup yang aktif auto promote
+• Campaign dan .schedule tetap memakai template yang dipilih admin`
+	}
+
+	if h.groupManagerService == nil {
+		return groupServiceUnavailableMessage
+	}
+
+	groupJIDs, groupNames, targetAll, errResponse := h.resolveGroupTargets(args[1])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	if targetAll {
+		activeGroups, err := h.autoPromoteService.GetActiveGroups()
+		if err != nil {
+			h.logger.Errorf("Failed to get active groups: %v", err)
+			return `❌ *GAGAL MENGAMBIL GRUP AKTIF*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+		}
+
+		names := h.groupNamesByJID()
+		for _, group := range activeGroups {
+			groupJIDs = append(groupJIDs, group.GroupJID)
+			groupNames = append(groupNames, groupDisplayName(names, group.GroupJID))
+		}
+	}
+
+	if len(groupJIDs) == 0 {
+		return `❌ *TIDAK ADA GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Tidak ada grup yang dipilih.
+📝 Gunakan .listgroups untuk melihat ID.`
+	}
+
+	spec := strings.Join(args[2:], "")
+	var categories []string
+	if !strings.EqualFold(spec, "all") {
+		categories = strings.Split(spec, ",")
+	}
+
+	var saved []string
+	for _, groupJID := range groupJIDs {
+		result, err := h.autoPromoteService.SetGroupCategories(groupJID, categories, evt.Info.Sender.User)
+		if err != nil {
+			return fmt.Sprintf(`❌ *GAGAL MENYIMPAN KATEGORI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+		}
+		saved = result
+	}
+
+	var result strings.Builder
+	result.WriteString("✅ *KATEGORI GRUP DIPERBARUI*\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	result.WriteString(fmt.Sprintf("👥 *Grup (%d):* %s\n", len(groupNames), strings.Join(groupNames, ", ")))
+
+	if len(saved) == 0 {
+		result.WriteString("📂 *Kategori:* Semua kategori\n")
+	} else {
+		result.WriteString(fmt.Sprintf("📂 *Kategori:* %s\n", strings.Join(saved, ", ")))
+
+		// Peringatkan kategori yang tidak punya template aktif (salah ketik atau belum diisi)
+		activeCounts := h.activeTemplateCountByCategory()
+		var empty []string
+		for _, category := range saved {
+			if activeCounts[category] == 0 {
+				empty = append(empty, category)
+			}
+		}
+		if len(empty) > 0 {
+			result.WriteString(fmt.Sprintf("\n⚠️ Belum ada template aktif di kategori: *%s*\n", strings.Join(empty, ", ")))
+			if len(empty) == len(saved) {
+				result.WriteString("🚫 Rotasi default grup ini akan dilewati sampai ada template di kategori tersebut.\n")
+			}
+		}
+	}
+
+	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 Gunakan *.groupcategories* untuk melihat pembagian kategori semua grup.")
+
+	return result.String()
+}
+
+// HandleGroupCategoriesCommand menangani command .groupcategories [ID Grup]
+func (h *AdminCommandHandler) HandleGroupCategoriesCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) >= 2 {
+		return h.groupCategoriesDetail(args[1])
+	}
+
+	templates, err := h.templateService.GetAllTemplates()
+	if err != nil {
+		h.logger.Errorf("Failed to get templates: %v", err)
+		return `❌ *GAGAL MENGAMBIL TEMPLATE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	subscriptions, err := h.autoPromoteService.GetAllGroupCategories()
+	if err != nil {
+		h.logger.Errorf("Failed to get group categories: %v", err)
+		return `❌ *GAGAL MENGAMBIL KATEGORI GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	totalCounts := make(map[string]int)
+	activeCounts := make(map[string]int)
+	for _, template := range templates {
+		totalCounts[template.Category]++
+		if template.IsActive {
+			activeCounts[template.Category]++
+		}
+	}
+
+	names := h.groupNamesByJID()
+	groupsByCategory := make(map[string][]string)
+	for groupJID, categories := range subscriptions {
+		for _, category := range categories {
+			groupsByCategory[category] = append(groupsByCategory[category], groupDisplayName(names, groupJID))
+			// Kategori yang diikuti grup tapi belum punya template tetap ditampilkan
+			if _, ok := totalCounts[category]; !ok {
+				totalCounts[category] = 0
+			}
+		}
+	}
+
+	if len(totalCounts) == 0 {
+		return `📭 *BELUM ADA KATEGORI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Kategori berasal dari template. Gunakan *.addtemplate* untuk membuat template.`
+	}
+
+	var categories []string
+	for category := range totalCounts {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var result strings.Builder
+	result.WriteString("📂 *KATEGORI TEMPLATE PER GRUP*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for _, category := range categories {
+		result.WriteString(fmt.Sprintf("📂 *%s* - %d aktif / %d template\n", category, activeCounts[category], totalCounts[category]))
+
+		groups := groupsByCategory[category]
+		if len(groups) == 0 {
+			result.WriteString("   👥 Belum diikuti grup khusus\n\n")
+			continue
+		}
+		sort.Strings(groups)
+		result.WriteString(fmt.Sprintf("   👥 %s\n", strings.Join(groups, ", ")))
+		if activeCounts[category] == 0 {
+			result.WriteString("   ⚠️ Tidak ada template aktif\n")
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("🌐 %d grup punya kategori khusus, grup lain menerima semua kategori.\n", len(subscriptions)))
+	result.WriteString("💡 *.setcategories [ID Grup] [kategori]* untuk mengatur\n")
+	result.WriteString("🔍 *.groupcategories [ID Grup]* untuk detail satu grup")
+
+	return result.String()
+}
+
+// groupCategoriesDetail menampilkan kategori dan jumlah template yang bisa dikirim ke satu grup
+func (h *AdminCommandHandler) groupCategoriesDetail(idArg string) string {
+	groupInfo, errResponse := h.groupFromArg(idArg)
+	if errResponse != "" {
+		return errResponse
+	}
+
+	templates, categories, err := h.autoPromoteService.GetTemplatesForGroup(groupInfo.JID)
+	if err != nil {
+		h.logger.Errorf("Failed to get templates for group %s: %v", groupInfo.JID, err)
+		return `❌ *GAGAL MENGAMBIL KATEGORI GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	categoryInfo := "Semua kategori"
+	if len(categories) > 0 {
+		categoryInfo = strings.Join(categories, ", ")
+	}
+
+	var result strings.Builder
+	result.WriteString("📂 *KATEGORI GRUP*\n\n")
+	result.WriteString(fmt.Sprintf("👥 *Grup:* %s\n", groupInfo.Name))
+	result.WriteString(fmt.Sprintf("📂 *Kategori:* %s\n", categoryInfo))
+	result.WriteString(fmt.Sprintf("📝 *Template Berlaku Saat Ini:* %d template\n\n", len(templates)))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(templates) == 0 {
+		result.WriteString("⚠️ Tidak ada template aktif yang sedang berlaku untuk grup ini, rotasi default akan dilewati.\n\n")
+	} else {
+		for i, template := range templates {
+			if i == 10 {
+				result.WriteString(fmt.Sprintf("... dan %d template lainnya\n", len(templates)-10))
+				break
+			}
+			result.WriteString(fmt.Sprintf("• *%d* - %s (%s)\n", template.ID, template.Title, template.Category))
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("💡 *.setcategories %s [kategori|all]* untuk mengubah", idArg))
+
+	return result.String()
+}
+
+// activeTemplateCountByCategory menghitung template aktif per kategori
+func (h *AdminCommandHandler) activeTemplateCountByCategory() map[string]int {
+	counts := make(map[string]int)
+
+	templates, err := h.templateService.GetActiveTemplates()
+	if err != nil {
+		h.logger.Warningf("Failed to get active templates: %v", err)
+		return counts
+	}
+
+	for _, template := range templates {
+		counts[template.Category]++
+	}
+	return counts
+}
+
+// groupDisplayName mengambil nama grup dari peta nama, atau JID jika bot tidak lagi di grup tersebut
+func groupDisplayName(names map[string]string, groupJID string) string {
+	if name, ok := names[groupJID]; ok {
+		return name
+	}
+	return groupJID
+}
@@ -0,0 +1,137 @@
+// Package handlers - Preview template untuk grup tertentu (.previewtemplate [ID] [ID Grup])
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/render"
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// previewSendOption adalah argumen opsional untuk mengirim preview sebagai pesan asli
+const previewSendOption = "dm"
+
+// HandleGroupPreviewCommand menangani command .previewtemplate [ID] [ID Grup] [dm].
+// Konten dirender persis seperti kiriman ke grup tersebut; dengan "dm" pesan yang sama
+// (termasuk media) dikirim ke chat admin.
+func (h *AdminCommandHandler) HandleGroupPreviewCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID := 0
+	if len(args) >= 3 {
+		templateID, _ = strconv.Atoi(args[1])
+	}
+	sendDM := len(args) >= 4 && strings.EqualFold(args[3], previewSendOption)
+
+	if templateID == 0 || (len(args) >= 4 && !sendDM) {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .previewtemplate [ID] [ID Grup] [dm]
+
+📋 *Contoh:*
+• .previewtemplate 5 3 - Lihat kiriman untuk grup 3
+• .previewtemplate 5 3 dm - Kirim juga sebagai pesan asli ke chat ini
+
+💡 *Keterangan:*
+• Variabel grup, bahasa, varian A/B dan spintax dirender seperti saat dikirim
+• Gunakan .listgroups untuk melihat ID grup`
+	}
+
+	groupInfo, errResponse := h.groupFromArg(args[2])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	now := time.Now().In(h.autoPromoteService.GetTimezone())
+	preview, err := h.templateService.PreviewForGroup(templateID, groupInfo.JID, now)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL PREVIEW TEMPLATE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	sentNote := ""
+	if sendDM {
+		if err := h.sendPreview(evt, preview); err != nil {
+			h.logger.Errorf("Failed to send template preview: %v", err)
+			sentNote = fmt.Sprintf("⚠️ *Gagal mengirim pesan asli:* %s\n\n", err.Error())
+		} else {
+			sentNote = "📨 Pesan asli sudah dikirim ke chat ini.\n\n"
+		}
+	}
+
+	return formatGroupPreview(preview, groupInfo, sentNote)
+}
+
+// sendPreview mengirim pesan preview (teks atau media + caption) ke chat admin
+func (h *AdminCommandHandler) sendPreview(evt *events.Message, preview *services.GroupPreview) error {
+	if h.client == nil {
+		return fmt.Errorf("client WhatsApp belum siap")
+	}
+
+	msg, err := h.templateService.BuildPreviewMessage(h.client, preview)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.client.SendMessage(context.Background(), evt.Info.Chat, msg); err != nil {
+		return fmt.Errorf("failed to send preview: %v", err)
+	}
+	return nil
+}
+
+// formatGroupPreview menampilkan detail dan isi preview grup
+func formatGroupPreview(preview *services.GroupPreview, groupInfo *services.GroupInfo, sentNote string) string {
+	template := preview.Template
+
+	var result strings.Builder
+	result.WriteString("📋 *PREVIEW TEMPLATE UNTUK GRUP*\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	result.WriteString(sentNote)
+	result.WriteString(fmt.Sprintf("🆔 *Template:* %d - %s\n", template.ID, template.Title))
+	result.WriteString(fmt.Sprintf("👥 *Grup:* %s\n", groupInfo.Name))
+	result.WriteString(fmt.Sprintf("🗣️ *Bahasa:* %s", render.LocaleLabel(preview.Locale)))
+	if preview.Translated {
+		result.WriteString(" - terjemahan")
+	}
+	result.WriteString("\n")
+	if !preview.Translated {
+		result.WriteString(fmt.Sprintf("🧪 *Varian:* %s\n", preview.Variant))
+	}
+
+	if template.MediaType != "" {
+		media := services.MediaTypeLabel(template.MediaType)
+		if preview.MediaMissing {
+			media += " - file tidak ditemukan, dikirim sebagai teks"
+		}
+		result.WriteString(fmt.Sprintf("📎 *Media:* %s (konten sebagai caption)\n", media))
+	}
+
+	for _, note := range preview.Notes {
+		result.WriteString(fmt.Sprintf("⚠️ %s\n", note))
+	}
+
+	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("           *KONTEN YANG DIKIRIM*\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	result.WriteString(preview.Content)
+	result.WriteString("\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+
+	if preview.HasSpintax {
+		result.WriteString("🎲 Spintax dipilih acak per kiriman, ulangi command untuk variasi lain.\n")
+	}
+	if sentNote == "" {
+		result.WriteString(fmt.Sprintf("💡 *.previewtemplate %d %d dm* - Kirim sebagai pesan asli", template.ID, groupInfo.ID))
+	}
+	return strings.TrimRight(result.String(), "\n")
+}
@@ -0,0 +1,92 @@
+// Package handlers - Command admin untuk memeriksa format WhatsApp template
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// maxLintTemplatesShown adalah jumlah template bermasalah yang ditampilkan .linttemplates
+const maxLintTemplatesShown = 15
+
+// maxLintIssuesShown adalah jumlah temuan per template yang ditampilkan
+const maxLintIssuesShown = 5
+
+// HandleLintTemplatesCommand menangani command .linttemplates
+func (h *AdminCommandHandler) HandleLintTemplatesCommand(evt *events.Message) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	results, checked, err := h.templateService.LintAllTemplates()
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMERIKSA TEMPLATE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	if len(results) == 0 {
+		return fmt.Sprintf(`✅ *SEMUA TEMPLATE RAPI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📋 %d template diperiksa, tidak ada masalah format.`, checked)
+	}
+
+	var result strings.Builder
+	result.WriteString("🧹 *AUDIT FORMAT TEMPLATE*\n\n")
+	result.WriteString(fmt.Sprintf("📋 *Diperiksa:* %d template\n", checked))
+	result.WriteString(fmt.Sprintf("⚠️ *Bermasalah:* %d template\n\n", len(results)))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for i, lint := range results {
+		if i == maxLintTemplatesShown {
+			result.WriteString(fmt.Sprintf("... dan %d template lainnya\n\n", len(results)-maxLintTemplatesShown))
+			break
+		}
+
+		status := ""
+		if !lint.IsActive {
+			status = " (nonaktif)"
+		}
+		result.WriteString(fmt.Sprintf("📝 *ID %d* - %s%s\n", lint.TemplateID, lint.Title, status))
+		result.WriteString(formatLintIssues(lint.Issues, "   "))
+		result.WriteString("\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 Perbaiki dengan *.edittemplate [ID]*, *.addvariant* atau *.translate*, lalu cek hasilnya dengan *.previewtemplate [ID]*.")
+
+	return result.String()
+}
+
+// lintWarnings menjalankan linter untuk konten yang baru disimpan dan memformat
+// temuannya sebagai catatan tambahan (kosong jika tidak ada temuan)
+func (h *AdminCommandHandler) lintWarnings(content string) string {
+	issues := h.templateService.LintTemplate(content)
+	if len(issues) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf(`⚠️ *CATATAN FORMAT*
+%s
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+`, formatLintIssues(issues, ""))
+}
+
+// formatLintIssues menampilkan temuan linter sebagai daftar, dibatasi maxLintIssuesShown
+func formatLintIssues(issues []services.LintIssue, indent string) string {
+	var result strings.Builder
+	for i, issue := range issues {
+		if i == maxLintIssuesShown {
+			result.WriteString(fmt.Sprintf("%s... dan %d catatan lainnya\n", indent, len(issues)-maxLintIssuesShown))
+			break
+		}
+		result.WriteString(fmt.Sprintf("%s• %s\n", indent, issue.String()))
+	}
+	return result.String()
+}
@@ -0,0 +1,195 @@
+// Package handlers - Command admin untuk bahasa grup dan terjemahan template
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/render"
+)
+
+// HandleSetLocaleCommand menangani command .setlocale [ID Grup] [bahasa|default]
+func (h *AdminCommandHandler) HandleSetLocaleCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	renderer := h.templateService.GetRenderer()
+	if len(args) < 3 {
+		return fmt.Sprintf(`❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setlocale [ID Grup] [bahasa|default]
+
+📋 *Contoh:*
+• .setlocale 3 en
+• .setlocale 3 ms
+• .setlocale 3 default
+
+💡 *Keterangan:*
+• Bahasa tersedia: %s
+• Bahasa default: %s
+• Grup menerima terjemahan template (.translate) jika ada, selain itu konten asli
+• {DATE}, {DAY} dan {MONTH} ditulis dalam bahasa grup`,
+			strings.Join(render.SupportedLocales(), ", "), render.LocaleLabel(renderer.DefaultLocale()))
+	}
+
+	groupInfo, errResponse := h.groupFromArg(args[1])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	group, err := renderer.SetGroupLocale(groupInfo.JID, args[2])
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGATUR BAHASA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	locale := render.LocaleLabel(renderer.GroupLocale(groupInfo.JID))
+	if group.Locale == "" {
+		locale += " - default"
+	}
+
+	return fmt.Sprintf(`✅ *BAHASA GRUP DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+👥 *Grup:* %s
+🗣️ *Bahasa:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Tambah terjemahan template dengan *.translate [ID] [bahasa] [konten]*.`, groupInfo.Name, locale)
+}
+
+// HandleTranslateCommand menangani command .translate [ID] [bahasa] [konten].
+// Konten diambil dari teks asli agar baris baru tetap terjaga.
+func (h *AdminCommandHandler) HandleTranslateCommand(evt *events.Message, messageText string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	args := strings.Fields(messageText)
+	templateID := 0
+	if len(args) >= 2 {
+		templateID, _ = strconv.Atoi(args[1])
+	}
+
+	var content string
+	if len(args) >= 4 {
+		rest := strings.TrimSpace(messageText[len(args[0]):])
+		rest = strings.TrimSpace(rest[len(args[1]):])
+		content = strings.TrimSpace(rest[len(args[2]):])
+	}
+
+	if templateID == 0 || content == "" {
+		return fmt.Sprintf(`❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .translate [ID] [bahasa] [konten]
+
+📋 *Contoh:*
+• .translate 5 en 🔥 PROMO {DAY}, {DATE}! Chat {STORE_WA} now
+
+💡 *Keterangan:*
+• Bahasa tersedia: %s
+• Terjemahan yang sudah ada akan diganti
+• Grup tanpa terjemahan untuk bahasanya menerima konten asli`, strings.Join(render.SupportedLocales(), ", "))
+	}
+
+	translation, err := h.templateService.SetTemplateTranslation(templateID, args[2], content, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENYIMPAN TERJEMAHAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *TERJEMAHAN DISIMPAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+🗣️ *Bahasa:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+%s💡 *.translations %d* - Lihat semua terjemahan`,
+		templateID, render.LocaleLabel(translation.Locale), h.lintWarnings(content), templateID)
+}
+
+// HandleTranslationsCommand menangani command .translations [ID]
+func (h *AdminCommandHandler) HandleTranslationsCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .translations [ID]
+
+📋 *Contoh:* .translations 5`
+	}
+
+	template, translations, err := h.templateService.GetTemplateTranslations(templateID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGAMBIL TERJEMAHAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	var result strings.Builder
+	result.WriteString("🗣️ *TERJEMAHAN TEMPLATE*\n\n")
+	result.WriteString(fmt.Sprintf("🆔 *Template:* %d - %s\n", template.ID, template.Title))
+	result.WriteString(fmt.Sprintf("📝 *Konten asli:* %s\n\n", variantSnippet(template.Content)))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(translations) == 0 {
+		result.WriteString("📭 Belum ada terjemahan, semua grup menerima konten asli.\n\n")
+	}
+	for _, translation := range translations {
+		result.WriteString(fmt.Sprintf("🌐 *%s*\n", render.LocaleLabel(translation.Locale)))
+		result.WriteString(fmt.Sprintf("   %s\n", variantSnippet(translation.Content)))
+		result.WriteString(fmt.Sprintf("   👤 %s • %s\n\n", formatRevisionAuthor(translation.UpdatedBy),
+			translation.UpdatedAt.Format("2006-01-02 15:04")))
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("💡 *.translate %d [bahasa] [konten]* - Tambah/ganti\n", template.ID))
+	result.WriteString(fmt.Sprintf("🗑️ *.deltranslation %d [bahasa]* - Hapus", template.ID))
+	return result.String()
+}
+
+// HandleDelTranslationCommand menangani command .deltranslation [ID] [bahasa]
+func (h *AdminCommandHandler) HandleDelTranslationCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok || len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .deltranslation [ID] [bahasa]
+
+📋 *Contoh:* .deltranslation 5 en`
+	}
+
+	locale, err := h.templateService.DeleteTemplateTranslation(templateID, args[2])
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGHAPUS TERJEMAHAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *TERJEMAHAN DIHAPUS*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+🗣️ *Bahasa:* %s
+
+📝 Grup berbahasa %s kembali menerima konten asli.`, templateID, render.LocaleLabel(locale), locale)
+}
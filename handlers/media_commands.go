@@ -0,0 +1,179 @@
+// Package handlers - Command admin untuk memasang media (gambar, video, dokumen) ke template
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// incomingMedia adalah media yang dikirim admin bersama command .attachmedia
+type incomingMedia struct {
+	mediaType  string
+	message    whatsmeow.DownloadableMessage
+	mimetype   string
+	fileName   string
+	fileLength uint64
+}
+
+// HandleAttachMediaCommand menangani command .attachmedia [ID].
+// Command dikirim sebagai reply ke foto/video/dokumen atau sebagai caption media tersebut.
+func (h *AdminCommandHandler) HandleAttachMediaCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID := 0
+	if len(args) >= 2 {
+		templateID, _ = strconv.Atoi(args[1])
+	}
+
+	media := templateMediaFromMessage(evt.Message)
+	if templateID == 0 || media == nil {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* reply foto/video/dokumen dengan .attachmedia [ID]
+
+📋 *Contoh:*
+• Reply foto: .attachmedia 5
+• Caption video: .attachmedia 5
+
+💡 *Keterangan:*
+• Promosi dikirim sebagai media dengan isi template sebagai caption
+• Media lama template otomatis diganti
+• Gunakan *.detachmedia [ID]* untuk kembali ke teks biasa`
+	}
+
+	if media.fileLength > services.MaxMediaSize {
+		return fmt.Sprintf(`❌ *MEDIA TERLALU BESAR*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Maksimal %d MB.`, services.MaxMediaSize>>20)
+	}
+
+	if h.client == nil {
+		return `❌ *CLIENT TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Client WhatsApp belum siap untuk mengunduh media.`
+	}
+
+	data, err := h.client.Download(context.Background(), media.message)
+	if err != nil {
+		h.logger.Errorf("Failed to download template media: %v", err)
+		return fmt.Sprintf(`❌ *GAGAL MENGUNDUH MEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Kirim ulang media jika sudah terlalu lama.`, err.Error())
+	}
+
+	template, err := h.templateService.AttachTemplateMedia(templateID, media.mediaType, data, media.mimetype, media.fileName)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMASANG MEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *MEDIA TEMPLATE DIPASANG*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d - %s
+📎 *Media:* %s
+📦 *Ukuran:* %d KB
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 *COMMANDS SELANJUTNYA*
+• *.previewtemplate %d* - Cek caption template
+• *.detachmedia %d* - Lepas media`,
+		template.ID, template.Title, services.MediaTypeLabel(template.MediaType), len(data)>>10, template.ID, template.ID)
+}
+
+// HandleDetachMediaCommand menangani command .detachmedia [ID]
+func (h *AdminCommandHandler) HandleDetachMediaCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID := 0
+	if len(args) >= 2 {
+		templateID, _ = strconv.Atoi(args[1])
+	}
+
+	if templateID == 0 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .detachmedia [ID]
+
+📋 *Contoh:*
+• .detachmedia 5
+
+💡 Template kembali dikirim sebagai teks biasa.`
+	}
+
+	if err := h.templateService.DetachTemplateMedia(templateID); err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MELEPAS MEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *MEDIA TEMPLATE DILEPAS*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d
+📝 Promosi berikutnya dikirim sebagai teks biasa.`, templateID)
+}
+
+// templateMediaFromMessage mengambil media dari pesan itu sendiri (caption)
+// atau dari pesan yang di-reply
+func templateMediaFromMessage(msg *waProto.Message) *incomingMedia {
+	if media := mediaFromMessage(msg); media != nil {
+		return media
+	}
+	return mediaFromMessage(msg.GetExtendedTextMessage().GetContextInfo().GetQuotedMessage())
+}
+
+// mediaFromMessage membaca gambar, video atau dokumen dari satu pesan
+func mediaFromMessage(msg *waProto.Message) *incomingMedia {
+	if image := msg.GetImageMessage(); image != nil {
+		return &incomingMedia{
+			mediaType:  database.MediaImage,
+			message:    image,
+			mimetype:   image.GetMimetype(),
+			fileLength: image.GetFileLength(),
+		}
+	}
+
+	if video := msg.GetVideoMessage(); video != nil {
+		return &incomingMedia{
+			mediaType:  database.MediaVideo,
+			message:    video,
+			mimetype:   video.GetMimetype(),
+			fileLength: video.GetFileLength(),
+		}
+	}
+
+	document := msg.GetDocumentMessage()
+	if document == nil {
+		document = msg.GetDocumentWithCaptionMessage().GetMessage().GetDocumentMessage()
+	}
+	if document != nil {
+		return &incomingMedia{
+			mediaType:  database.MediaDocument,
+			message:    document,
+			mimetype:   document.GetMimetype(),
+			fileName:   document.GetFileName(),
+			fileLength: document.GetFileLength(),
+		}
+	}
+	return nil
+}
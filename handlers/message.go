@@ -94,6 +94,16 @@ func (h *MessageHandler) HandleMessage(evt *events.Message) {
 func (h *MessageHandler) handlePersonalMessage(evt *events.Message, messageText string) {
 	fmt.Println("💬 Memproses pesan personal...")
 
+	// Admin yang sedang dalam sesi .newtemplate: semua pesan adalah isian template,
+	// termasuk yang diawali . atau / (misal ".:: PROMO ::."), kecuali command sesi itu sendiri
+	if h.adminCommandHandler != nil && h.adminCommandHandler.HasTemplateDraft(evt.Info.Sender.User) &&
+		!isTemplateDraftCommand(messageText) {
+		if response := h.adminCommandHandler.HandleTemplateDraftMessage(evt, messageText); response != "" {
+			h.sendMessage(evt.Info.Chat, response)
+		}
+		return
+	}
+
 	// Cek apakah ini adalah command (dimulai dengan / atau .)
 	if strings.HasPrefix(messageText, "/") || strings.HasPrefix(messageText, ".") {
 		h.handleCommand(evt, messageText)
@@ -104,6 +114,15 @@ func (h *MessageHandler) handlePersonalMessage(evt *events.Message, messageText
 	// Hanya merespon command auto promote dari admin
 }
 
+// isTemplateDraftCommand mengecek command yang tetap diproses selama sesi .newtemplate
+func isTemplateDraftCommand(messageText string) bool {
+	fields := strings.Fields(strings.ToLower(messageText))
+	if len(fields) == 0 {
+		return false
+	}
+	return fields[0] == ".canceltemplate" || fields[0] == ".newtemplate"
+}
+
 // handleGroupMessage menangani pesan dari grup
 func (h *MessageHandler) handleGroupMessage(evt *events.Message, messageText string) {
 	fmt.Println("👥 Memproses pesan grup...")
@@ -166,6 +185,12 @@ Ketik */help* untuk melihat command yang tersedia.`
 
 // getMessageText mengekstrak teks dari berbagai tipe pesan WhatsApp
 func (h *MessageHandler) getMessageText(msg *waProto.Message) string {
+	return extractMessageText(msg)
+}
+
+// extractMessageText mengambil teks atau caption dari satu pesan WhatsApp apa adanya.
+// Dipakai juga untuk membaca pesan yang di-reply (.savetemplate).
+func extractMessageText(msg *waProto.Message) string {
 	// Pesan teks biasa
 	if msg.GetConversation() != "" {
 		return msg.GetConversation()
@@ -176,6 +201,22 @@ func (h *MessageHandler) getMessageText(msg *waProto.Message) string {
 		return msg.GetExtendedTextMessage().GetText()
 	}
 
+	// Caption dokumen (misal file JSON untuk .importtemplates)
+	if msg.GetDocumentMessage() != nil {
+		return msg.GetDocumentMessage().GetCaption()
+	}
+	if document := msg.GetDocumentWithCaptionMessage().GetMessage().GetDocumentMessage(); document != nil {
+		return document.GetCaption()
+	}
+
+	// Caption gambar/video (misal .attachmedia sebagai caption foto)
+	if msg.GetImageMessage() != nil {
+		return msg.GetImageMessage().GetCaption()
+	}
+	if msg.GetVideoMessage() != nil {
+		return msg.GetVideoMessage().GetCaption()
+	}
+
 	// Jika bukan teks, return empty string
 	return ""
 }
@@ -333,8 +374,23 @@ func (h *MessageHandler) handleAutoPromoteCommand(evt *events.Message, messageTe
 	adminCommands := []string{
 		// Group Management Commands
 		".listgroups", ".enablegroup", ".enablemulti", ".disablegroup", ".groupstatus", ".testgroup",
+		// Schedule Commands
+		".setschedule",
+		".setwindow",
+		".setinterval",
+		".forecast",
+		".setstrategy", ".setweight",
+		".setcategories", ".groupcategories",
+		// Campaign Commands
+		".createcampaign", ".listcampaigns", ".pausecampaign", ".resumecampaign", ".campaignreport",
+		// Scheduled Broadcast Commands
+		".schedule", ".listschedules", ".cancelschedule",
+		// Template Variable Commands
+		".setvar", ".listvars", ".delvar", ".setgroupvar", ".groupvars", ".delgroupvar",
+		// Locale Commands
+		".setlocale", ".translate", ".translations", ".deltranslation",
 		// Template Management Commands
-		".addtemplate", ".edittemplate", ".deletetemplate", ".templatestats", ".promotestats", ".activegroups", ".fetchproducts", ".productstats", ".deleteall", ".deletemulti"}
+		".addtemplate", ".newtemplate", ".canceltemplate", ".savetemplate", ".edittemplate", ".deletetemplate", ".templatehistory", ".diff", ".rollback", ".addvariant", ".variants", ".delvariant", ".absplit", ".abreport", ".validity", ".exporttemplates", ".importtemplates", ".linttemplates", ".attachmedia", ".detachmedia", ".templatestats", ".promotestats", ".activegroups", ".fetchproducts", ".productstats", ".deleteall", ".deletemulti"}
 	for _, cmd := range adminCommands {
 		if strings.HasPrefix(lowerText, cmd) {
 			if h.adminCommandHandler != nil {
@@ -361,7 +417,7 @@ func (h *MessageHandler) handleAutoPromoteCommand(evt *events.Message, messageTe
 	}
 
 	// Cek apakah ini template command yang juga perlu admin access
-	templateCommands := []string{".listtemplates", ".alltemplates", ".previewtemplate", ".help"}
+	templateCommands := []string{".listtemplates", ".alltemplates", ".previewtemplate", ".statuspromo", ".help"}
 	for _, cmd := range templateCommands {
 		if strings.HasPrefix(lowerText, cmd) {
 			// Semua command auto promote sekarang hanya untuk admin
@@ -386,6 +442,10 @@ func (h *MessageHandler) handleAutoPromoteCommand(evt *events.Message, messageTe
 
 🔒 *Akses terbatas untuk keamanan sistem*`
 				}
+				// .previewtemplate [ID] [ID Grup] dirender persis seperti kiriman ke grup tersebut
+				if cmd == ".previewtemplate" && len(strings.Fields(messageText)) >= 3 {
+					return h.adminCommandHandler.HandleGroupPreviewCommand(evt, strings.Fields(messageText))
+				}
 				return h.promoteCommandHandler.HandlePromoteCommands(evt, messageText)
 			}
 			return "" // Tidak ada response jika handler tidak tersedia
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -169,26 +170,9 @@ func (h *PromoteCommandHandler) HandleDisableAcaCommand(evt *events.Message) str
 
 // HandleStatusPromoCommand menangani command .statuspromo
 func (h *PromoteCommandHandler) HandleStatusPromoCommand(evt *events.Message) string {
-	// Hanya bisa digunakan di grup
+	// Di chat personal tampilkan status sistem auto promote
 	if evt.Info.Chat.Server != types.GroupServer {
-		return `❌ *COMMAND TIDAK VALID*
-
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-           *HANYA UNTUK GRUP*
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-
-🚫 Command .statuspromo hanya bisa digunakan di grup
-
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-
-💡 *INFORMASI*
-• Command ini khusus untuk grup WhatsApp
-• Tidak bisa digunakan di chat personal
-• Gunakan /help untuk command umum
-
-━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-
-🔄 *Coba gunakan di grup yang sesuai*`
+		return h.formatSystemStatus()
 	}
 
 	groupJID := evt.Info.Chat.String()
@@ -266,6 +250,7 @@ func (h *PromoteCommandHandler) HandleStatusPromoCommand(evt *events.Message) st
 🎯 *Status:* %s
 📅 *Dimulai:* %s
 ⏰ *Promosi Terakhir:* %s
+🗓️ *Jadwal:* %s
 📝 *Template Tersedia:* %d template
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -278,7 +263,108 @@ func (h *PromoteCommandHandler) HandleStatusPromoCommand(evt *events.Message) st
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-🎯 *Status diperbarui real-time*`, status, startedInfo, lastPromoteInfo, templateCount)
+🎯 *Status diperbarui real-time*`, status, startedInfo, lastPromoteInfo,
+		h.autoPromoteService.GetScheduleDescription(group), templateCount)
+}
+
+// formatSystemStatus memformat status scheduler, interval efektif, dan antrian kirim
+func (h *PromoteCommandHandler) formatSystemStatus() string {
+	schedulerStatus := "❌ Berhenti"
+	if h.autoPromoteService.IsSchedulerRunning() {
+		schedulerStatus = "✅ Berjalan"
+	}
+
+	activeGroups, _ := h.autoPromoteService.GetActiveGroupsCount()
+	templates, _ := h.templateService.GetActiveTemplates()
+	queue := h.autoPromoteService.GetQueueStatus()
+
+	return fmt.Sprintf(`📊 *STATUS AUTO PROMOTE*
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *SCHEDULER*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🤖 *Scheduler:* %s
+⏰ *Interval Default:* %s
+📦 *Sumber Interval:* %s
+🌏 *Timezone:* %s
+🕘 *Jam Kirim Default:* %s
+⏮️ *Jadwal Terlewat:* %s
+🎲 *Pilih Template:* %s (%s)
+👥 *Grup Aktif:* %d grup
+📝 *Template Aktif:* %d template
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *ANTRIAN KIRIM*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📤 *Menunggu:* %d pesan
+✅ *Terkirim Hari Ini:* %d / %d
+🚦 *Batas Per Menit:* %d pesan
+🎲 *Jitter:* %s
+❌ *Gagal (total):* %d pesan
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *LEADER INSTANCE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+%s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+💡 Gunakan *.setinterval* untuk mengubah interval`,
+		schedulerStatus,
+		formatInterval(h.autoPromoteService.GetInterval()),
+		h.autoPromoteService.GetIntervalSource(),
+		h.autoPromoteService.GetTimezone().String(),
+		h.autoPromoteService.GetDefaultSendWindowDescription(),
+		h.autoPromoteService.GetMissedSlotPolicy(),
+		h.autoPromoteService.GetTemplateSelector().GetGlobalStrategy().String(),
+		h.autoPromoteService.GetTemplateSelector().GetStrategySource(),
+		activeGroups,
+		len(templates),
+		queue.Pending,
+		queue.SentToday, queue.DailyLimit,
+		queue.PerMinuteLimit,
+		formatInterval(queue.Spread),
+		queue.FailedTotal,
+		h.formatLeaderStatus())
+}
+
+// formatLeaderStatus memformat status lease scheduler antar instance
+func (h *PromoteCommandHandler) formatLeaderStatus() string {
+	status, err := h.autoPromoteService.GetLeaderStatus()
+	if err != nil {
+		h.logger.Errorf("Failed to get leader status: %v", err)
+		return "⚠️ Gagal membaca status lease"
+	}
+	if status == nil {
+		return "👑 *Mode:* Single instance (tanpa lock)"
+	}
+
+	role := "💤 Standby"
+	if status.IsLeader {
+		role = "👑 Leader"
+	}
+
+	lines := []string{
+		fmt.Sprintf("🖥️ *Instance Ini:* %s", status.InstanceID),
+		fmt.Sprintf("🎭 *Peran:* %s", role),
+	}
+
+	switch {
+	case status.Holder == "":
+		lines = append(lines, "👑 *Leader:* Belum ada")
+	case status.Expired:
+		lines = append(lines, fmt.Sprintf("👑 *Leader:* %s (lease kedaluwarsa, menunggu pengambilalihan)", status.Holder))
+	default:
+		lines = append(lines,
+			fmt.Sprintf("👑 *Leader:* %s", status.Holder),
+			fmt.Sprintf("💓 *Heartbeat:* %d detik lalu", int(time.Since(*status.HeartbeatAt).Seconds())),
+			fmt.Sprintf("⏱️ *Leader Sejak:* %s", status.AcquiredAt.In(h.autoPromoteService.GetTimezone()).Format("2006-01-02 15:04")))
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 // HandleTestPromoCommand menangani command .testpromo
@@ -332,13 +418,13 @@ func (h *PromoteCommandHandler) HandleTestPromoCommand(evt *events.Message) stri
 🔄 *Coba lagi atau hubungi admin*`, err.Error())
 	}
 
-	return `🚀 *PROMOSI BERHASIL DIKIRIM!*
+	return `🚀 *PROMOSI MASUK ANTRIAN!*
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
-          *BERHASIL TERKIRIM*
+          *SIAP DIKIRIM*
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-✅ Promosi telah dikirim ke grup ini
+✅ Promosi akan dikirim ke grup ini dalam beberapa detik
 🎲 Template dipilih secara random
 📝 Contoh bagaimana auto promote bekerja
 
@@ -415,8 +501,12 @@ func (h *PromoteCommandHandler) HandleListTemplatesCommand(evt *events.Message)
 
 		result.WriteString(fmt.Sprintf("🆔 *ID: %d* - %s\n", template.ID, template.Title))
 		result.WriteString(fmt.Sprintf("📂 *Kategori:* %s\n", template.Category))
+		result.WriteString(fmt.Sprintf("⚖️ *Bobot:* %d\n", template.Weight))
 		result.WriteString(fmt.Sprintf("📅 *Dibuat:* %s\n", template.CreatedAt.Format("2006-01-02")))
 		result.WriteString(fmt.Sprintf("✅ *Status:* %s\n", getTemplateStatusText(template.IsActive)))
+		if validity := services.DescribeValidity(&template, h.autoPromoteService.GetTimezone()); validity != "" {
+			result.WriteString(fmt.Sprintf("🗓️ *Berlaku:* %s\n", validity))
+		}
 
 		if i < len(templates)-1 && i < 14 {
 			result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
@@ -454,8 +544,9 @@ func (h *PromoteCommandHandler) HandlePreviewTemplateCommand(evt *events.Message
 	if len(args) < 2 {
 		return `❌ *FORMAT SALAH*
 
-📝 **Format:** .previewtemplate [ID]
+📝 **Format:** .previewtemplate [ID] [ID Grup] [dm]
 📋 **Contoh:** .previewtemplate 1
+📋 **Untuk grup:** .previewtemplate 1 3 (tambah *dm* untuk kirim sebagai pesan asli)
 
 💡 Gunakan .listtemplates untuk melihat daftar template`
 	}
@@ -583,8 +674,12 @@ func (h *PromoteCommandHandler) HandleAllTemplatesCommand(evt *events.Message) s
 
 		result.WriteString(fmt.Sprintf("%s *ID: %d* - %s\n", statusIcon, template.ID, template.Title))
 		result.WriteString(fmt.Sprintf("📂 *Kategori:* %s\n", template.Category))
+		result.WriteString(fmt.Sprintf("⚖️ *Bobot:* %d\n", template.Weight))
 		result.WriteString(fmt.Sprintf("📅 *Dibuat:* %s\n", template.CreatedAt.Format("2006-01-02")))
 		result.WriteString(fmt.Sprintf("✅ *Status:* %s\n", getTemplateStatusText(template.IsActive)))
+		if validity := services.DescribeValidity(&template, h.autoPromoteService.GetTimezone()); validity != "" {
+			result.WriteString(fmt.Sprintf("🗓️ *Berlaku:* %s\n", validity))
+		}
 
 		if i < len(templates)-1 {
 			result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
@@ -639,6 +734,114 @@ func (h *PromoteCommandHandler) HandleHelpCommand(evt *events.Message) string {
 
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
+🗓️ *JADWAL PROMOSI*
+
+• *.setschedule* [ID] [jadwal]
+  _Atur jadwal per grup_
+  Contoh: .setschedule 3 every 3h
+  Contoh: .setschedule 3 08:00,19:00
+
+• *.setwindow* [ID] [jam]
+  _Atur jam kirim (quiet hours) per grup_
+  Contoh: .setwindow 3 08:00-21:00
+
+• *.setinterval* [durasi]
+  _Ubah interval default (tersimpan)_
+  Contoh: .setinterval 90m
+
+• *.setstrategy* [ID|global] [strategi]
+  _Cara memilih template: random, roundrobin, leastrecent, norepeat:N_
+  Contoh: .setstrategy global norepeat:3
+
+• *.setweight* [ID Template] [bobot]
+  _Bobot template untuk strategi random (1-100)_
+  Contoh: .setweight 4 3
+
+• *.setcategories* [ID] [kategori|all]
+  _Grup hanya menerima template dari kategori tertentu_
+  Contoh: .setcategories 3 vpn,produk_api_group
+
+• *.groupcategories* [ID]
+  _Lihat pembagian kategori per grup_
+
+• *.forecast* [jam] [ID grup]
+  _Simulasi jadwal tanpa mengirim_
+  Contoh: .forecast 24 3,7
+
+• *.statuspromo*
+  _Lihat status scheduler & antrian_
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🎯 *CAMPAIGN*
+
+• *.createcampaign* "Nama" "Mulai" "Selesai" "Template" "Grup" "Interval"
+  _Buat campaign berbatas waktu_
+  Contoh: .createcampaign "Flash Sale" "2026-10-10 08:00" "2026-10-10 22:00" "3,4" "all" "2h"
+
+• *.listcampaigns*
+  _Lihat semua campaign_
+
+• *.pausecampaign* [ID] / *.resumecampaign* [ID]
+  _Jeda atau lanjutkan campaign_
+
+• *.campaignreport* [ID]
+  _Laporan pengiriman campaign_
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏰ *BROADCAST TERJADWAL*
+
+• *.schedule* [ID Template] [ID Grup|all] [waktu]
+  _Kirim template sekali pada waktu tertentu_
+  Contoh: .schedule 5 1,3 besok 19:30
+
+• *.listschedules* [all]
+  _Lihat jadwal broadcast (all = termasuk riwayat)_
+
+• *.cancelschedule* [ID]
+  _Batalkan jadwal yang belum dikirim_
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🔤 *VARIABEL TEMPLATE*
+
+• *.setvar* [NAMA] [nilai]
+  _Atur variabel global, dipakai sebagai {NAMA}_
+  Contoh: .setvar STORE_WA 6287786388052
+
+• *.listvars*
+  _Lihat semua variabel_
+
+• *.delvar* [NAMA]
+  _Hapus variabel yang tidak dipakai_
+
+• *.setgroupvar* [ID Grup] [NAMA] [nilai]
+  _Variabel khusus satu grup, menimpa nilai global_
+  Contoh: .setgroupvar 3 RESELLER_WA 6281234567890
+
+• *.groupvars* [ID Grup]
+  _Lihat variabel grup + {GROUP_NAME}, {MEMBER_COUNT}_
+
+• *.delgroupvar* [ID Grup] [NAMA]
+  _Hapus variabel khusus grup_
+
+🗣️ *BAHASA & TERJEMAHAN*
+
+• *.setlocale* [ID Grup] [id|en|ms|default]
+  _Bahasa grup untuk terjemahan dan {DATE}/{DAY}/{MONTH}_
+
+• *.translate* [ID] [bahasa] [konten]
+  _Tambah/ganti terjemahan template_
+
+• *.translations* [ID]
+  _Lihat terjemahan template_
+
+• *.deltranslation* [ID] [bahasa]
+  _Hapus terjemahan, grup kembali ke konten asli_
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
 📝 *TEMPLATE MANAGEMENT*
 
 • *.listtemplates*
@@ -648,9 +851,13 @@ func (h *PromoteCommandHandler) HandleHelpCommand(evt *events.Message) string {
   _Lihat semua template_
 
 • *.previewtemplate* [ID]
-  _Preview template_
+  _Preview template (beberapa variasi jika ada spintax)_
   Contoh: .previewtemplate 5
 
+• *.previewtemplate* [ID] [ID Grup] [dm]
+  _Preview persis seperti kiriman ke grup, dm = kirim sebagai pesan asli_
+  Contoh: .previewtemplate 5 3 dm
+
 • *.help*
   _Bantuan lengkap_
 
@@ -661,6 +868,15 @@ func (h *PromoteCommandHandler) HandleHelpCommand(evt *events.Message) string {
 • *.addtemplate* "Judul" "Kategori"
   _Tambah template baru_
 
+• *.newtemplate*
+  _Buat template lewat tanya-jawab (konten multi-baris)_
+
+• *.canceltemplate*
+  _Batalkan sesi .newtemplate_
+
+• *.savetemplate* "Judul" kategori
+  _Reply pesan/media untuk disimpan sebagai template_
+
 • *.edittemplate* [ID] "Judul"
   _Edit template existing_
 
@@ -673,6 +889,48 @@ func (h *PromoteCommandHandler) HandleHelpCommand(evt *events.Message) string {
 • *.deletemulti* [ID1,ID2,ID3]
   _Hapus multiple template_
 
+• *.templatehistory* [ID]
+  _Riwayat perubahan template (termasuk yang terhapus)_
+
+• *.diff* [ID] [revisi]
+  _Bandingkan revisi dengan versi sekarang_
+
+• *.rollback* [ID] [revisi]
+  _Kembalikan/pulihkan template ke revisi tersebut_
+
+• *.addvariant* [ID] [konten]
+  _Tambah varian A/B (konten asli = varian A)_
+
+• *.variants* [ID]
+  _Lihat varian template_
+
+• *.delvariant* [ID] [label]
+  _Hentikan varian, hasilnya tetap di laporan_
+
+• *.absplit* [ID] [send|group]
+  _Bagi varian per kiriman atau per grup_
+
+• *.validity* [ID] [from|until|days|hours|clear] [nilai]
+  _Masa berlaku template, nonaktif otomatis saat berakhir_
+
+• *.abreport* [ID]
+  _Bandingkan terkirim, dibaca dan balasan per varian_
+
+• *.exporttemplates* [all|ID1,ID2]
+  _Kirim template sebagai file JSON_
+
+• *.importtemplates* [apply]
+  _Caption/reply file JSON, tanpa apply = dry run_
+
+• *.linttemplates*
+  _Audit format WhatsApp semua template_
+
+• *.attachmedia* [ID]
+  _Reply foto/video/dokumen untuk jadi media template_
+
+• *.detachmedia* [ID]
+  _Kirim template kembali sebagai teks_
+
 • *.templatestats*
   _Statistik template_
 
@@ -731,6 +989,7 @@ Sistem otomatis untuk mengirim promosi bisnis sesuai interval yang dikonfigurasi
 📝 **Commands Template:**
 • .listtemplates - Lihat daftar template
 • .previewtemplate [ID] - Preview template
+• .previewtemplate [ID] [ID Grup] [dm] - Preview persis seperti kiriman ke grup
 • .addtemplate - Tambah template (admin only)
 • .edittemplate [ID] - Edit template (admin only)
 • .deletetemplate [ID] - Hapus template (admin only)
@@ -750,7 +1009,8 @@ Sistem otomatis untuk mengirim promosi bisnis sesuai interval yang dikonfigurasi
 • 10+ template promosi bisnis siap pakai
 • Random selection untuk variasi
 • Admin bisa tambah/edit template
-• Support variables: {DATE}, {TIME}, dll
+• Support variables: {DATE}, {TIME}, dll + {IF}/{EACH}
+• Spintax {Halo|Hai} untuk variasi tiap grup
 
 ❓ **Butuh bantuan?**
 Hubungi admin atau gunakan command di atas`
@@ -768,14 +1028,63 @@ func (h *PromoteCommandHandler) IsPromoteCommand(messageText string) bool {
 		".disablegroup",
 		".groupstatus",
 		".testgroup",
+		// Schedule Commands
+		".setschedule",
+		".setwindow",
+		".setinterval",
+		".forecast",
+		".setstrategy",
+		".setweight",
+		".setcategories",
+		".groupcategories",
+		// Campaign Commands
+		".createcampaign",
+		".listcampaigns",
+		".pausecampaign",
+		".resumecampaign",
+		".campaignreport",
+		// Scheduled Broadcast Commands
+		".schedule",
+		".listschedules",
+		".cancelschedule",
+		// Template Variable Commands
+		".setvar",
+		".listvars",
+		".delvar",
+		".setgroupvar",
+		".groupvars",
+		".delgroupvar",
+		// Locale Commands
+		".setlocale",
+		".translate",
+		".translations",
+		".deltranslation",
 		// Template Commands
 		".listtemplates",
 		".alltemplates",
 		".previewtemplate",
+		".statuspromo",
 		// Admin Commands
 		".addtemplate",
+		".newtemplate",
+		".canceltemplate",
+		".savetemplate",
 		".edittemplate",
 		".deletetemplate",
+		".templatehistory",
+		".diff",
+		".rollback",
+		".addvariant",
+		".variants",
+		".delvariant",
+		".absplit",
+		".validity",
+		".abreport",
+		".exporttemplates",
+		".importtemplates",
+		".linttemplates",
+		".attachmedia",
+		".detachmedia",
 		".templatestats",
 		".promotestats",
 		".activegroups",
@@ -816,6 +1125,9 @@ func (h *PromoteCommandHandler) HandlePromoteCommands(evt *events.Message, messa
 	case ".previewtemplate":
 		return h.HandlePreviewTemplateCommand(evt, args)
 
+	case ".statuspromo":
+		return h.HandleStatusPromoCommand(evt)
+
 	case ".help":
 		return h.HandleHelpCommand(evt)
 
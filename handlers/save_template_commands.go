@@ -0,0 +1,113 @@
+// Package handlers - Command admin untuk menyimpan pesan yang di-reply sebagai template
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// saveTemplateUsage adalah petunjuk penggunaan .savetemplate
+const saveTemplateUsage = `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* reply pesan dengan .savetemplate "Judul" kategori
+
+📋 *Contoh:*
+• Reply pesan promo: .savetemplate "Flash Sale Akhir Pekan" diskon
+• Reply foto ber-caption: .savetemplate "Promo Banner" produk
+
+💡 *Keterangan:*
+• Teks/caption pesan disimpan persis (baris baru dan formatting tetap)
+• Foto, video atau dokumen ikut disimpan sebagai media template
+• Gunakan tanda kutip untuk judul dengan spasi`
+
+// HandleSaveTemplateCommand menangani command .savetemplate "Judul" kategori
+// yang dikirim sebagai reply ke pesan teks atau media ber-caption.
+func (h *AdminCommandHandler) HandleSaveTemplateCommand(evt *events.Message, messageText string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	args := strings.Fields(messageText)
+	if len(args) < 3 {
+		return saveTemplateUsage
+	}
+
+	rest := strings.TrimSpace(messageText)
+	parts := h.parseQuotedArgs(strings.TrimSpace(rest[len(args[0]):]))
+	if len(parts) != 2 {
+		return saveTemplateUsage
+	}
+	title, category := parts[0], parts[1]
+
+	quoted := evt.Message.GetExtendedTextMessage().GetContextInfo().GetQuotedMessage()
+	if quoted == nil {
+		return `❌ *TIDAK ADA PESAN YANG DI-REPLY*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Kirim .savetemplate sebagai reply ke pesan promo yang ingin disimpan.`
+	}
+
+	content := extractMessageText(quoted)
+	media := mediaFromMessage(quoted)
+	if strings.TrimSpace(content) == "" {
+		return `❌ *PESAN TIDAK BERISI TEKS*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Pesan yang di-reply tidak punya teks atau caption.
+
+💡 Untuk media tanpa caption, buat template dulu lalu pasang media dengan *.attachmedia [ID]*.`
+	}
+
+	// Unduh media lebih dulu agar template tidak tersimpan setengah jadi
+	var data []byte
+	if media != nil {
+		if media.fileLength > services.MaxMediaSize {
+			return fmt.Sprintf(`❌ *MEDIA TERLALU BESAR*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Maksimal %d MB.`, services.MaxMediaSize>>20)
+		}
+
+		if h.client == nil {
+			return `❌ *CLIENT TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Client WhatsApp belum siap untuk mengunduh media.`
+		}
+
+		var err error
+		data, err = h.client.Download(context.Background(), media.message)
+		if err != nil {
+			h.logger.Errorf("Failed to download quoted media: %v", err)
+			return fmt.Sprintf(`❌ *GAGAL MENGUNDUH MEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Media yang terlalu lama mungkin sudah tidak tersedia, kirim ulang lalu reply lagi.`, err.Error())
+		}
+	}
+
+	template, err := h.templateService.CreateTemplate(title, content, category, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENYIMPAN TEMPLATE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	if media != nil {
+		withMedia, err := h.templateService.AttachTemplateMedia(template.ID, media.mediaType, data, media.mimetype, media.fileName)
+		if err != nil {
+			return h.templateCreatedMessage(template) + fmt.Sprintf(`
+
+⚠️ *Media gagal disimpan:* %s
+💡 Pasang ulang dengan reply media tersebut: *.attachmedia %d*`, err.Error(), template.ID)
+		}
+		template = withMedia
+	}
+
+	h.logger.Infof("Quoted message saved as template %d by %s", template.ID, evt.Info.Sender.User)
+	return h.templateCreatedMessage(template)
+}
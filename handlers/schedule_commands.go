@@ -0,0 +1,396 @@
+// Package handlers - Command admin untuk mengatur jadwal dan jam kirim auto promote per grup
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// adminOnlyMessage adalah response standar untuk command admin yang ditolak
+const adminOnlyMessage = `❌ *AKSES DITOLAK*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *TIDAK ADA IZIN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Command ini hanya bisa digunakan oleh admin.`
+
+// groupServiceUnavailableMessage adalah response saat service grup tidak tersedia
+const groupServiceUnavailableMessage = `❌ *SERVICE TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *KESALAHAN SISTEM*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Service untuk manajemen grup tidak dikonfigurasi.`
+
+// HandleSetScheduleCommand menangani command .setschedule [ID] [jadwal]
+func (h *AdminCommandHandler) HandleSetScheduleCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setschedule [ID] [jadwal]
+
+📋 *Contoh:*
+• .setschedule 3 every 3h
+• .setschedule 3 08:00,12:00,19:00
+• .setschedule 3 cron 0 9,18 * * 1-5
+• .setschedule 3 default
+
+💡 *Keterangan:*
+• *every* - interval sejak promosi terakhir
+• *HH:MM* - jam tetap setiap hari
+• *cron* - menit jam tanggal bulan hari
+• *default* - kembali ke interval global`
+	}
+
+	if h.groupManagerService == nil {
+		return groupServiceUnavailableMessage
+	}
+
+	groupID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID grup harus berupa angka.
+📝 Gunakan .listgroups untuk melihat ID.`
+	}
+
+	groupInfo, err := h.groupManagerService.GetGroupByID(groupID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GRUP TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+📝 Gunakan .listgroups untuk melihat ID yang valid.`, err.Error())
+	}
+
+	expr := strings.Join(args[2:], " ")
+	group, err := h.autoPromoteService.SetGroupSchedule(groupInfo.JID, expr)
+	if err != nil {
+		h.logger.Errorf("Failed to set schedule for group %d: %v", groupID, err)
+		return fmt.Sprintf(`❌ *JADWAL TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.setschedule* tanpa parameter untuk contoh format.`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *JADWAL GRUP DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *DETAIL JADWAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+👥 *Grup:* %s
+🆔 *ID:* %d
+🗓️ *Jadwal:* %s
+⏭️ *Promosi Berikutnya:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.groupstatus %d* untuk memantau grup.`,
+		groupInfo.Name, groupID,
+		h.autoPromoteService.GetScheduleDescription(group),
+		formatNextPromoteTime(h.autoPromoteService.NextPromoteTime(group)),
+		groupID)
+}
+
+// formatNextPromoteTime memformat waktu promosi berikutnya untuk ditampilkan
+func formatNextPromoteTime(next time.Time) string {
+	if next.IsZero() || !next.After(time.Now()) {
+		return "Segera (tick berikutnya)"
+	}
+	return next.Format("2006-01-02 15:04")
+}
+
+// HandleSetWindowCommand menangani command .setwindow [ID] [jam]
+func (h *AdminCommandHandler) HandleSetWindowCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setwindow [ID] [jam]
+
+📋 *Contoh:*
+• .setwindow 3 08:00-21:00
+• .setwindow 3 06:00-11:00,13:00-22:00
+• .setwindow 3 always
+• .setwindow 3 default
+
+💡 *Keterangan:*
+• Promosi di luar jam kirim *ditunda*, bukan dibatalkan
+• *always* - kirim 24 jam
+• *default* - kembali ke jam kirim global`
+	}
+
+	if h.groupManagerService == nil {
+		return groupServiceUnavailableMessage
+	}
+
+	groupID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID grup harus berupa angka.
+📝 Gunakan .listgroups untuk melihat ID.`
+	}
+
+	groupInfo, err := h.groupManagerService.GetGroupByID(groupID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GRUP TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+📝 Gunakan .listgroups untuk melihat ID yang valid.`, err.Error())
+	}
+
+	expr := strings.Join(args[2:], "")
+	group, err := h.autoPromoteService.SetGroupSendWindow(groupInfo.JID, expr)
+	if err != nil {
+		h.logger.Errorf("Failed to set send window for group %d: %v", groupID, err)
+		return fmt.Sprintf(`❌ *JAM KIRIM TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.setwindow* tanpa parameter untuk contoh format.`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *JAM KIRIM GRUP DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *DETAIL JAM KIRIM*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+👥 *Grup:* %s
+🆔 *ID:* %d
+🕘 *Jam Kirim:* %s
+⏭️ *Promosi Berikutnya:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.groupstatus %d* untuk memantau grup.`,
+		groupInfo.Name, groupID,
+		h.autoPromoteService.GetSendWindowDescription(group),
+		formatNextPromoteTime(h.autoPromoteService.NextPromoteTime(group)),
+		groupID)
+}
+
+// HandleSetIntervalCommand menangani command .setinterval [durasi]
+func (h *AdminCommandHandler) HandleSetIntervalCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 2 {
+		return fmt.Sprintf(`❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setinterval [durasi]
+
+📋 *Contoh:*
+• .setinterval 90m
+• .setinterval 2h
+• .setinterval 1h30m
+• .setinterval default
+
+⏰ *Interval Saat Ini:* %s
+📦 *Sumber:* %s
+
+💡 *Keterangan:*
+• Berlaku untuk grup tanpa jadwal sendiri
+• Tersimpan di database, tetap berlaku setelah restart
+• *default* - kembali ke AUTO_PROMOTE_INTERVAL`,
+			formatInterval(h.autoPromoteService.GetInterval()),
+			h.autoPromoteService.GetIntervalSource())
+	}
+
+	value := strings.Join(args[1:], "")
+
+	var interval time.Duration
+	if strings.ToLower(value) != "default" {
+		parsed, err := services.ParseInterval(value)
+		if err != nil {
+			return fmt.Sprintf(`❌ *INTERVAL TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.setinterval* tanpa parameter untuk contoh format.`, err.Error())
+		}
+		interval = parsed
+	}
+
+	if err := h.autoPromoteService.UpdateInterval(interval); err != nil {
+		h.logger.Errorf("Failed to update interval: %v", err)
+		return fmt.Sprintf(`❌ *GAGAL MENGUBAH INTERVAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *INTERVAL DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *DETAIL INTERVAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+⏰ *Interval:* %s
+📦 *Sumber:* %s
+🔄 *Scheduler:* Di-restart
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Grup dengan jadwal sendiri (.setschedule) tidak terpengaruh.`,
+		formatInterval(h.autoPromoteService.GetInterval()),
+		h.autoPromoteService.GetIntervalSource())
+}
+
+// formatInterval memformat durasi interval untuk ditampilkan
+func formatInterval(interval time.Duration) string {
+	hours := int(interval.Hours())
+	minutes := int(interval.Minutes()) % 60
+
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%d jam %d menit", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d jam", hours)
+	default:
+		return fmt.Sprintf("%d menit", minutes)
+	}
+}
+
+// defaultForecastHours dan maxForecastHours membatasi horizon .forecast
+const (
+	defaultForecastHours = 24
+	maxForecastHours     = 168
+)
+
+// maxForecastLines membatasi jumlah baris jadwal agar pesan tidak terlalu panjang
+const maxForecastLines = 40
+
+// HandleForecastCommand menangani command .forecast [jam] [ID grup tambahan]
+func (h *AdminCommandHandler) HandleForecastCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	hours := defaultForecastHours
+	if len(args) > 1 {
+		parsed, err := strconv.Atoi(args[1])
+		if err != nil || parsed < 1 || parsed > maxForecastHours {
+			return fmt.Sprintf(`❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .forecast [jam] [ID grup]
+
+📋 *Contoh:*
+• .forecast
+• .forecast 48
+• .forecast 24 3,7,9
+
+💡 *Keterangan:*
+• *jam* - 1 sampai %d (default %d)
+• *ID grup* - grup dari .listgroups yang disimulasikan seolah sudah diaktifkan`, maxForecastHours, defaultForecastHours)
+		}
+		hours = parsed
+	}
+
+	var extraJIDs []string
+	if len(args) > 2 {
+		if h.groupManagerService == nil {
+			return groupServiceUnavailableMessage
+		}
+
+		jids, _, _, errResponse := h.resolveGroupTargets(strings.Join(args[2:], ""))
+		if errResponse != "" {
+			return errResponse
+		}
+		extraJIDs = jids
+	}
+
+	forecast, err := h.autoPromoteService.Forecast(time.Duration(hours)*time.Hour, extraJIDs)
+	if err != nil {
+		h.logger.Errorf("Failed to build forecast: %v", err)
+		return `❌ *GAGAL MEMBUAT FORECAST*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	names := h.groupNamesByJID()
+	groupName := func(groupJID string) string {
+		if name, ok := names[groupJID]; ok {
+			return name
+		}
+		return h.formatGroupJID(groupJID)
+	}
+
+	var result strings.Builder
+	result.WriteString("🔮 *FORECAST PROMOSI*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("           *SIMULASI (TIDAK DIKIRIM)*\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	result.WriteString(fmt.Sprintf("🕐 *Rentang:* %s - %s\n",
+		forecast.From.Format("2006-01-02 15:04"), forecast.Until.Format("2006-01-02 15:04")))
+	result.WriteString(fmt.Sprintf("👥 *Grup:* %d", forecast.GroupCount))
+	if len(extraJIDs) > 0 {
+		result.WriteString(fmt.Sprintf(" (termasuk %d simulasi baru)", len(extraJIDs)))
+	}
+	result.WriteString(fmt.Sprintf("\n📝 *Template Aktif:* %d\n", forecast.TemplateCount))
+	result.WriteString(fmt.Sprintf("📤 *Perkiraan Pesan:* %d\n", len(forecast.Entries)))
+
+	if len(forecast.Warnings) > 0 {
+		result.WriteString("\n*⚠️ KONFLIK*\n")
+		for _, warning := range forecast.Warnings {
+			if warning.GroupJID != "" {
+				result.WriteString(fmt.Sprintf("• %s: %s\n", groupName(warning.GroupJID), warning.Message))
+			} else {
+				result.WriteString(fmt.Sprintf("• %s\n", warning.Message))
+			}
+		}
+	}
+
+	if len(forecast.Entries) > 0 {
+		result.WriteString("\n*📅 JADWAL*\n")
+
+		currentDay := ""
+		for i, entry := range forecast.Entries {
+			if i >= maxForecastLines {
+				result.WriteString(fmt.Sprintf("\n_... dan %d pengiriman lainnya_\n", len(forecast.Entries)-maxForecastLines))
+				break
+			}
+
+			day := entry.Time.Format("2006-01-02")
+			if day != currentDay {
+				currentDay = day
+				result.WriteString(fmt.Sprintf("\n🗓️ *%s*\n", day))
+			}
+
+			template := "❌ tidak ada template"
+			if entry.TemplateID != 0 {
+				template = fmt.Sprintf("[%d] %s", entry.TemplateID, entry.TemplateTitle)
+			}
+
+			result.WriteString(fmt.Sprintf("• %s %s → %s", entry.Time.Format("15:04"), groupName(entry.GroupJID), template))
+			if entry.Label != "" {
+				result.WriteString(fmt.Sprintf(" (%s)", entry.Label))
+			}
+			if entry.Deferred {
+				result.WriteString(fmt.Sprintf(" 🌙 ditunda dari %s", entry.DueAt.Format("15:04")))
+			}
+			if entry.Hypothetical {
+				result.WriteString(" 🆕")
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	result.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 Template rotasi dipilih acak, waktu aktual bisa bergeser karena jitter dan batas antrian.")
+
+	return result.String()
+}
@@ -0,0 +1,198 @@
+// Package handlers - Command admin untuk broadcast terjadwal sekali jalan
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// scheduledJobServiceUnavailableMessage adalah response saat service broadcast terjadwal tidak tersedia
+const scheduledJobServiceUnavailableMessage = `❌ *SERVICE TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *KESALAHAN SISTEM*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Service jadwal broadcast tidak dikonfigurasi.`
+
+// HandleScheduleCommand menangani command .schedule [templateID] [grup|all] [waktu]
+func (h *AdminCommandHandler) HandleScheduleCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.scheduledJobService == nil || h.groupManagerService == nil {
+		return scheduledJobServiceUnavailableMessage
+	}
+
+	if len(args) < 4 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:*
+.schedule [ID Template] [ID Grup|all] [Waktu]
+
+📋 *Contoh:*
+.schedule 5 1,3,7 2026-10-20 19:30
+.schedule 5 all besok 19:30
+.schedule 5 2 19:30
+
+💡 *Keterangan:*
+• *ID Grup* - dari .listgroups (pisah koma) atau *all* (semua grup aktif)
+• *Waktu* - YYYY-MM-DD HH:MM, HH:MM (jam terdekat), atau besok HH:MM
+• Broadcast dikirim sekali dan tidak mengubah jadwal rotasi grup`
+	}
+
+	templateID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TEMPLATE TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID template harus berupa angka.
+📝 Gunakan .listtemplates untuk melihat ID.`
+	}
+
+	groupJIDs, groupNames, targetAll, errResponse := h.resolveGroupTargets(args[2])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	runAt := strings.Join(args[3:], " ")
+	job, err := h.scheduledJobService.CreateJob(templateID, groupJIDs, targetAll, runAt, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMBUAT JADWAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	templateTitle := fmt.Sprintf("Template %d", templateID)
+	if template, err := h.templateService.GetTemplateByID(templateID); err == nil && template != nil {
+		templateTitle = template.Title
+	}
+
+	targetInfo := "Semua grup aktif"
+	if !targetAll {
+		targetInfo = strings.Join(groupNames, ", ")
+	}
+
+	return fmt.Sprintf(`✅ *BROADCAST DIJADWALKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID Jadwal:* %d
+📝 *Template:* [%d] %s
+👥 *Target:* %s
+🕐 *Waktu Kirim:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Batalkan dengan *.cancelschedule %d*`,
+		job.ID, templateID, templateTitle, targetInfo,
+		h.scheduledJobService.FormatTime(job.RunAt), job.ID)
+}
+
+// HandleListSchedulesCommand menangani command .listschedules [all]
+func (h *AdminCommandHandler) HandleListSchedulesCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.scheduledJobService == nil {
+		return scheduledJobServiceUnavailableMessage
+	}
+
+	includeFinished := len(args) > 1 && strings.EqualFold(args[1], "all")
+
+	jobs, err := h.scheduledJobService.GetJobs(includeFinished)
+	if err != nil {
+		h.logger.Errorf("Failed to get scheduled jobs: %v", err)
+		return `❌ *GAGAL MENGAMBIL JADWAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	if len(jobs) == 0 {
+		return `📭 *TIDAK ADA JADWAL BROADCAST*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.schedule* untuk menjadwalkan broadcast.
+📜 Gunakan *.listschedules all* untuk melihat riwayat.`
+	}
+
+	var names map[string]string
+	var result strings.Builder
+	result.WriteString("🗓️ *JADWAL BROADCAST*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	for _, job := range jobs {
+		target := "Semua grup aktif"
+		if !job.TargetAll {
+			if names == nil {
+				names = h.groupNamesByJID()
+			}
+
+			var groupNames []string
+			for _, groupJID := range job.GroupJIDs {
+				name, ok := names[groupJID]
+				if !ok {
+					name = h.formatGroupJID(groupJID)
+				}
+				groupNames = append(groupNames, name)
+			}
+			target = strings.Join(groupNames, ", ")
+		}
+
+		result.WriteString(fmt.Sprintf("🆔 *%d* - Template %d\n", job.ID, job.TemplateID))
+		result.WriteString(fmt.Sprintf("   🕐 %s | %s\n", h.scheduledJobService.FormatTime(job.RunAt), h.scheduledJobService.GetJobState(&job)))
+		result.WriteString(fmt.Sprintf("   👥 %s\n", target))
+		if job.ErrorMsg != nil {
+			result.WriteString(fmt.Sprintf("   ⚠️ %s\n", *job.ErrorMsg))
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 *.cancelschedule [ID]* untuk membatalkan")
+	if !includeFinished {
+		result.WriteString("\n📜 *.listschedules all* untuk melihat riwayat")
+	}
+
+	return result.String()
+}
+
+// HandleCancelScheduleCommand menangani command .cancelschedule [ID]
+func (h *AdminCommandHandler) HandleCancelScheduleCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if h.scheduledJobService == nil {
+		return scheduledJobServiceUnavailableMessage
+	}
+
+	if len(args) < 2 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .cancelschedule [ID]
+📋 Gunakan .listschedules untuk melihat ID.`
+	}
+
+	id, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID jadwal harus berupa angka.`
+	}
+
+	job, err := h.scheduledJobService.CancelJob(id)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMBATALKAN JADWAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *JADWAL DIBATALKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID Jadwal:* %d
+📝 *Template:* %d
+🕐 *Waktu Kirim:* %s`, job.ID, job.TemplateID, h.scheduledJobService.FormatTime(job.RunAt))
+}
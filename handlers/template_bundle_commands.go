@@ -0,0 +1,264 @@
+// Package handlers - Command admin untuk ekspor/impor template lewat file dokumen WhatsApp
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// maxImportItemsShown adalah jumlah template per bagian yang ditampilkan di ringkasan impor
+const maxImportItemsShown = 15
+
+// templateBundleMimetype adalah tipe file bundle template
+const templateBundleMimetype = "application/json"
+
+// HandleExportTemplatesCommand menangani command .exporttemplates [all|ID1,ID2]
+func (h *AdminCommandHandler) HandleExportTemplatesCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	var ids []int
+	if len(args) >= 2 && !strings.EqualFold(args[1], "all") {
+		for _, idStr := range strings.Split(strings.Join(args[1:], ""), ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(idStr))
+			if err != nil {
+				return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .exporttemplates [all|ID1,ID2]
+
+📋 *Contoh:*
+• .exporttemplates
+• .exporttemplates 1,5,8
+
+💡 File JSON dikirim sebagai dokumen. Kirim file tersebut ke bot lain dengan caption *.importtemplates* untuk memindahkan template.`
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	if h.client == nil {
+		return `❌ *CLIENT TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Client WhatsApp belum siap untuk mengirim file.`
+	}
+
+	data, count, err := h.templateService.ExportTemplates(ids)
+	if err != nil {
+		return fmt.Sprintf(`❌ *EKSPOR GAGAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	fileName := fmt.Sprintf("templates_%s.json", time.Now().Format("20060102_150405"))
+	caption := fmt.Sprintf("📦 %d template diekspor", count)
+	if err := h.sendDocument(evt, data, fileName, caption); err != nil {
+		h.logger.Errorf("Failed to send template export: %v", err)
+		return fmt.Sprintf(`❌ *GAGAL MENGIRIM FILE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *EKSPOR TEMPLATE BERHASIL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+📦 *Template:* %d
+📄 *File:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 *CARA IMPOR*
+• Teruskan file di atas ke nomor bot tujuan
+• Beri caption *.importtemplates* (atau reply file dengan command tersebut) untuk melihat ringkasan
+• Reply file dengan *.importtemplates apply* untuk menerapkan`, count, fileName)
+}
+
+// HandleImportTemplatesCommand menangani command .importtemplates [apply].
+// Command dikirim sebagai caption dokumen JSON atau sebagai reply ke dokumen tersebut.
+func (h *AdminCommandHandler) HandleImportTemplatesCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	apply := len(args) >= 2 && strings.EqualFold(args[1], "apply")
+
+	document := bundleDocument(evt.Message)
+	if document == nil {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* kirim file JSON hasil *.exporttemplates* dengan caption .importtemplates
+
+📋 *Contoh:*
+• Caption file: .importtemplates
+• Reply file: .importtemplates apply
+
+💡 *Keterangan:*
+• Tanpa *apply* hanya ditampilkan ringkasan (dry run), tidak ada yang diubah
+• Template dicocokkan berdasarkan judul
+• Konflik (judul ganda, variabel belum ada) dilewati`
+	}
+
+	if document.GetFileLength() > services.MaxTemplateBundleSize {
+		return fmt.Sprintf(`❌ *FILE TERLALU BESAR*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Maksimal %d MB.`, services.MaxTemplateBundleSize>>20)
+	}
+
+	if h.client == nil {
+		return `❌ *CLIENT TIDAK TERSEDIA*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Client WhatsApp belum siap untuk mengunduh file.`
+	}
+
+	data, err := h.client.Download(context.Background(), document)
+	if err != nil {
+		h.logger.Errorf("Failed to download template bundle: %v", err)
+		return fmt.Sprintf(`❌ *GAGAL MENGUNDUH FILE*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Kirim ulang file jika sudah terlalu lama.`, err.Error())
+	}
+
+	result, err := h.templateService.ImportTemplates(data, apply, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *IMPOR GAGAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return formatImportResult(result, document.GetFileName())
+}
+
+// sendDocument mengunggah data lalu mengirimnya sebagai dokumen ke chat asal command
+func (h *AdminCommandHandler) sendDocument(evt *events.Message, data []byte, fileName, caption string) error {
+	msg, err := services.NewMediaMessage(context.Background(), h.client, database.MediaDocument,
+		data, templateBundleMimetype, fileName, caption)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.client.SendMessage(context.Background(), evt.Info.Chat, msg); err != nil {
+		return fmt.Errorf("failed to send document: %v", err)
+	}
+	return nil
+}
+
+// bundleDocument mengambil dokumen dari pesan itu sendiri (caption) atau dari pesan yang di-reply
+func bundleDocument(msg *waProto.Message) *waProto.DocumentMessage {
+	if document := msg.GetDocumentMessage(); document != nil {
+		return document
+	}
+
+	quoted := msg.GetExtendedTextMessage().GetContextInfo().GetQuotedMessage()
+	if document := quoted.GetDocumentMessage(); document != nil {
+		return document
+	}
+	return quoted.GetDocumentWithCaptionMessage().GetMessage().GetDocumentMessage()
+}
+
+// formatImportResult menampilkan ringkasan dry run atau hasil impor
+func formatImportResult(result *services.ImportResult, fileName string) string {
+	var builder strings.Builder
+	if result.Applied {
+		builder.WriteString("📥 *IMPOR TEMPLATE SELESAI*\n\n")
+	} else {
+		builder.WriteString("🔍 *DRY RUN IMPOR TEMPLATE*\n\n")
+	}
+	if fileName != "" {
+		builder.WriteString(fmt.Sprintf("📄 *File:* %s\n", fileName))
+	}
+	builder.WriteString(fmt.Sprintf("📦 *Template di file:* %d\n\n", len(result.Items)))
+	builder.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	builder.WriteString(fmt.Sprintf("🆕 *Dibuat:* %d\n", result.Count(services.ImportCreate)))
+	builder.WriteString(fmt.Sprintf("✏️ *Diperbarui:* %d\n", result.Count(services.ImportUpdate)))
+	builder.WriteString(fmt.Sprintf("✅ *Tidak berubah:* %d\n", result.Count(services.ImportUnchanged)))
+	builder.WriteString(fmt.Sprintf("⚠️ *Konflik (dilewati):* %d\n", result.Count(services.ImportConflict)))
+	if result.Failed > 0 {
+		builder.WriteString(fmt.Sprintf("❌ *Gagal diterapkan:* %d\n", result.Failed))
+	}
+	builder.WriteString("\n")
+
+	writeSection := func(action, title string, line func(item services.ImportItem) string) {
+		var lines []string
+		for _, item := range result.Items {
+			if item.Action == action {
+				lines = append(lines, line(item))
+			}
+		}
+		if len(lines) == 0 {
+			return
+		}
+
+		builder.WriteString(title + "\n")
+		for i, text := range lines {
+			if i == maxImportItemsShown {
+				builder.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(lines)-maxImportItemsShown))
+				break
+			}
+			builder.WriteString(text + "\n")
+		}
+		builder.WriteString("\n")
+	}
+
+	writeSection(services.ImportCreate, "🆕 *BARU*", func(item services.ImportItem) string {
+		line := "• " + item.Title
+		if item.ExistingID > 0 {
+			line += fmt.Sprintf(" (ID %d)", item.ExistingID)
+		}
+		if item.Reason != "" {
+			line += " ❌ " + item.Reason
+		}
+		return line
+	})
+	writeSection(services.ImportUpdate, "✏️ *DIPERBARUI*", func(item services.ImportItem) string {
+		line := fmt.Sprintf("• ID %d - %s: %s", item.ExistingID, item.Title, strings.Join(item.Changes, ", "))
+		if item.Reason != "" {
+			line += " ❌ " + item.Reason
+		}
+		return line
+	})
+	writeSection(services.ImportConflict, "⚠️ *KONFLIK*", func(item services.ImportItem) string {
+		return fmt.Sprintf("• %s - %s", item.Title, item.Reason)
+	})
+
+	var mediaNotes []string
+	for _, item := range result.Items {
+		if item.MediaNote != "" && item.Action != services.ImportConflict {
+			mediaNotes = append(mediaNotes, fmt.Sprintf("• %s - %s", item.Title, item.MediaNote))
+		}
+	}
+	if len(mediaNotes) > 0 {
+		builder.WriteString("📎 *MEDIA TIDAK DIIMPOR*\n")
+		for i, text := range mediaNotes {
+			if i == maxImportItemsShown {
+				builder.WriteString(fmt.Sprintf("... dan %d lainnya\n", len(mediaNotes)-maxImportItemsShown))
+				break
+			}
+			builder.WriteString(text + "\n")
+		}
+		builder.WriteString("💡 Pasang manual dengan *.attachmedia [ID]* setelah impor.\n\n")
+	}
+
+	builder.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	if result.Applied {
+		builder.WriteString("💡 Perubahan tercatat di riwayat, gunakan *.templatehistory [ID]* dan *.rollback* jika perlu.")
+	} else {
+		builder.WriteString("💡 Belum ada yang diubah. Reply file yang sama dengan *.importtemplates apply* untuk menerapkan.")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,222 @@
+// Package handlers - Pembuatan template lewat percakapan (.newtemplate)
+// agar konten multi-baris dan formatting WhatsApp tersimpan apa adanya
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// templateDraftTimeout adalah batas waktu menunggu balasan admin sebelum sesi dibatalkan
+const templateDraftTimeout = 10 * time.Minute
+
+// Tahapan sesi .newtemplate
+const (
+	draftStepTitle = iota
+	draftStepCategory
+	draftStepContent
+)
+
+// templateDraft menyimpan isian sementara satu sesi .newtemplate
+type templateDraft struct {
+	step     int
+	title    string
+	category string
+	chat     types.JID
+	timer    *time.Timer
+}
+
+// HandleNewTemplateCommand menangani command .newtemplate.
+// Bot menanyakan judul, kategori, lalu mengambil pesan berikutnya apa adanya sebagai konten.
+func (h *AdminCommandHandler) HandleNewTemplateCommand(evt *events.Message) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	draft := &templateDraft{step: draftStepTitle, chat: evt.Info.Chat}
+	replaced := h.startTemplateDraft(evt.Info.Sender.User, draft)
+
+	note := ""
+	if replaced {
+		note = "♻️ Sesi sebelumnya dibatalkan dan diganti sesi baru.\n\n"
+	}
+
+	h.logger.Infof("Template draft session started by %s", evt.Info.Sender.User)
+	return fmt.Sprintf(`📝 *BUAT TEMPLATE BARU*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+%s*Langkah 1/3* - Kirim *judul* template.
+
+📋 *Contoh:* Flash Sale Akhir Pekan
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+⏳ Sesi berakhir jika tidak ada balasan %d menit
+⚠️ Selama sesi, semua pesan (termasuk yang diawali . atau /) dianggap isian template
+❌ *.canceltemplate* - Batalkan`, note, int(templateDraftTimeout.Minutes()))
+}
+
+// HandleCancelTemplateCommand menangani command .canceltemplate
+func (h *AdminCommandHandler) HandleCancelTemplateCommand(evt *events.Message) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if !h.stopTemplateDraft(evt.Info.Sender.User) {
+		return `ℹ️ *TIDAK ADA SESI AKTIF*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Mulai membuat template dengan *.newtemplate*`
+	}
+
+	h.logger.Infof("Template draft session cancelled by %s", evt.Info.Sender.User)
+	return `❌ *PEMBUATAN TEMPLATE DIBATALKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 Tidak ada template yang disimpan.`
+}
+
+// HasTemplateDraft mengecek apakah admin sedang dalam sesi .newtemplate
+func (h *AdminCommandHandler) HasTemplateDraft(userNumber string) bool {
+	h.draftMutex.Lock()
+	defer h.draftMutex.Unlock()
+	_, ok := h.drafts[userNumber]
+	return ok
+}
+
+// HandleTemplateDraftMessage memproses balasan admin selama sesi .newtemplate (semua pesan
+// kecuali .canceltemplate dan .newtemplate, termasuk yang diawali . atau /).
+// messageText dipakai apa adanya sehingga baris baru dan formatting tidak hilang.
+func (h *AdminCommandHandler) HandleTemplateDraftMessage(evt *events.Message, messageText string) string {
+	sender := evt.Info.Sender.User
+
+	h.draftMutex.Lock()
+	defer h.draftMutex.Unlock()
+
+	draft, ok := h.drafts[sender]
+	if !ok {
+		return ""
+	}
+	h.resetDraftTimer(sender, draft)
+
+	switch draft.step {
+	case draftStepTitle:
+		title := strings.TrimSpace(messageText)
+		if title == "" || strings.Contains(title, "\n") || len(title) > 100 {
+			return `❌ *JUDUL TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Judul harus satu baris dan maksimal 100 karakter.
+
+📝 Kirim ulang *judul* template.`
+		}
+		draft.title = title
+		draft.step = draftStepCategory
+		return fmt.Sprintf(`✅ *Judul:* %s
+
+*Langkah 2/3* - Kirim *kategori* template.
+
+📋 *Contoh:* produk, diskon, testimoni, flashsale`, title)
+
+	case draftStepCategory:
+		category := strings.ToLower(strings.TrimSpace(messageText))
+		if category == "" || strings.ContainsAny(category, " \n\t") || len(category) > 50 {
+			return `❌ *KATEGORI TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Kategori harus satu kata dan maksimal 50 karakter.
+
+📝 Kirim ulang *kategori* template.`
+		}
+		draft.category = category
+		draft.step = draftStepContent
+		return fmt.Sprintf(`✅ *Kategori:* %s
+
+*Langkah 3/3* - Kirim *konten* template dalam satu pesan.
+
+💡 Baris baru, emoji, *bold*, _italic_ dan variabel seperti {DAY} disimpan persis seperti yang dikirim.`, category)
+
+	default:
+		template, err := h.templateService.CreateTemplate(draft.title, messageText, draft.category, sender)
+		if err != nil {
+			return fmt.Sprintf(`❌ *KONTEN TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+📝 Perbaiki lalu kirim ulang *konten* template, atau *.canceltemplate* untuk batal.`, err.Error())
+		}
+
+		draft.timer.Stop()
+		delete(h.drafts, sender)
+		h.logger.Infof("Template draft session by %s saved as template %d", sender, template.ID)
+		return h.templateCreatedMessage(template)
+	}
+}
+
+// startTemplateDraft menyimpan sesi baru untuk admin, mengganti sesi lama jika ada
+func (h *AdminCommandHandler) startTemplateDraft(userNumber string, draft *templateDraft) bool {
+	h.draftMutex.Lock()
+	defer h.draftMutex.Unlock()
+
+	previous, replaced := h.drafts[userNumber]
+	if replaced {
+		previous.timer.Stop()
+	}
+
+	h.drafts[userNumber] = draft
+	h.resetDraftTimer(userNumber, draft)
+	return replaced
+}
+
+// stopTemplateDraft menghapus sesi admin, false jika tidak ada sesi
+func (h *AdminCommandHandler) stopTemplateDraft(userNumber string) bool {
+	h.draftMutex.Lock()
+	defer h.draftMutex.Unlock()
+
+	draft, ok := h.drafts[userNumber]
+	if !ok {
+		return false
+	}
+
+	draft.timer.Stop()
+	delete(h.drafts, userNumber)
+	return true
+}
+
+// resetDraftTimer memulai ulang hitungan timeout sesi (draftMutex harus sudah dikunci)
+func (h *AdminCommandHandler) resetDraftTimer(userNumber string, draft *templateDraft) {
+	if draft.timer != nil {
+		draft.timer.Stop()
+	}
+	draft.timer = time.AfterFunc(templateDraftTimeout, func() {
+		h.expireTemplateDraft(userNumber, draft)
+	})
+}
+
+// expireTemplateDraft membatalkan sesi yang tidak dibalas dan memberi tahu admin
+func (h *AdminCommandHandler) expireTemplateDraft(userNumber string, draft *templateDraft) {
+	h.draftMutex.Lock()
+	if h.drafts[userNumber] != draft {
+		h.draftMutex.Unlock()
+		return
+	}
+	delete(h.drafts, userNumber)
+	h.draftMutex.Unlock()
+
+	h.logger.Infof("Template draft session by %s timed out", userNumber)
+	if h.client == nil {
+		return
+	}
+
+	text := fmt.Sprintf(`⏳ *SESI .newtemplate BERAKHIR*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Tidak ada balasan selama %d menit, template tidak disimpan.
+
+💡 Ketik *.newtemplate* untuk memulai lagi.`, int(templateDraftTimeout.Minutes()))
+
+	msg := &waProto.Message{Conversation: &text}
+	if _, err := h.client.SendMessage(context.Background(), draft.chat, msg); err != nil {
+		h.logger.Errorf("Failed to send template draft timeout notice: %v", err)
+	}
+}
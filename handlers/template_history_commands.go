@@ -0,0 +1,305 @@
+// Package handlers - Command admin untuk riwayat template, diff dan rollback
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// maxHistoryEntries adalah jumlah revisi terbaru yang ditampilkan .templatehistory
+const maxHistoryEntries = 15
+
+// diffContextLines adalah jumlah baris sama yang ditampilkan di sekitar perubahan
+const diffContextLines = 2
+
+// templateHistoryUsage adalah cara penggunaan .templatehistory
+const templateHistoryUsage = `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .templatehistory [ID]
+
+📋 *Contoh:*
+• .templatehistory 5
+
+💡 Template yang sudah dihapus juga bisa dilihat riwayatnya.`
+
+// HandleTemplateHistoryCommand menangani command .templatehistory [ID]
+func (h *AdminCommandHandler) HandleTemplateHistoryCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 2 {
+		return templateHistoryUsage
+	}
+
+	templateID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return templateHistoryUsage
+	}
+
+	revisions, err := h.templateService.GetTemplateHistory(templateID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGAMBIL RIWAYAT*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	if len(revisions) == 0 {
+		return fmt.Sprintf(`📭 *BELUM ADA RIWAYAT*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+ℹ️ Template ID %d belum pernah diubah sejak riwayat mulai dicatat.
+
+💡 Riwayat tercatat otomatis saat template diedit, diubah status/bobotnya, atau dihapus.`, templateID)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🕘 *RIWAYAT TEMPLATE ID %d*\n\n", templateID))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if revisions[0].Action == database.RevisionDelete {
+		result.WriteString("🗑️ *Template sudah dihapus*\n\n")
+	}
+
+	for i, rev := range revisions {
+		if i == maxHistoryEntries {
+			result.WriteString(fmt.Sprintf("... dan %d revisi lebih lama\n\n", len(revisions)-maxHistoryEntries))
+			break
+		}
+
+		result.WriteString(fmt.Sprintf("📌 *Rev %d* - %s\n", rev.Revision, revisionActionLabel(rev.Action)))
+		result.WriteString(fmt.Sprintf("   👤 %s | 🕒 %s\n", formatRevisionAuthor(rev.Author), rev.CreatedAt.Format("02 Jan 2006, 15:04")))
+		result.WriteString(fmt.Sprintf("   🏷️ %s (%s)\n\n", rev.Title, rev.Category))
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("💡 *.diff %d [rev]* - Bandingkan dengan versi sekarang\n", templateID))
+	result.WriteString(fmt.Sprintf("♻️ *.rollback %d [rev]* - Kembalikan ke revisi tersebut", templateID))
+
+	return result.String()
+}
+
+// HandleDiffTemplateCommand menangani command .diff [ID] [revisi]
+func (h *AdminCommandHandler) HandleDiffTemplateCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, revision, ok := parseRevisionArgs(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .diff [ID] [revisi]
+
+📋 *Contoh:*
+• .diff 5 2
+
+💡 Lihat nomor revisi dengan *.templatehistory [ID]*.
+Baris *-* hanya ada di revisi lama, baris *+* hanya ada di versi sekarang.`
+	}
+
+	diff, err := h.templateService.DiffTemplateRevision(templateID, revision)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MEMBANDINGKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	target := "versi sekarang"
+	if diff.Deleted {
+		target = fmt.Sprintf("rev %d (terakhir sebelum dihapus)", diff.To.Revision)
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("🔍 *DIFF TEMPLATE ID %d*\n\n", templateID))
+	result.WriteString(fmt.Sprintf("📌 Rev %d ➜ %s\n", revision, target))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	from, to := diff.From, diff.To
+	fieldChanged := false
+	writeField := func(label, before, after string) {
+		if before != after {
+			result.WriteString(fmt.Sprintf("%s: %s ➜ %s\n", label, before, after))
+			fieldChanged = true
+		}
+	}
+	writeField("🏷️ *Judul*", from.Title, to.Title)
+	writeField("📂 *Kategori*", from.Category, to.Category)
+	writeField("📈 *Status*", getTemplateStatusText(from.IsActive), getTemplateStatusText(to.IsActive))
+	writeField("⚖️ *Bobot*", strconv.Itoa(from.Weight), strconv.Itoa(to.Weight))
+	if fieldChanged {
+		result.WriteString("\n")
+	}
+
+	if from.Content == to.Content {
+		result.WriteString("✅ Konten sama, tidak ada perubahan.\n\n")
+	} else {
+		result.WriteString("```\n")
+		result.WriteString(formatDiffLines(diff.Lines))
+		result.WriteString("```\n\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString(fmt.Sprintf("♻️ *.rollback %d %d* - Kembalikan ke revisi ini", templateID, revision))
+
+	return result.String()
+}
+
+// HandleRollbackTemplateCommand menangani command .rollback [ID] [revisi]
+func (h *AdminCommandHandler) HandleRollbackTemplateCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, revision, ok := parseRevisionArgs(args)
+	if !ok {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .rollback [ID] [revisi]
+
+📋 *Contoh:*
+• .rollback 5 2
+
+💡 Judul, kategori, konten, status, bobot dan masa berlaku dikembalikan seperti di revisi tersebut.
+Template yang sudah dihapus dipulihkan dengan ID yang sama beserta medianya.`
+	}
+
+	rollback, err := h.templateService.RollbackTemplate(templateID, revision, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *ROLLBACK GAGAL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Gunakan *.templatehistory %d* untuk melihat revisi yang tersedia.`, err.Error(), templateID)
+	}
+
+	template := rollback.Template
+	title := "♻️ *TEMPLATE DIKEMBALIKAN*"
+	if rollback.Restored {
+		title = "♻️ *TEMPLATE DIPULIHKAN*"
+	}
+
+	details := ""
+	if template.MediaType != "" {
+		details += fmt.Sprintf("📎 *Media:* %s\n", services.MediaTypeLabel(template.MediaType))
+	}
+	if validity := services.DescribeValidity(template, h.autoPromoteService.GetTimezone()); validity != "" {
+		details += fmt.Sprintf("🗓️ *Berlaku:* %s\n", validity)
+	}
+	for _, note := range rollback.Notes {
+		details += fmt.Sprintf("⚠️ %s\n", note)
+	}
+
+	return fmt.Sprintf(`%s
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID:* %d
+📌 *Dari Revisi:* %d
+🏷️ *Judul:* %s
+📂 *Kategori:* %s
+📈 *Status:* %s
+⚖️ *Bobot:* %d
+%s
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Rollback juga tercatat di riwayat, jadi bisa dibatalkan dengan *.rollback* lagi.
+• *.previewtemplate %d* - Preview template`,
+		title, template.ID, revision, template.Title, template.Category,
+		getTemplateStatusText(template.IsActive), template.Weight, details, template.ID)
+}
+
+// parseRevisionArgs membaca ID template dan nomor revisi dari argumen command
+func parseRevisionArgs(args []string) (int, int, bool) {
+	if len(args) < 3 {
+		return 0, 0, false
+	}
+
+	templateID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	revision, err := strconv.Atoi(args[2])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return templateID, revision, true
+}
+
+// formatDiffLines menampilkan baris yang berubah beserta beberapa baris di sekitarnya.
+// Baris sama yang jauh dari perubahan diringkas menjadi "...".
+func formatDiffLines(lines []services.DiffLine) string {
+	show := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == ' ' {
+			continue
+		}
+		for j := max(0, i-diffContextLines); j <= min(len(lines)-1, i+diffContextLines); j++ {
+			show[j] = true
+		}
+	}
+
+	var result strings.Builder
+	skipped := false
+	for i, line := range lines {
+		if !show[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			result.WriteString("  ...\n")
+			skipped = false
+		}
+		result.WriteString(fmt.Sprintf("%c %s\n", line.Op, line.Text))
+	}
+	if skipped {
+		result.WriteString("  ...\n")
+	}
+
+	return result.String()
+}
+
+// revisionActionLabel menerjemahkan jenis perubahan revisi untuk ditampilkan
+func revisionActionLabel(action string) string {
+	switch action {
+	case database.RevisionBaseline:
+		return "📦 Versi awal"
+	case database.RevisionCreate:
+		return "🆕 Dibuat"
+	case database.RevisionEdit:
+		return "✏️ Diedit"
+	case database.RevisionStatus:
+		return "🔄 Status diubah"
+	case database.RevisionWeight:
+		return "⚖️ Bobot diubah"
+	case database.RevisionValidity:
+		return "🗓️ Masa berlaku diubah"
+	case database.RevisionDelete:
+		return "🗑️ Dihapus"
+	case database.RevisionRollback:
+		return "♻️ Rollback"
+	case database.RevisionRestore:
+		return "♻️ Dipulihkan"
+	default:
+		return action
+	}
+}
+
+// formatRevisionAuthor menampilkan pengubah revisi (kosong untuk versi awal)
+func formatRevisionAuthor(author string) string {
+	if author == "" {
+		return "-"
+	}
+	return author
+}
@@ -0,0 +1,148 @@
+// Package handlers - Command admin untuk mengatur strategi pemilihan template
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// HandleSetStrategyCommand menangani command .setstrategy [ID|global] [strategi]
+func (h *AdminCommandHandler) HandleSetStrategyCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setstrategy [ID|global] [strategi]
+
+📋 *Contoh:*
+• .setstrategy global norepeat
+• .setstrategy 3 roundrobin
+• .setstrategy 3 norepeat:5
+• .setstrategy 3 default
+
+💡 *Strategi:*
+• *random* - acak sesuai bobot template (.setweight)
+• *roundrobin* - bergiliran sesuai urutan ID
+• *leastrecent* - template yang paling lama tidak dikirim ke grup
+• *norepeat:N* - acak, tapi tidak mengulang N kiriman terakhir (default 3)
+• *default* - kembali ke strategi global/konfigurasi`
+	}
+
+	selector := h.autoPromoteService.GetTemplateSelector()
+	expr := strings.Join(args[2:], "")
+
+	if strings.EqualFold(args[1], "global") {
+		if err := selector.UpdateGlobalStrategy(expr); err != nil {
+			h.logger.Errorf("Failed to update global template strategy: %v", err)
+			return fmt.Sprintf(`❌ *STRATEGI TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.setstrategy* tanpa parameter untuk contoh format.`, err.Error())
+		}
+
+		return fmt.Sprintf(`✅ *STRATEGI GLOBAL DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🎲 *Strategi:* %s
+📦 *Sumber:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Grup dengan strategi sendiri tidak terpengaruh.`,
+			selector.GetGlobalStrategy().String(), selector.GetStrategySource())
+	}
+
+	if h.groupManagerService == nil {
+		return groupServiceUnavailableMessage
+	}
+
+	groupID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID grup harus berupa angka atau *global*.
+📝 Gunakan .listgroups untuk melihat ID.`
+	}
+
+	groupInfo, err := h.groupManagerService.GetGroupByID(groupID)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GRUP TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+📝 Gunakan .listgroups untuk melihat ID yang valid.`, err.Error())
+	}
+
+	group, err := selector.SetGroupStrategy(groupInfo.JID, expr)
+	if err != nil {
+		h.logger.Errorf("Failed to set template strategy for group %d: %v", groupID, err)
+		return fmt.Sprintf(`❌ *STRATEGI TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+
+💡 Ketik *.setstrategy* tanpa parameter untuk contoh format.`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *STRATEGI GRUP DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+👥 *Grup:* %s
+🆔 *ID:* %d
+🎲 *Strategi:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.forecast* untuk melihat urutan template berikutnya.`,
+		groupInfo.Name, groupID, selector.GetStrategyDescription(group))
+}
+
+// HandleSetWeightCommand menangani command .setweight [ID Template] [bobot]
+func (h *AdminCommandHandler) HandleSetWeightCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setweight [ID Template] [bobot]
+
+📋 *Contoh:* .setweight 4 3
+
+💡 Bobot 1-100. Template berbobot 3 terpilih ±3x lebih sering dari bobot 1 pada strategi *random* dan *norepeat*.`
+	}
+
+	templateID, err := strconv.Atoi(args[1])
+	if err != nil {
+		return `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID template harus berupa angka.`
+	}
+
+	weight, err := strconv.Atoi(args[2])
+	if err != nil {
+		return `❌ *BOBOT TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Bobot harus berupa angka 1-100.`
+	}
+
+	template, err := h.templateService.SetTemplateWeight(templateID, weight, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGUBAH BOBOT*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *BOBOT TEMPLATE DIPERBARUI*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *ID:* %d
+📝 *Judul:* %s
+⚖️ *Bobot:* %d`, template.ID, template.Title, template.Weight)
+}
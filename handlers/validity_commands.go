@@ -0,0 +1,123 @@
+// Package handlers - Command admin untuk masa berlaku template (flash sale, promo musiman)
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// validityUsage adalah petunjuk penggunaan .validity
+const validityUsage = `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .validity [ID] [from|until|days|hours|clear] [nilai]
+
+📋 *Contoh:*
+• .validity 5 - Lihat masa berlaku
+• .validity 5 from 2026-11-01 08:00
+• .validity 5 until 2026-11-11 23:59
+• .validity 5 days sen-jum
+• .validity 5 hours 08:00-21:00
+• .validity 5 until off
+• .validity 5 clear
+
+💡 *Keterangan:*
+• Tanggal tanpa jam: from = 00:00, until = 23:59
+• Template dinonaktifkan otomatis setelah *until* lewat dan admin diberi tahu
+• Di luar hari/jam tayang, template dilewati rotasi tanpa dinonaktifkan`
+
+// HandleValidityCommand menangani command .validity [ID] [bagian] [nilai]
+func (h *AdminCommandHandler) HandleValidityCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	templateID, ok := parseVariantTemplateID(args)
+	if !ok {
+		return validityUsage
+	}
+
+	location := h.autoPromoteService.GetTimezone()
+
+	if len(args) == 2 {
+		template, err := h.templateService.GetTemplateByID(templateID)
+		if err != nil {
+			return fmt.Sprintf(`❌ *TEMPLATE TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+		}
+		return formatTemplateValidity(template, location, "🗓️ *MASA BERLAKU TEMPLATE*")
+	}
+
+	field := strings.ToLower(args[2])
+	value := strings.Join(args[3:], " ")
+	if field != services.ValidityClear && len(args) < 4 {
+		return validityUsage
+	}
+
+	template, reactivated, err := h.templateService.SetTemplateValidity(templateID, field, value, location, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGATUR MASA BERLAKU*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	result := formatTemplateValidity(template, location, "✅ *MASA BERLAKU DIPERBARUI*")
+	if reactivated {
+		result += "\n\n🔄 Template diaktifkan kembali karena masa berlakunya diperpanjang."
+	}
+	return result
+}
+
+// formatTemplateValidity menampilkan detail masa berlaku satu template
+func formatTemplateValidity(template *database.PromoteTemplate, location *time.Location, title string) string {
+	formatTime := func(t *time.Time, empty string) string {
+		if t == nil {
+			return empty
+		}
+		return t.In(location).Format("2006-01-02 15:04")
+	}
+
+	days := template.ValidDays
+	if days == "" {
+		days = "Setiap hari"
+	}
+	hours := template.ValidHours
+	if hours == "" {
+		hours = "24 jam"
+	}
+
+	state := "🟢 Sedang tayang"
+	now := time.Now().In(location)
+	switch {
+	case !template.IsActive:
+		state = "⚪ Nonaktif"
+	case services.TemplateExpired(template, now):
+		state = "🔴 Kedaluwarsa"
+	case !services.TemplateValidAt(template, now):
+		state = "🟡 Di luar masa tayang"
+	}
+
+	return fmt.Sprintf(`%s
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🆔 *Template:* %d - %s
+📅 *Mulai:* %s
+⏳ *Berakhir:* %s
+📆 *Hari:* %s
+🕐 *Jam:* %s
+📈 *Status:* %s
+
+🌍 Timezone: %s`,
+		title, template.ID, template.Title,
+		formatTime(template.ValidFrom, "Sejak dibuat"),
+		formatTime(template.ValidUntil, "Tanpa batas"),
+		days, hours, state, location.String())
+}
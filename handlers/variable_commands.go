@@ -0,0 +1,288 @@
+// Package handlers - Command admin untuk variabel template (global dan per grup)
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/services"
+)
+
+// HandleSetVarCommand menangani command .setvar [NAMA] [nilai]
+func (h *AdminCommandHandler) HandleSetVarCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setvar [NAMA] [nilai]
+
+📋 *Contoh:*
+• .setvar STORE_WA 6287786388052
+• .setvar STORE_TG grnstoreofficial_bot
+• .setvar JAM_BUKA 01:00 - 23:00 WIB
+
+💡 Pakai di template sebagai *{STORE_WA}*. Perubahan langsung berlaku saat promosi berikutnya dikirim.`
+	}
+
+	renderer := h.templateService.GetRenderer()
+	value := strings.Join(args[2:], " ")
+
+	name, err := renderer.SetVariable(args[1], value, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENYIMPAN VARIABEL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	usage := "Belum dipakai template manapun"
+	if using, err := renderer.TemplatesUsingVariable(name); err == nil && len(using) > 0 {
+		usage = fmt.Sprintf("Dipakai %d template", len(using))
+	}
+
+	return fmt.Sprintf(`✅ *VARIABEL DISIMPAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+🔤 *Nama:* {%s}
+📝 *Nilai:* %s
+📋 *Pemakaian:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.listvars* untuk melihat semua variabel.`, name, value, usage)
+}
+
+// HandleListVarsCommand menangani command .listvars
+func (h *AdminCommandHandler) HandleListVarsCommand(evt *events.Message) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	renderer := h.templateService.GetRenderer()
+	variables, err := renderer.GetVariables()
+	if err != nil {
+		h.logger.Errorf("Failed to get template variables: %v", err)
+		return `❌ *GAGAL MENGAMBIL VARIABEL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	var result strings.Builder
+	result.WriteString("🔤 *VARIABEL TEMPLATE*\n\n")
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	if len(variables) == 0 {
+		result.WriteString("📭 Belum ada variabel global.\n\n")
+	}
+	for _, variable := range variables {
+		result.WriteString(fmt.Sprintf("• *{%s}* = %s\n", variable.Name, variable.Value))
+		result.WriteString(fmt.Sprintf("   🕐 %s oleh %s\n", variable.UpdatedAt.Local().Format("2006-01-02 15:04"), variable.UpdatedBy))
+	}
+	if len(variables) > 0 {
+		result.WriteString("\n")
+	}
+
+	builtins := renderer.BuiltinVariableNames()
+	for i, name := range builtins {
+		builtins[i] = "{" + name + "}"
+	}
+	result.WriteString(fmt.Sprintf("⚙️ *Bawaan:* %s\n\n", strings.Join(builtins, ", ")))
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 *.setvar [NAMA] [nilai]* untuk menambah/mengubah\n")
+	result.WriteString("🗑️ *.delvar [NAMA]* untuk menghapus")
+
+	return result.String()
+}
+
+// HandleDelVarCommand menangani command .delvar [NAMA]
+func (h *AdminCommandHandler) HandleDelVarCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 2 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .delvar [NAMA]
+
+📋 *Contoh:* .delvar STORE_WA`
+	}
+
+	name, err := h.templateService.GetRenderer().DeleteVariable(args[1])
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGHAPUS VARIABEL*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *VARIABEL DIHAPUS*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🔤 {%s} tidak lagi tersedia untuk template.`, name)
+}
+
+// HandleSetGroupVarCommand menangani command .setgroupvar [ID Grup] [NAMA] [nilai]
+func (h *AdminCommandHandler) HandleSetGroupVarCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 4 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+           *CARA PENGGUNAAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .setgroupvar [ID Grup] [NAMA] [nilai]
+
+📋 *Contoh:*
+• .setgroupvar 3 RESELLER_WA 6281234567890
+• .setgroupvar 3 STORE_WA 6289876543210
+
+💡 Variabel grup menimpa variabel global dengan nama yang sama, hanya untuk grup tersebut.`
+	}
+
+	groupInfo, errResponse := h.groupFromArg(args[1])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	value := strings.Join(args[3:], " ")
+	name, err := h.templateService.GetRenderer().SetGroupVariable(groupInfo.JID, args[2], value, evt.Info.Sender.User)
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENYIMPAN VARIABEL GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *VARIABEL GRUP DISIMPAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+
+👥 *Grup:* %s
+🔤 *Nama:* {%s}
+📝 *Nilai:* %s
+
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+💡 Gunakan *.groupvars %s* untuk melihat semua variabel grup ini.`, groupInfo.Name, name, value, args[1])
+}
+
+// HandleGroupVarsCommand menangani command .groupvars [ID Grup]
+func (h *AdminCommandHandler) HandleGroupVarsCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 2 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .groupvars [ID Grup]
+
+📋 *Contoh:* .groupvars 3`
+	}
+
+	groupInfo, errResponse := h.groupFromArg(args[1])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	variables, err := h.templateService.GetRenderer().GetGroupVariables(groupInfo.JID)
+	if err != nil {
+		h.logger.Errorf("Failed to get group variables: %v", err)
+		return `❌ *GAGAL MENGAMBIL VARIABEL GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 Terjadi kesalahan saat membaca database.`
+	}
+
+	var result strings.Builder
+	result.WriteString("🔤 *VARIABEL GRUP*\n\n")
+	result.WriteString(fmt.Sprintf("👥 *Grup:* %s\n\n", groupInfo.Name))
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+
+	result.WriteString("⚙️ *Dari info grup:*\n")
+	result.WriteString(fmt.Sprintf("• *{GROUP_NAME}* = %s\n", groupInfo.Name))
+	result.WriteString(fmt.Sprintf("• *{MEMBER_COUNT}* = %d\n", groupInfo.MemberCount))
+	if groupInfo.Description != "" {
+		result.WriteString("• *{GROUP_DESC}* = (deskripsi grup)\n")
+	} else {
+		result.WriteString("• *{GROUP_DESC}* = (kosong)\n")
+	}
+	result.WriteString("\n")
+
+	if len(variables) == 0 {
+		result.WriteString("📭 Belum ada variabel khusus grup ini.\n\n")
+	} else {
+		result.WriteString("🏷️ *Khusus grup ini:*\n")
+		for _, variable := range variables {
+			result.WriteString(fmt.Sprintf("• *{%s}* = %s\n", variable.Name, variable.Value))
+		}
+		result.WriteString("\n")
+	}
+
+	result.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	result.WriteString("💡 *.setgroupvar [ID] [NAMA] [nilai]* untuk menambah/mengubah\n")
+	result.WriteString("🗑️ *.delgroupvar [ID] [NAMA]* untuk menghapus")
+
+	return result.String()
+}
+
+// HandleDelGroupVarCommand menangani command .delgroupvar [ID Grup] [NAMA]
+func (h *AdminCommandHandler) HandleDelGroupVarCommand(evt *events.Message, args []string) string {
+	if !h.isAdmin(evt.Info.Sender.User) {
+		return adminOnlyMessage
+	}
+
+	if len(args) < 3 {
+		return `❌ *FORMAT SALAH*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+📝 *Format:* .delgroupvar [ID Grup] [NAMA]
+
+📋 *Contoh:* .delgroupvar 3 RESELLER_WA`
+	}
+
+	groupInfo, errResponse := h.groupFromArg(args[1])
+	if errResponse != "" {
+		return errResponse
+	}
+
+	name, err := h.templateService.GetRenderer().DeleteGroupVariable(groupInfo.JID, args[2])
+	if err != nil {
+		return fmt.Sprintf(`❌ *GAGAL MENGHAPUS VARIABEL GRUP*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s`, err.Error())
+	}
+
+	return fmt.Sprintf(`✅ *VARIABEL GRUP DIHAPUS*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+👥 *Grup:* %s
+🔤 {%s} tidak lagi diatur khusus untuk grup ini.`, groupInfo.Name, name)
+}
+
+// groupFromArg mencari grup berdasarkan ID dari .listgroups; string kedua berisi pesan error untuk admin
+func (h *AdminCommandHandler) groupFromArg(idArg string) (*services.GroupInfo, string) {
+	if h.groupManagerService == nil {
+		return nil, groupServiceUnavailableMessage
+	}
+
+	groupID, err := strconv.Atoi(idArg)
+	if err != nil {
+		return nil, `❌ *ID TIDAK VALID*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 ID grup harus berupa angka.
+📝 Gunakan .listgroups untuk melihat ID.`
+	}
+
+	groupInfo, err := h.groupManagerService.GetGroupByID(groupID)
+	if err != nil {
+		return nil, fmt.Sprintf(`❌ *GRUP TIDAK DITEMUKAN*
+━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
+🚫 %s
+📝 Gunakan .listgroups untuk melihat ID yang valid.`, err.Error())
+	}
+
+	return groupInfo, ""
+}
@@ -3,25 +3,27 @@ package layout
 import (
 	"fmt"
 	"math/rand"
-	"strings"
 	"time"
+
+	"github.com/nabilulilalbab/promote/render"
 )
 
 type MessageTemplate interface {
-	Render(data map[string]string) string
+	Render(data map[string]string) (string, error)
 }
 
+// SimpleTemplate memakai sintaks render yang sama dengan template promosi,
+// misal {PRICE}. Key data tidak peka huruf besar/kecil.
 type SimpleTemplate struct {
 	Format string
 }
 
-func (t SimpleTemplate) Render(data map[string]string) string {
-	msg := t.Format
+func (t SimpleTemplate) Render(data map[string]string) (string, error) {
+	values := render.NewData()
 	for k, v := range data {
-		placeholder := fmt.Sprintf("{{%s}}", k)
-		msg = strings.ReplaceAll(msg, placeholder, v)
+		values.Set(k, v)
 	}
-	return msg
+	return render.Render(t.Format, values)
 }
 
 type TemplateSection struct {
@@ -33,22 +35,22 @@ var sections = []TemplateSection{
 	{
 		Category: "vpn",
 		Templates: []MessageTemplate{
-			SimpleTemplate{Format: "🔥 *VPN PREMIUM {{vpn_type}}* {{price}}/bulan 🚀\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🌐 *PROTOCOLS:*\n• Trojan GRPC/WS • VMess GRPC/WS\n• VLess GRPC/WS • SSH WebSocket\n• Multipath • Wildcard\n\n🌍 *SERVERS:*\n🇮🇩 ID: wa.me/6287786388052 \n🇸🇬 SG: t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
-			SimpleTemplate{Format: "⚡ *VPN {{vpn_type}} PREMIUM* {{price}} aja! 🔥\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🚀 *FEATURES:*\n• ⚡ High Speed • 🔒 Military Encryption\n• 🌍 Multi Server • 📱 All Device\n• 🛡️ No Log • 🔄 24/7 Reconnect\n\n📱 *ORDER:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "🔥 *VPN PREMIUM {VPN_TYPE}* {PRICE}/bulan 🚀\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🌐 *PROTOCOLS:*\n• Trojan GRPC/WS • VMess GRPC/WS\n• VLess GRPC/WS • SSH WebSocket\n• Multipath • Wildcard\n\n🌍 *SERVERS:*\n🇮🇩 ID: wa.me/6287786388052 \n🇸🇬 SG: t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "⚡ *VPN {VPN_TYPE} PREMIUM* {PRICE} aja! 🔥\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🚀 *FEATURES:*\n• ⚡ High Speed • 🔒 Military Encryption\n• 🌍 Multi Server • 📱 All Device\n• 🛡️ No Log • 🔄 24/7 Reconnect\n\n📱 *ORDER:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
 		},
 	},
 	{
 		Category: "ssh",
 		Templates: []MessageTemplate{
-			SimpleTemplate{Format: "⚡ *SSH WS PREMIUM* {{price}} stabil & kenceng! 🚀\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🔒 *SSH FEATURES:*\n• WebSocket Support • Bypass DPI\n• High Speed • Stable Connection\n• Multi Port • SSL/TLS Encryption\n\n📱 *ORDER:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
-			SimpleTemplate{Format: "🔥 *SSH MURAH* {{price}} aja, cobain sekarang! ⚡\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🌐 *ADVANTAGES:*\n• WebSocket Protocol • Anti Blokir\n• Speed Unlimited • Server Stabil\n• Support All Device • 24/7 Online\n\n💬 *CONTACT:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "⚡ *SSH WS PREMIUM* {PRICE} stabil & kenceng! 🚀\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🔒 *SSH FEATURES:*\n• WebSocket Support • Bypass DPI\n• High Speed • Stable Connection\n• Multi Port • SSL/TLS Encryption\n\n📱 *ORDER:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "🔥 *SSH MURAH* {PRICE} aja, cobain sekarang! ⚡\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n🌐 *ADVANTAGES:*\n• WebSocket Protocol • Anti Blokir\n• Speed Unlimited • Server Stabil\n• Support All Device • 24/7 Online\n\n💬 *CONTACT:*\n🇮🇩 wa.me/6287786388052\n🇸🇬 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
 		},
 	},
 	{
 		Category: "kuota",
 		Templates: []MessageTemplate{
-			SimpleTemplate{Format: "💡 *KUOTA DOR XL {{size}}* {{price}}! 🔥\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n📱 *PAKET DATA:*\n• Kuota {{size}} • Harga {{price}}\n• Proses Cepat • Garansi Masuk\n• Support 24/7\n\n📞 *ORDER:*\n📱 wa.me/6287786388052\n🤖 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
-			SimpleTemplate{Format: "🚀 *INTERNET HEMAT* XL {{size}} {{price}} 👌\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n✅ *BENEFITS:*\n• Harga Terjangkau • Kuota Besar\n• Proses Otomatis • Respon Cepat\n• Terpercaya\n\n💬 *CONTACT:*\n📱 wa.me/6287786388052\n🤖 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "💡 *KUOTA DOR XL {SIZE}* {PRICE}! 🔥\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n📱 *PAKET DATA:*\n• Kuota {SIZE} • Harga {PRICE}\n• Proses Cepat • Garansi Masuk\n• Support 24/7\n\n📞 *ORDER:*\n📱 wa.me/6287786388052\n🤖 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
+			SimpleTemplate{Format: "🚀 *INTERNET HEMAT* XL {SIZE} {PRICE} 👌\n\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬\n✅ *BENEFITS:*\n• Harga Terjangkau • Kuota Besar\n• Proses Otomatis • Respon Cepat\n• Terpercaya\n\n💬 *CONTACT:*\n📱 wa.me/6287786388052\n🤖 t.me/grnstoreofficial_bot\n▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬"},
 		},
 	},
 }
@@ -59,7 +61,7 @@ func GetRandomMessage(category string, data map[string]string) (string, error) {
 	for _, section := range sections {
 		if section.Category == category {
 			tmpl := section.Templates[rand.Intn(len(section.Templates))]
-			return tmpl.Render(data), nil
+			return tmpl.Render(data)
 		}
 	}
 
@@ -0,0 +1,539 @@
+// Package render - Mesin render template promosi.
+//
+// Sintaks yang didukung:
+//
+//	{DATE}                  variabel biasa (nama huruf besar, angka, underscore)
+//	{STORE_WA:08123}        variabel dengan nilai default jika kosong/tidak ada
+//	{IF PROMO}...{ELSE}...{END}
+//	{IF NOT PROMO}...{END}
+//	{EACH PRODUCTS}• {NAME} - {PRICE}{END}
+//
+// Di dalam {EACH}, field setiap item bisa dipakai langsung beserta {INDEX}
+// (mulai dari 1) dan {LAST} ("1" untuk item terakhir). Kurung kurawal lain
+// yang tidak cocok dengan sintaks di atas dianggap teks biasa. Tag blok
+// ({IF}, {ELSE}, {END}, {EACH}) yang berdiri sendiri di satu baris tidak
+// meninggalkan baris kosong pada hasil.
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Error adalah kesalahan parse/render beserta nomor baris di template
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("baris %d: %s", e.Line, e.Msg)
+}
+
+func errorf(line int, format string, args ...interface{}) error {
+	return &Error{Line: line, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Data berisi variabel dan daftar (untuk {EACH}) yang tersedia untuk template
+type Data struct {
+	vars  map[string]string
+	lists map[string][]map[string]string
+}
+
+// NewData membuat Data kosong
+func NewData() *Data {
+	return &Data{
+		vars:  make(map[string]string),
+		lists: make(map[string][]map[string]string),
+	}
+}
+
+// Set mengisi variabel. Nama otomatis diubah ke huruf besar.
+func (d *Data) Set(name, value string) *Data {
+	d.vars[strings.ToUpper(name)] = value
+	return d
+}
+
+// SetList mengisi daftar item untuk {EACH}. Nama field item diubah ke huruf besar.
+func (d *Data) SetList(name string, items []map[string]string) *Data {
+	normalized := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		fields := make(map[string]string, len(item))
+		for key, value := range item {
+			fields[strings.ToUpper(key)] = value
+		}
+		normalized = append(normalized, fields)
+	}
+	d.lists[strings.ToUpper(name)] = normalized
+	return d
+}
+
+// Get mengambil nilai variabel
+func (d *Data) Get(name string) (string, bool) {
+	value, ok := d.vars[strings.ToUpper(name)]
+	return value, ok
+}
+
+// Names mengembalikan nama semua variabel dan daftar, terurut
+func (d *Data) Names() []string {
+	names := make([]string, 0, len(d.vars)+len(d.lists))
+	for name := range d.vars {
+		names = append(names, name)
+	}
+	for name := range d.lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Template adalah template yang sudah di-parse dan siap dirender berkali-kali
+type Template struct {
+	nodes []node
+}
+
+// Parse mem-parse konten template dan memeriksa struktur blok
+func Parse(content string) (*Template, error) {
+	tokens, err := lex(content)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	nodes, err := p.parseBlock(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{nodes: nodes}, nil
+}
+
+// Execute merender template dengan data yang diberikan.
+// Variabel yang tidak dikenal (dan tidak punya default) menghasilkan error.
+func (t *Template) Execute(data *Data) (string, error) {
+	if data == nil {
+		data = NewData()
+	}
+
+	var out strings.Builder
+	ex := &executor{data: data, out: &out}
+	if err := ex.run(t.nodes, nil); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// Variables mengembalikan nama variabel dan daftar yang dipakai template, terurut.
+// Field item di dalam {EACH} ikut tercantum karena tidak bisa dibedakan saat parse.
+func (t *Template) Variables() []string {
+	seen := make(map[string]bool)
+	var walk func(nodes []node)
+	walk = func(nodes []node) {
+		for _, n := range nodes {
+			switch n := n.(type) {
+			case varNode:
+				seen[n.name] = true
+			case ifNode:
+				seen[n.name] = true
+				walk(n.then)
+				walk(n.otherwise)
+			case eachNode:
+				seen[n.name] = true
+				walk(n.body)
+			}
+		}
+	}
+	walk(t.nodes)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render mem-parse dan langsung merender konten
+func Render(content string, data *Data) (string, error) {
+	tmpl, err := Parse(content)
+	if err != nil {
+		return "", err
+	}
+	return tmpl.Execute(data)
+}
+
+// ============================================================================
+// LEXER
+// ============================================================================
+
+type tokenKind int
+
+const (
+	tokenText tokenKind = iota
+	tokenVar
+	tokenIf
+	tokenElse
+	tokenEnd
+	tokenEach
+)
+
+type token struct {
+	kind       tokenKind
+	text       string // Isi teks untuk tokenText
+	name       string // Nama variabel/daftar
+	def        string // Nilai default untuk tokenVar
+	hasDefault bool
+	negate     bool // {IF NOT ...}
+	line       int
+}
+
+// lex memecah konten menjadi token teks dan tag
+func lex(src string) ([]token, error) {
+	var tokens []token
+	cursor := 0
+
+	for i := 0; i < len(src); i++ {
+		if src[i] != '{' {
+			continue
+		}
+
+		end := tagEnd(src, i)
+		if end < 0 {
+			continue
+		}
+
+		line := 1 + strings.Count(src[:i], "\n")
+		tok, ok, err := classify(src[i+1:end], line)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		start, stop := i, end+1
+		if tok.kind != tokenVar {
+			start, stop = standaloneSpan(src, start, stop)
+		}
+
+		if start > cursor {
+			tokens = append(tokens, token{kind: tokenText, text: src[cursor:start]})
+		}
+		tokens = append(tokens, tok)
+		cursor = stop
+		i = stop - 1
+	}
+
+	if cursor < len(src) {
+		tokens = append(tokens, token{kind: tokenText, text: src[cursor:]})
+	}
+	return tokens, nil
+}
+
+// tagEnd mencari '}' penutup untuk '{' di posisi start dalam satu baris
+func tagEnd(src string, start int) int {
+	for j := start + 1; j < len(src); j++ {
+		switch src[j] {
+		case '}':
+			return j
+		case '{', '\n':
+			return -1
+		}
+	}
+	return -1
+}
+
+// standaloneSpan memperluas rentang tag blok menjadi satu baris penuh
+// jika tag tersebut berdiri sendiri di barisnya
+func standaloneSpan(src string, start, stop int) (int, int) {
+	lineStart := strings.LastIndexByte(src[:start], '\n') + 1
+	if strings.TrimSpace(src[lineStart:start]) != "" {
+		return start, stop
+	}
+
+	lineEnd := len(src)
+	if idx := strings.IndexByte(src[stop:], '\n'); idx >= 0 {
+		lineEnd = stop + idx
+	}
+	if strings.TrimSpace(src[stop:lineEnd]) != "" {
+		return start, stop
+	}
+
+	if lineEnd < len(src) {
+		lineEnd++ // Ikut buang newline
+	}
+	return lineStart, lineEnd
+}
+
+// classify menentukan jenis tag dari isi di antara kurung kurawal.
+// ok=false berarti isi tersebut bukan tag dan dianggap teks biasa.
+func classify(inner string, line int) (token, bool, error) {
+	tok := token{line: line}
+
+	switch {
+	case inner == "ELSE":
+		tok.kind = tokenElse
+		return tok, true, nil
+
+	case inner == "END":
+		tok.kind = tokenEnd
+		return tok, true, nil
+
+	case inner == "IF" || inner == "EACH":
+		return tok, false, errorf(line, "{%s} membutuhkan nama variabel, contoh {%s PROMO}", inner, inner)
+
+	case strings.HasPrefix(inner, "IF "):
+		tok.kind = tokenIf
+		name := strings.TrimSpace(inner[3:])
+		if strings.HasPrefix(name, "NOT ") {
+			tok.negate = true
+			name = strings.TrimSpace(name[4:])
+		}
+		if !ValidName(name) {
+			return tok, false, errorf(line, "nama variabel tidak valid di {%s}", inner)
+		}
+		tok.name = name
+		return tok, true, nil
+
+	case strings.HasPrefix(inner, "EACH "):
+		tok.kind = tokenEach
+		name := strings.TrimSpace(inner[5:])
+		if !ValidName(name) {
+			return tok, false, errorf(line, "nama daftar tidak valid di {%s}", inner)
+		}
+		tok.name = name
+		return tok, true, nil
+	}
+
+	name, def, hasDefault := inner, "", false
+	if idx := strings.IndexByte(inner, ':'); idx >= 0 {
+		name, def, hasDefault = inner[:idx], inner[idx+1:], true
+	}
+	if !ValidName(name) {
+		return tok, false, nil
+	}
+
+	tok.kind = tokenVar
+	tok.name = name
+	tok.def = def
+	tok.hasDefault = hasDefault
+	return tok, true, nil
+}
+
+// ValidName mengecek format nama variabel: huruf besar di awal, lalu huruf besar/angka/underscore
+func ValidName(s string) bool {
+	if s == "" || s[0] < 'A' || s[0] > 'Z' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================================
+// PARSER
+// ============================================================================
+
+type node interface{}
+
+type textNode struct {
+	text string
+}
+
+type varNode struct {
+	name       string
+	def        string
+	hasDefault bool
+	line       int
+}
+
+type ifNode struct {
+	name      string
+	negate    bool
+	then      []node
+	otherwise []node
+	line      int
+}
+
+type eachNode struct {
+	name string
+	body []node
+	line int
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parseBlock mem-parse token sampai {END}/{ELSE} milik blok pembuka (open)
+// atau sampai token habis jika open nil
+func (p *parser) parseBlock(open *token) ([]node, error) {
+	var nodes []node
+
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		p.pos++
+
+		switch tok.kind {
+		case tokenText:
+			nodes = append(nodes, textNode{text: tok.text})
+
+		case tokenVar:
+			nodes = append(nodes, varNode{name: tok.name, def: tok.def, hasDefault: tok.hasDefault, line: tok.line})
+
+		case tokenIf:
+			n := ifNode{name: tok.name, negate: tok.negate, line: tok.line}
+			body, err := p.parseBlock(&tok)
+			if err != nil {
+				return nil, err
+			}
+			n.then = body
+
+			if p.tokens[p.pos-1].kind == tokenElse {
+				body, err = p.parseBlock(&tok)
+				if err != nil {
+					return nil, err
+				}
+				if p.tokens[p.pos-1].kind == tokenElse {
+					return nil, errorf(p.tokens[p.pos-1].line, "{ELSE} ganda untuk {IF %s} di baris %d", tok.name, tok.line)
+				}
+				n.otherwise = body
+			}
+			nodes = append(nodes, n)
+
+		case tokenEach:
+			n := eachNode{name: tok.name, line: tok.line}
+			body, err := p.parseBlock(&tok)
+			if err != nil {
+				return nil, err
+			}
+			if p.tokens[p.pos-1].kind == tokenElse {
+				return nil, errorf(p.tokens[p.pos-1].line, "{ELSE} tidak bisa dipakai di dalam {EACH %s}", tok.name)
+			}
+			n.body = body
+			nodes = append(nodes, n)
+
+		case tokenElse:
+			// {ELSE} di dalam {EACH} dilaporkan oleh pemanggil dengan pesan yang lebih jelas
+			if open == nil {
+				return nil, errorf(tok.line, "{ELSE} tanpa {IF}")
+			}
+			return nodes, nil
+
+		case tokenEnd:
+			if open == nil {
+				return nil, errorf(tok.line, "{END} tanpa {IF}/{EACH} pembuka")
+			}
+			return nodes, nil
+		}
+	}
+
+	if open != nil {
+		keyword := "IF"
+		if open.kind == tokenEach {
+			keyword = "EACH"
+		}
+		return nil, errorf(open.line, "{%s %s} belum ditutup dengan {END}", keyword, open.name)
+	}
+	return nodes, nil
+}
+
+// ============================================================================
+// EXECUTOR
+// ============================================================================
+
+// scope menyimpan field item {EACH} yang sedang dirender
+type scope struct {
+	vars   map[string]string
+	parent *scope
+}
+
+type executor struct {
+	data *Data
+	out  *strings.Builder
+}
+
+// lookup mencari variabel dari scope terdalam ke data global
+func (ex *executor) lookup(name string, sc *scope) (string, bool) {
+	for s := sc; s != nil; s = s.parent {
+		if value, ok := s.vars[name]; ok {
+			return value, true
+		}
+	}
+	value, ok := ex.data.vars[name]
+	return value, ok
+}
+
+func (ex *executor) run(nodes []node, sc *scope) error {
+	for _, n := range nodes {
+		switch n := n.(type) {
+		case textNode:
+			ex.out.WriteString(n.text)
+
+		case varNode:
+			value, ok := ex.lookup(n.name, sc)
+			if !ok || value == "" {
+				if n.hasDefault {
+					value = n.def
+				} else if !ok {
+					return errorf(n.line, "variabel {%s} tidak dikenal", n.name)
+				}
+			}
+			ex.out.WriteString(value)
+
+		case ifNode:
+			truthy, err := ex.truthy(n, sc)
+			if err != nil {
+				return err
+			}
+			if truthy != n.negate {
+				if err := ex.run(n.then, sc); err != nil {
+					return err
+				}
+			} else if err := ex.run(n.otherwise, sc); err != nil {
+				return err
+			}
+
+		case eachNode:
+			items, ok := ex.data.lists[n.name]
+			if !ok {
+				return errorf(n.line, "daftar {EACH %s} tidak dikenal", n.name)
+			}
+			for i, item := range items {
+				vars := map[string]string{
+					"INDEX": strconv.Itoa(i + 1),
+					"LAST":  "",
+				}
+				if i == len(items)-1 {
+					vars["LAST"] = "1"
+				}
+				for key, value := range item {
+					vars[key] = value
+				}
+				if err := ex.run(n.body, &scope{vars: vars, parent: sc}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// truthy menentukan kondisi {IF}: variabel tidak kosong (dan bukan "0"/"false"),
+// atau daftar yang memiliki minimal satu item
+func (ex *executor) truthy(n ifNode, sc *scope) (bool, error) {
+	if value, ok := ex.lookup(n.name, sc); ok {
+		value = strings.TrimSpace(value)
+		return value != "" && value != "0" && !strings.EqualFold(value, "false"), nil
+	}
+	if items, ok := ex.data.lists[n.name]; ok {
+		return len(items) > 0, nil
+	}
+	return false, errorf(n.line, "variabel {IF %s} tidak dikenal", n.name)
+}
@@ -0,0 +1,153 @@
+package render
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testData adalah data contoh untuk test render
+func testData() *Data {
+	return NewData().
+		Set("STORE", "Toko Kita").
+		Set("EMPTY", "").
+		Set("PROMO", "1").
+		Set("ZERO", "0").
+		Set("OFF", "False").
+		Set("BLANK", "  ").
+		Set("NAME", "global").
+		SetList("PRODUCTS", []map[string]string{
+			{"name": "Kopi", "price": "10k"},
+			{"name": "Teh", "price": "8k"},
+			{"name": "Susu", "price": "12k"},
+		}).
+		SetList("SIZES", []map[string]string{{"size": "S"}, {"size": "L"}}).
+		SetList("NONE", nil)
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"variabel", "Halo {STORE}!", "Halo Toko Kita!"},
+		{"default saat tidak ada", "WA {STORE_WA:0812}", "WA 0812"},
+		{"default saat kosong", "[{EMPTY:kosong}]", "[kosong]"},
+		{"default kosong", "[{MISSING:}]", "[]"},
+		{"nilai kosong tanpa default", "[{EMPTY}]", "[]"},
+		{"default berisi titik dua", "{URL:https://x.id}", "https://x.id"},
+		{"kurung bukan tag tetap teks", "{harga} {a b} { } {", "{harga} {a b} { } {"},
+		{"kurung lintas baris bukan tag", "{STORE\n}", "{STORE\n}"},
+		{"if benar", "{IF PROMO}ada{END}", "ada"},
+		{"if nol salah", "{IF ZERO}ada{ELSE}tidak{END}", "tidak"},
+		{"if false salah", "{IF OFF}ada{ELSE}tidak{END}", "tidak"},
+		{"if spasi salah", "{IF BLANK}ada{ELSE}tidak{END}", "tidak"},
+		{"if not", "{IF NOT EMPTY}kosong{END}", "kosong"},
+		{"if daftar berisi", "{IF PRODUCTS}ada produk{END}", "ada produk"},
+		{"if daftar kosong", "{IF NONE}ada{ELSE}habis{END}", "habis"},
+		{"if bersarang", "{IF PROMO}A{IF ZERO}B{ELSE}C{IF NOT EMPTY}D{END}{END}E{END}", "ACDE"},
+		{"each dengan index", "{EACH PRODUCTS}{INDEX}.{NAME}={PRICE} {END}", "1.Kopi=10k 2.Teh=8k 3.Susu=12k "},
+		{"each dengan if last", "{EACH PRODUCTS}{NAME}{IF NOT LAST}, {END}{END}", "Kopi, Teh, Susu"},
+		{"each daftar kosong", "[{EACH NONE}x{END}]", "[]"},
+		{"field item menutupi variabel global", "{EACH PRODUCTS}{NAME} {END}{NAME}", "Kopi Teh Susu global"},
+		{"each bersarang memakai field luar", "{EACH SIZES}{EACH PRODUCTS}{NAME}-{SIZE}{IF NOT LAST},{END}{END};{END}", "Kopi-S,Teh-S,Susu-S;Kopi-L,Teh-L,Susu-L;"},
+		{"index each dalam", "{EACH SIZES}{EACH SIZES}{INDEX}{END}{END}", "1212"},
+		{"variabel global di dalam each", "{EACH SIZES}{STORE} {SIZE}\n{END}", "Toko Kita S\nToko Kita L\n"},
+		{
+			"tag blok sendirian tidak meninggalkan baris kosong",
+			"Menu:\n{EACH PRODUCTS}\n• {NAME}\n{END}\n{IF ZERO}\nrahasia\n{ELSE}\nSelesai\n{END}\n",
+			"Menu:\n• Kopi\n• Teh\n• Susu\nSelesai\n",
+		},
+		{"tag blok di tengah baris tetap", "a {IF PROMO}b{END} c", "a b c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.content, testData())
+			if err != nil {
+				t.Fatalf("Render(%q) error: %v", tt.content, err)
+			}
+			if got != tt.want {
+				t.Errorf("Render(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		line    int
+		want    string
+	}{
+		{"variabel tidak dikenal", "Halo\n{MISSING}", 2, "variabel {MISSING} tidak dikenal"},
+		{"if tanpa nama", "{IF}x{END}", 1, "membutuhkan nama variabel"},
+		{"each tanpa nama", "{EACH}x{END}", 1, "membutuhkan nama variabel"},
+		{"nama if tidak valid", "{IF promo}x{END}", 1, "nama variabel tidak valid"},
+		{"nama each tidak valid", "{EACH 1X}x{END}", 1, "nama daftar tidak valid"},
+		{"end tanpa pembuka", "a\n{END}", 2, "{END} tanpa"},
+		{"else tanpa if", "a {ELSE} b", 1, "{ELSE} tanpa {IF}"},
+		{"if belum ditutup", "a\n{IF PROMO}\nb", 2, "{IF PROMO} belum ditutup"},
+		{"if dalam each belum ditutup", "{EACH PRODUCTS}\n{IF LAST}x\n{END}", 1, "{EACH PRODUCTS} belum ditutup"},
+		{"else ganda", "{IF PROMO}a{ELSE}b\n{ELSE}c{END}", 2, "{ELSE} ganda"},
+		{"else di dalam each", "{EACH PRODUCTS}a\n{ELSE}b{END}", 2, "tidak bisa dipakai di dalam {EACH PRODUCTS}"},
+		{"daftar tidak dikenal", "\n{EACH ITEMS}x{END}", 2, "daftar {EACH ITEMS} tidak dikenal"},
+		{"if variabel tidak dikenal", "{IF MISSING}x{END}", 1, "variabel {IF MISSING} tidak dikenal"},
+		{"field each di luar each", "{EACH SIZES}{SIZE}{END}\n{SIZE}", 2, "variabel {SIZE} tidak dikenal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(tt.content, testData())
+			if err == nil {
+				t.Fatalf("Render(%q) tidak mengembalikan error", tt.content)
+			}
+			renderErr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("error %T bukan *render.Error", err)
+			}
+			if renderErr.Line != tt.line {
+				t.Errorf("Line = %d, want %d (%v)", renderErr.Line, tt.line, err)
+			}
+			if !strings.Contains(renderErr.Msg, tt.want) {
+				t.Errorf("Msg = %q, want mengandung %q", renderErr.Msg, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	tmpl, err := Parse("{STORE} {IF PROMO}{A:x}{ELSE}{B}{END}{EACH PRODUCTS}{NAME}{END} {harga}")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	want := []string{"A", "B", "NAME", "PRODUCTS", "PROMO", "STORE"}
+	if got := tmpl.Variables(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Variables() = %v, want %v", got, want)
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"STORE_WA", true},
+		{"A1", true},
+		{"", false},
+		{"store", false},
+		{"_A", false},
+		{"1A", false},
+		{"A-B", false},
+		{"A B", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidName(tt.name); got != tt.want {
+			t.Errorf("ValidName(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
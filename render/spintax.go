@@ -0,0 +1,155 @@
+// Package render - Spintax untuk variasi teks per pengiriman
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Spintax yang didukung:
+//
+//	{Halo|Hai|Hey kak}      pilih salah satu opsi secara acak
+//	{Halo|Hai {kak|bro}}    opsi boleh bersarang
+//	{Promo hari ini!|}      opsi kosong berarti bagian ini kadang tidak muncul
+//	{?Promo hari ini!}      singkatan blok opsional (50% muncul)
+//
+// Kurung kurawal tanpa '|' di level teratas (misal {DATE} atau {IF PROMO})
+// bukan spintax dan dibiarkan untuk tahap render variabel. Begitu juga
+// {NAMA:default|lain} karena diawali nama variabel dan ':'.
+
+// HasSpintax mengecek apakah konten memiliki minimal satu blok spintax
+func HasSpintax(content string) bool {
+	for i := 0; i < len(content); i++ {
+		if content[i] != '{' {
+			continue
+		}
+		if end := matchingBrace(content, i); end > 0 {
+			if _, ok := spinOptions(content[i+1 : end]); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Spin memilih satu opsi untuk setiap blok spintax. intn harus mengembalikan
+// angka acak 0 <= x < n (misal rand.Intn).
+func Spin(content string, intn func(n int) int) string {
+	return expandSpintax(content, func(options []string) string {
+		return options[intn(len(options))]
+	})
+}
+
+// SpinAll menggabungkan semua opsi spintax (dipisah spasi) agar variabel
+// di setiap opsi bisa divalidasi sekaligus
+func SpinAll(content string) string {
+	return expandSpintax(content, func(options []string) string {
+		return strings.Join(options, " ")
+	})
+}
+
+// CheckSpintax memastikan setiap opsi spintax berdiri sendiri: blok
+// {IF}/{EACH} yang dibuka di satu opsi harus ditutup di opsi yang sama
+func CheckSpintax(content string) error {
+	var firstErr error
+	expandSpintax(content, func(options []string) string {
+		for _, option := range options {
+			if _, err := Parse(option); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("opsi spintax %q: %v", truncateOption(option), err)
+			}
+		}
+		return ""
+	})
+	return firstErr
+}
+
+// expandSpintax mengganti setiap blok spintax dengan hasil choose.
+// Opsi yang dipilih diekspansi ulang sehingga spintax bersarang ikut diproses.
+func expandSpintax(content string, choose func(options []string) string) string {
+	var out strings.Builder
+	cursor := 0
+
+	for i := 0; i < len(content); i++ {
+		if content[i] != '{' {
+			continue
+		}
+
+		end := matchingBrace(content, i)
+		if end < 0 {
+			continue
+		}
+
+		options, ok := spinOptions(content[i+1 : end])
+		if !ok {
+			continue
+		}
+
+		out.WriteString(content[cursor:i])
+		out.WriteString(expandSpintax(choose(options), choose))
+		cursor = end + 1
+		i = end
+	}
+
+	out.WriteString(content[cursor:])
+	return out.String()
+}
+
+// matchingBrace mencari '}' pasangan dari '{' di posisi start (memperhitungkan kurung bersarang)
+func matchingBrace(content string, start int) int {
+	depth := 0
+	for j := start; j < len(content); j++ {
+		switch content[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+// spinOptions memecah isi kurung menjadi opsi spintax.
+// ok=false berarti isi tersebut bukan spintax.
+func spinOptions(inner string) ([]string, bool) {
+	if strings.HasPrefix(inner, "?") {
+		return []string{inner[1:], ""}, true
+	}
+
+	// {NAMA:default} tetap variabel walaupun default-nya berisi '|'
+	if idx := strings.IndexByte(inner, ':'); idx > 0 && ValidName(inner[:idx]) {
+		return nil, false
+	}
+
+	var options []string
+	depth, last := 0, 0
+	for j := 0; j < len(inner); j++ {
+		switch inner[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '|':
+			if depth == 0 {
+				options = append(options, inner[last:j])
+				last = j + 1
+			}
+		}
+	}
+	if len(options) == 0 {
+		return nil, false
+	}
+	return append(options, inner[last:]), true
+}
+
+// truncateOption memendekkan opsi panjang untuk pesan error
+func truncateOption(option string) string {
+	const maxLength = 40
+	runes := []rune(option)
+	if len(runes) <= maxLength {
+		return option
+	}
+	return string(runes[:maxLength]) + "..."
+}
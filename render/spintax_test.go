@@ -0,0 +1,99 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpin(t *testing.T) {
+	first := func(n int) int { return 0 }
+	last := func(n int) int { return n - 1 }
+
+	tests := []struct {
+		name      string
+		content   string
+		wantFirst string
+		wantLast  string
+	}{
+		{"tanpa spintax", "Halo {NAME}", "Halo {NAME}", "Halo {NAME}"},
+		{"opsi sederhana", "{Halo|Hai|Hey kak}!", "Halo!", "Hey kak!"},
+		{"opsi kosong", "Promo{ hari ini|}", "Promo hari ini", "Promo"},
+		{"opsional", "{?Gratis ongkir! }Order", "Gratis ongkir! Order", "Order"},
+		{"bersarang", "{Halo {kak|bro}|Hai}", "Halo kak", "Hai"},
+		{"bersarang di opsi terakhir", "{Hai|Halo {kak|bro}}", "Hai", "Halo bro"},
+		{"variabel di dalam opsi", "{Halo {NAME}|Hai}", "Halo {NAME}", "Hai"},
+		{"blok if di dalam opsi", "{{IF PROMO}diskon{END}|normal}", "{IF PROMO}diskon{END}", "normal"},
+		{"default berisi pipa bukan spintax", "{LINK:a|b}", "{LINK:a|b}", "{LINK:a|b}"},
+		{"kurung tidak ditutup", "{Halo|Hai", "{Halo|Hai", "{Halo|Hai"},
+		{"beberapa blok", "{A|B} dan {C|D}", "A dan C", "B dan D"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Spin(tt.content, first); got != tt.wantFirst {
+				t.Errorf("Spin(first) = %q, want %q", got, tt.wantFirst)
+			}
+			if got := Spin(tt.content, last); got != tt.wantLast {
+				t.Errorf("Spin(last) = %q, want %q", got, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestHasSpintax(t *testing.T) {
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{"{Halo|Hai}", true},
+		{"{?opsional}", true},
+		{"{IF PROMO}{A|B}{END}", true},
+		{"{NAME} {IF PROMO}x{END}", false},
+		{"{LINK:a|b}", false},
+		{"{Halo|Hai", false},
+		{"tanpa kurung", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasSpintax(tt.content); got != tt.want {
+			t.Errorf("HasSpintax(%q) = %v, want %v", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestSpinAll(t *testing.T) {
+	got := SpinAll("{Halo {NAME}|Hai {kak|{STORE}}}!")
+	want := "Halo {NAME} Hai kak {STORE}!"
+	if got != want {
+		t.Errorf("SpinAll = %q, want %q", got, want)
+	}
+}
+
+func TestCheckSpintax(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"tanpa spintax", "{IF PROMO}a{END}", ""},
+		{"blok lengkap di satu opsi", "{{IF PROMO}a{END}|b}", ""},
+		{"spintax di dalam blok", "{IF PROMO}{a|b}{END}", ""},
+		{"blok terbelah antar opsi", "{{IF PROMO}a|b{END}}", "belum ditutup"},
+		{"end di opsi lain", "{a|b{END}}", "{END} tanpa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSpintax(tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("CheckSpintax(%q) error: %v", tt.content, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CheckSpintax(%q) = %v, want error mengandung %q", tt.content, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Package render - Variabel standar tanggal/waktu
+package render
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultLocale adalah bahasa yang dipakai jika grup belum diatur
+const DefaultLocale = "id"
+
+// localeNames berisi nama hari dan bulan untuk satu bahasa
+type localeNames struct {
+	label  string
+	days   [7]string
+	months [13]string
+}
+
+var locales = map[string]localeNames{
+	"id": {
+		label:  "Indonesia",
+		days:   [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+		months: [13]string{"", "Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+	},
+	"en": {
+		label:  "English",
+		days:   [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		months: [13]string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+	},
+	"ms": {
+		label:  "Melayu",
+		days:   [7]string{"Ahad", "Isnin", "Selasa", "Rabu", "Khamis", "Jumaat", "Sabtu"},
+		months: [13]string{"", "Januari", "Februari", "Mac", "April", "Mei", "Jun", "Julai", "Ogos", "September", "Oktober", "November", "Disember"},
+	},
+}
+
+// ParseLocale menormalkan kode bahasa ("EN", "en-US" menjadi "en")
+func ParseLocale(value string) (string, error) {
+	locale := strings.ToLower(strings.TrimSpace(value))
+	if idx := strings.IndexAny(locale, "-_"); idx > 0 {
+		locale = locale[:idx]
+	}
+	if _, ok := locales[locale]; !ok {
+		return "", fmt.Errorf("bahasa '%s' tidak didukung (gunakan %s)", value, strings.Join(SupportedLocales(), ", "))
+	}
+	return locale, nil
+}
+
+// SupportedLocales mengembalikan kode bahasa yang didukung, terurut
+func SupportedLocales() []string {
+	codes := make([]string, 0, len(locales))
+	for code := range locales {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// LocaleLabel mengembalikan nama bahasa untuk ditampilkan, misal "en (English)"
+func LocaleLabel(locale string) string {
+	names, ok := locales[locale]
+	if !ok {
+		return locale
+	}
+	return fmt.Sprintf("%s (%s)", locale, names.label)
+}
+
+// namesFor mengembalikan nama hari/bulan untuk locale, atau bahasa default jika tidak dikenal
+func namesFor(locale string) localeNames {
+	if names, ok := locales[locale]; ok {
+		return names
+	}
+	return locales[DefaultLocale]
+}
+
+// DayName mengembalikan nama hari dalam bahasa locale
+func DayName(day time.Weekday, locale string) string {
+	return namesFor(locale).days[day]
+}
+
+// MonthName mengembalikan nama bulan dalam bahasa locale
+func MonthName(month time.Month, locale string) string {
+	return namesFor(locale).months[month]
+}
+
+// FormatDate memformat tanggal dalam bahasa locale, misal "16 Oktober 2026"
+func FormatDate(t time.Time, locale string) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), MonthName(t.Month(), locale), t.Year())
+}
+
+// StandardData membuat Data berisi variabel waktu standar:
+// {DATE}, {DATE_ISO}, {TIME}, {DAY}, {MONTH}, {YEAR}.
+// {DATE}, {DAY} dan {MONTH} mengikuti bahasa locale.
+func StandardData(now time.Time, locale string) *Data {
+	return NewData().
+		Set("DATE", FormatDate(now, locale)).
+		Set("DATE_ISO", now.Format("2006-01-02")).
+		Set("TIME", now.Format("15:04")).
+		Set("DAY", DayName(now.Weekday(), locale)).
+		Set("MONTH", MonthName(now.Month(), locale)).
+		Set("YEAR", strconv.Itoa(now.Year()))
+}
@@ -0,0 +1,52 @@
+// Package services - Pemberitahuan otomatis ke nomor admin lewat chat pribadi
+package services
+
+import (
+	"context"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// AdminNotifier mengirim pemberitahuan sistem (misal template kedaluwarsa) ke semua admin
+type AdminNotifier struct {
+	client *whatsmeow.Client
+	admins []string
+	logger *utils.Logger
+}
+
+// NewAdminNotifier membuat notifier untuk nomor admin dari konfigurasi
+func NewAdminNotifier(client *whatsmeow.Client, adminNumbers []string, logger *utils.Logger) *AdminNotifier {
+	var admins []string
+	for _, number := range adminNumbers {
+		if number = strings.TrimSpace(number); number != "" {
+			admins = append(admins, number)
+		}
+	}
+
+	return &AdminNotifier{
+		client: client,
+		admins: admins,
+		logger: logger,
+	}
+}
+
+// Notify mengirim pesan ke setiap admin. Kegagalan hanya dicatat di log
+// agar proses yang memicu pemberitahuan tetap berjalan.
+func (n *AdminNotifier) Notify(text string) {
+	if n == nil || len(n.admins) == 0 {
+		return
+	}
+
+	for _, number := range n.admins {
+		jid := types.NewJID(number, types.DefaultUserServer)
+		msg := &waProto.Message{Conversation: &text}
+		if _, err := n.client.SendMessage(context.Background(), jid, msg); err != nil {
+			n.logger.Errorf("Failed to notify admin %s: %v", number, err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nabilulilalbab/promote/render"
 	"github.com/nabilulilalbab/promote/utils"
 )
 
@@ -39,6 +40,19 @@ type Product struct {
 	NoNeedLogin        bool   `json:"no_need_login"`
 }
 
+// defaultStoreVariables adalah nilai awal variabel kontak yang dipakai template produk.
+// Nilai hanya diisi jika admin belum mengaturnya lewat .setvar.
+var defaultStoreVariables = []struct {
+	name  string
+	value string
+}{
+	{"STORE_WA", "6287786388052"},
+	{"STORE_TG", "grnstoreofficial_bot"},
+	{"ADMIN_WA", "6285117557905"},
+	{"STORE_GROUP", "IeIXOndIoFr0apnlKzghUC"},
+	{"STORE_HOURS", "01:00 - 23:00 WIB"},
+}
+
 // NewAPIProductService membuat service baru
 func NewAPIProductService(templateService *TemplateService, logger *utils.Logger) *APIProductService {
 	return &APIProductService{
@@ -80,6 +94,9 @@ func (s *APIProductService) FetchProductsAndCreateTemplates() (string, error) {
 🔄 *Coba lagi nanti atau hubungi admin API*`, nil
 	}
 
+	// Template produk memakai variabel kontak, pastikan variabelnya tersedia
+	s.ensureStoreVariables()
+
 	// Group produk per 15 dan buat template gabungan
 	createdCount := 0
 	var errors []string
@@ -95,7 +112,7 @@ func (s *APIProductService) FetchProductsAndCreateTemplates() (string, error) {
 		templateContent := s.generateGroupedProductTemplate(productGroup, i/groupSize+1)
 		templateTitle := fmt.Sprintf("Paket Group %d (%d Produk)", i/groupSize+1, len(productGroup))
 
-		_, err := s.templateService.CreateTemplate(templateTitle, templateContent, "produk_api_group")
+		_, err := s.templateService.CreateTemplate(templateTitle, templateContent, "produk_api_group", "api")
 		if err != nil {
 			s.logger.Errorf("Failed to create template group %d: %v", i/groupSize+1, err)
 			errors = append(errors, fmt.Sprintf("Group %d: %v", i/groupSize+1, err))
@@ -155,6 +172,21 @@ func (s *APIProductService) FetchProductsAndCreateTemplates() (string, error) {
 	return result.String(), nil
 }
 
+// ensureStoreVariables mengisi variabel kontak default yang belum diatur admin
+func (s *APIProductService) ensureStoreVariables() {
+	renderer := s.templateService.GetRenderer()
+	for _, variable := range defaultStoreVariables {
+		created, err := renderer.EnsureVariable(variable.name, variable.value, "system")
+		if err != nil {
+			s.logger.Errorf("Failed to ensure template variable %s: %v", variable.name, err)
+			continue
+		}
+		if created {
+			s.logger.Infof("Template variable {%s} initialized with default value", variable.name)
+		}
+	}
+}
+
 // Helper function untuk max
 func max(a, b int) int {
 	if a > b {
@@ -208,6 +240,14 @@ func (s *APIProductService) fetchProductsFromAPI() ([]Product, error) {
 	return productResp.Data, nil
 }
 
+// productListTemplate adalah format daftar paket pada template katalog grup
+const productListTemplate = `{EACH PRODUCTS}
+📱 *{NAME}* - {PRICE}
+{IF NOT LAST}
+
+{END}
+{END}`
+
 // generateGroupedProductTemplate membuat template promosi untuk group produk
 func (s *APIProductService) generateGroupedProductTemplate(products []Product, groupNum int) string {
 	var template strings.Builder
@@ -224,8 +264,8 @@ func (s *APIProductService) generateGroupedProductTemplate(products []Product, g
 • Multipath • Wildcard
 
 🌍 *SERVERS:*
-🇮🇩 ID: wa.me/6287786388052
-🇸🇬 SG: t.me/grnstoreofficial_bot
+🇮🇩 ID: wa.me/{STORE_WA}
+🇸🇬 SG: t.me/{STORE_TG}
 
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
@@ -241,18 +281,24 @@ _Stok menipis, buruan order!_
 `, groupNum))
 
 	// Tambahkan daftar produk dengan format yang lebih ringkas
-	for i, product := range products {
+	items := make([]map[string]string, 0, len(products))
+	for _, product := range products {
 		// Validasi data produk
 		if product.PackageNameShort == "" || product.PackageHarga == "" {
 			continue // Skip produk dengan data kosong
 		}
 
-		template.WriteString(fmt.Sprintf("📱 *%s* - %s\n", product.PackageNameShort, product.PackageHarga))
+		items = append(items, map[string]string{
+			"NAME":  product.PackageNameShort,
+			"PRICE": product.PackageHarga,
+		})
+	}
 
-		if i < len(products)-1 {
-			template.WriteString("\n")
-		}
+	productList, err := render.Render(productListTemplate, render.NewData().SetList("PRODUCTS", items))
+	if err != nil {
+		s.logger.Errorf("Failed to render product list: %v", err)
 	}
+	template.WriteString(productList)
 
 	// Tambahkan informasi singkat dan contact
 	template.WriteString(`
@@ -283,22 +329,22 @@ _Stok menipis, buruan order!_
 📞 *ORDER CENTER*
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
-🇮🇩 *VPN ID:* wa.me/6287786388052
-🇸🇬 *VPN SG:* t.me/grnstoreofficial_bot
+🇮🇩 *VPN ID:* wa.me/{STORE_WA}
+🇸🇬 *VPN SG:* t.me/{STORE_TG}
 
 🛒 *PAKET DATA:*
-📱 wa.me/6287786388052
-🤖 t.me/grnstoreofficial_bot
+📱 wa.me/{STORE_WA}
+🤖 t.me/{STORE_TG}
 
 👨‍💼 *ADMIN:*
-📱 wa.me/6287786388052
-📱 wa.me/6285117557905
+📱 wa.me/{STORE_WA}
+📱 wa.me/{ADMIN_WA}
 
-👥 *GROUP:* chat.whatsapp.com/IeIXOndIoFr0apnlKzghUC
+👥 *GROUP:* chat.whatsapp.com/{STORE_GROUP}
 
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
-🟢 *BUKA:* 01:00 - 23:00 WIB
+🟢 *BUKA:* {STORE_HOURS}
 ⏰ *BURUAN ORDER!* Stok terbatas!
 
 #PaketData #VPNPremium #GRNStore`)
@@ -326,8 +372,8 @@ func (s *APIProductService) generateProductTemplate(product Product) string {
 • Multipath • Wildcard
 
 🌍 *SERVERS:*
-🇮🇩 ID: wa.me/6287786388052
-🇸🇬 SG: t.me/grnstoreofficial_bot
+🇮🇩 ID: wa.me/{STORE_WA}
+🇸🇬 SG: t.me/{STORE_TG}
 
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
@@ -340,18 +386,18 @@ func (s *APIProductService) generateProductTemplate(product Product) string {
 📞 *ORDER CENTER*
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
-🇮🇩 *VPN ID:* wa.me/6287786388052
-🇸🇬 *VPN SG:* t.me/grnstoreofficial_bot
+🇮🇩 *VPN ID:* wa.me/{STORE_WA}
+🇸🇬 *VPN SG:* t.me/{STORE_TG}
 
 🛒 *PAKET DATA:*
-📱 wa.me/6287786388052
-🤖 t.me/grnstoreofficial_bot
+📱 wa.me/{STORE_WA}
+🤖 t.me/{STORE_TG}
 
 👨‍💼 *ADMIN:*
-📱 wa.me/6287786388052
-📱 wa.me/6285117557905
+📱 wa.me/{STORE_WA}
+📱 wa.me/{ADMIN_WA}
 
-👥 *GROUP:* chat.whatsapp.com/IeIXOndIoFr0apnlKzghUC
+👥 *GROUP:* chat.whatsapp.com/{STORE_GROUP}
 
 ▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬▬
 
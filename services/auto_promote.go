@@ -2,53 +2,345 @@
 package services
 
 import (
-	"context"
 	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/types"
-	waProto "go.mau.fi/whatsmeow/binary/proto"
 
 	"github.com/nabilulilalbab/promote/database"
 	"github.com/nabilulilalbab/promote/utils"
 )
 
+// schedulerTickInterval adalah resolusi pengecekan jadwal grup.
+// Setiap tick scheduler menghitung grup mana yang sudah jatuh tempo.
+const schedulerTickInterval = time.Minute
+
+// settingAutoPromoteInterval adalah key promote_settings untuk interval yang diatur admin
+const settingAutoPromoteInterval = "auto_promote_interval"
+
+// maxAutoPromoteInterval adalah batas atas interval default
+const maxAutoPromoteInterval = 24 * time.Hour
+
+// missedSlotGrace adalah toleransi keterlambatan sebelum jadwal dianggap terlewat saat startup
+const missedSlotGrace = 5 * time.Minute
+
+// MissedSlotPolicy menentukan perlakuan jadwal yang terlewat saat bot mati (restart/deploy)
+type MissedSlotPolicy string
+
+const (
+	MissedSlotOnce   MissedSlotPolicy = "once"   // Kirim satu kali untuk semua slot yang terlewat
+	MissedSlotSkip   MissedSlotPolicy = "skip"   // Lewati, tunggu slot berikutnya
+	MissedSlotSpread MissedSlotPolicy = "spread" // Kirim, tapi disebar merata sepanjang interval
+)
+
 // AutoPromoteService mengelola fitur auto promote
 type AutoPromoteService struct {
 	client     *whatsmeow.Client
 	repository database.Repository
 	logger     *utils.Logger
 	scheduler  *SchedulerService
+	queue      *SendQueueService
+	selector   *TemplateSelector
+	renderer   *TemplateRenderer
+	campaigns  *CampaignService
+	jobs       *ScheduledJobService
+	leader     *LeaderService
+	templates  *TemplateService
+	notifier   *AdminNotifier
 	isRunning  bool
-	interval   time.Duration // Interval auto promote dalam durasi
+	runMutex   sync.Mutex     // Melindungi isRunning: diubah dari callback leader dan command admin
+	location   *time.Location // Timezone untuk jadwal dan jam kirim
+	window     *SendWindow    // Jam kirim default untuk grup tanpa window sendiri
+	
+	interval       time.Duration // Interval default untuk grup tanpa jadwal sendiri
+	configInterval time.Duration // Interval dari konfigurasi (env), dipakai saat override dihapus
+	intervalFromDB bool          // Interval efektif berasal dari promote_settings
+	intervalMutex  sync.RWMutex
+	
+	missedPolicy MissedSlotPolicy // Perlakuan jadwal terlewat saat startup
+	catchUp      bool             // Pass berikutnya adalah evaluasi startup
+	processMutex sync.Mutex       // Mencegah dua pass scheduler berjalan bersamaan
 }
 
 // NewAutoPromoteService membuat service baru
-func NewAutoPromoteService(client *whatsmeow.Client, repo database.Repository, logger *utils.Logger) *AutoPromoteService {
+func NewAutoPromoteService(client *whatsmeow.Client, repo database.Repository, queue *SendQueueService, selector *TemplateSelector, renderer *TemplateRenderer, logger *utils.Logger) *AutoPromoteService {
 	// Inisialisasi random seed sekali saja
 	rand.Seed(time.Now().UnixNano())
 
 	service := &AutoPromoteService{
 		client:     client,
 		repository: repo,
+		queue:      queue,
+		selector:   selector,
+		renderer:   renderer,
 		logger:     logger,
 		isRunning:  false,
-		interval:   4 * time.Hour, // Default 4 jam
+		location:   time.Local,
+		window:     &SendWindow{}, // Default tanpa batasan jam
+		
+		interval:       4 * time.Hour, // Default 4 jam
+		configInterval: 4 * time.Hour,
+		missedPolicy:   MissedSlotOnce,
 	}
 	
 	// Inisialisasi scheduler
 	service.scheduler = NewSchedulerService(service.processScheduledPromotes, logger)
 	
+	// Antrian menahan promosi yang baru tiba gilirannya di luar jam kirim grup
+	if queue != nil {
+		queue.SetWindowResolver(service.sendWindowFor)
+	}
+	
 	return service
 }
 
-// SetInterval mengatur interval auto promote
-func (s *AutoPromoteService) SetInterval(hours int) {
-	s.interval = time.Duration(hours) * time.Hour
-	s.logger.Infof("Auto promote interval set to %d hours", hours)
+// SetInterval mengatur interval auto promote dari konfigurasi
+func (s *AutoPromoteService) SetInterval(interval time.Duration) {
+	s.intervalMutex.Lock()
+	s.configInterval = interval
+	if !s.intervalFromDB {
+		s.interval = interval
+	}
+	s.intervalMutex.Unlock()
+	
+	s.logger.Infof("Auto promote interval set to %v", interval)
+}
+
+// LoadSettings memuat pengaturan runtime yang disimpan admin di database
+func (s *AutoPromoteService) LoadSettings() error {
+	setting, err := s.repository.GetSetting(settingAutoPromoteInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get interval setting: %v", err)
+	}
+	
+	if setting == nil {
+		return nil
+	}
+	
+	interval, err := ParseInterval(setting.Value)
+	if err != nil {
+		return fmt.Errorf("invalid stored interval %q: %v", setting.Value, err)
+	}
+	
+	s.intervalMutex.Lock()
+	s.interval = interval
+	s.intervalFromDB = true
+	s.intervalMutex.Unlock()
+	
+	s.logger.Infof("Auto promote interval loaded from database: %v", interval)
+	return nil
+}
+
+// UpdateInterval mengubah interval default saat runtime, menyimpannya ke database,
+// lalu me-restart scheduler. Interval 0 menghapus override dan kembali ke konfigurasi.
+func (s *AutoPromoteService) UpdateInterval(interval time.Duration) error {
+	if interval != 0 && (interval < time.Minute || interval > maxAutoPromoteInterval) {
+		return fmt.Errorf("interval harus antara 1 menit - %v", maxAutoPromoteInterval)
+	}
+	
+	if interval == 0 {
+		if err := s.repository.DeleteSetting(settingAutoPromoteInterval); err != nil {
+			return fmt.Errorf("failed to delete interval setting: %v", err)
+		}
+	} else {
+		if err := s.repository.SetSetting(settingAutoPromoteInterval, formatScheduleDuration(interval)); err != nil {
+			return fmt.Errorf("failed to save interval setting: %v", err)
+		}
+	}
+	
+	// Tunggu pass scheduler yang sedang berjalan agar interval tidak berubah di tengah proses
+	s.processMutex.Lock()
+	s.intervalMutex.Lock()
+	if interval == 0 {
+		s.interval = s.configInterval
+		s.intervalFromDB = false
+	} else {
+		s.interval = interval
+		s.intervalFromDB = true
+	}
+	effective := s.interval
+	s.intervalMutex.Unlock()
+	
+	s.resetDefaultScheduleGroups()
+	s.processMutex.Unlock()
+	
+	s.logger.Infof("Auto promote interval updated to %v", effective)
+	
+	s.runMutex.Lock()
+	if s.isRunning {
+		s.scheduler.Restart(schedulerTickInterval)
+	}
+	s.runMutex.Unlock()
+	return nil
+}
+
+// GetInterval mengembalikan interval default yang sedang berlaku
+func (s *AutoPromoteService) GetInterval() time.Duration {
+	s.intervalMutex.RLock()
+	defer s.intervalMutex.RUnlock()
+	return s.interval
+}
+
+// GetIntervalSource mengembalikan asal interval efektif (database atau konfigurasi)
+func (s *AutoPromoteService) GetIntervalSource() string {
+	s.intervalMutex.RLock()
+	defer s.intervalMutex.RUnlock()
+	
+	if s.intervalFromDB {
+		return "database (.setinterval)"
+	}
+	return "konfigurasi (AUTO_PROMOTE_INTERVAL)"
+}
+
+// resetDefaultScheduleGroups menghitung ulang jadwal grup yang memakai interval default
+func (s *AutoPromoteService) resetDefaultScheduleGroups() {
+	groups, err := s.repository.GetActiveGroups()
+	if err != nil {
+		s.logger.Errorf("Failed to get active groups for interval reset: %v", err)
+		return
+	}
+	
+	for _, group := range groups {
+		if group.Schedule != "" || group.NextPromoteAt == nil {
+			continue
+		}
+		
+		group.NextPromoteAt = nil
+		if err := s.repository.UpdateAutoPromoteGroup(&group); err != nil {
+			s.logger.Errorf("Failed to reset schedule for group %s: %v", group.GroupJID, err)
+		}
+	}
+}
+
+// SetCampaignService menghubungkan service campaign agar diprioritaskan oleh scheduler
+func (s *AutoPromoteService) SetCampaignService(campaigns *CampaignService) {
+	s.campaigns = campaigns
+	campaigns.SetLocation(s.location)
+}
+
+// SetScheduledJobService menghubungkan service broadcast terjadwal agar dijalankan oleh scheduler
+func (s *AutoPromoteService) SetScheduledJobService(jobs *ScheduledJobService) {
+	s.jobs = jobs
+	jobs.SetLocation(s.location)
+}
+
+// SetTemplateService menghubungkan service template agar template kedaluwarsa dinonaktifkan scheduler
+func (s *AutoPromoteService) SetTemplateService(templates *TemplateService) {
+	s.templates = templates
+}
+
+// SetAdminNotifier mengatur penerima pemberitahuan otomatis (misal template kedaluwarsa)
+func (s *AutoPromoteService) SetAdminNotifier(notifier *AdminNotifier) {
+	s.notifier = notifier
+}
+
+// SetLeaderService menghubungkan leader election; scheduler hanya berjalan di instance leader
+func (s *AutoPromoteService) SetLeaderService(leader *LeaderService) {
+	s.leader = leader
+}
+
+// GetLeaderStatus mengembalikan status lease scheduler (nil jika leader election tidak dipakai)
+func (s *AutoPromoteService) GetLeaderStatus() (*LeaderStatus, error) {
+	if s.leader == nil {
+		return nil, nil
+	}
+
+	status, err := s.leader.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// isStandby mengecek apakah instance ini bukan leader sehingga tidak boleh menjalankan scheduler
+func (s *AutoPromoteService) isStandby() bool {
+	return s.leader != nil && !s.leader.IsLeader()
+}
+
+// GetTemplateSelector mengembalikan selector template yang dipakai scheduler
+func (s *AutoPromoteService) GetTemplateSelector() *TemplateSelector {
+	return s.selector
+}
+
+// GetQueueStatus mengembalikan ringkasan antrian kirim
+func (s *AutoPromoteService) GetQueueStatus() QueueStatus {
+	return s.queue.GetStatus()
+}
+
+// GetMissedSlotPolicy mengembalikan perlakuan jadwal terlewat saat startup
+func (s *AutoPromoteService) GetMissedSlotPolicy() MissedSlotPolicy {
+	return s.missedPolicy
+}
+
+// GetDefaultSendWindowDescription mengembalikan jam kirim global dalam bentuk teks
+func (s *AutoPromoteService) GetDefaultSendWindowDescription() string {
+	if s.window.IsAlwaysOpen() {
+		return "24 jam"
+	}
+	return s.window.String()
+}
+
+// IsSchedulerRunning mengecek apakah scheduler auto promote sedang berjalan
+func (s *AutoPromoteService) IsSchedulerRunning() bool {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	return s.isRunning
+}
+
+// SetTimezone mengatur timezone yang dipakai untuk jadwal dan jam kirim (misal "Asia/Jakarta")
+func (s *AutoPromoteService) SetTimezone(name string) error {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("failed to load timezone %s: %v", name, err)
+	}
+
+	s.location = location
+	s.queue.SetLocation(location)
+	if s.campaigns != nil {
+		s.campaigns.SetLocation(location)
+	}
+	if s.jobs != nil {
+		s.jobs.SetLocation(location)
+	}
+	s.logger.Infof("Auto promote timezone set to %s", location.String())
+	return nil
+}
+
+// GetTimezone mengembalikan timezone yang dipakai scheduler
+func (s *AutoPromoteService) GetTimezone() *time.Location {
+	return s.location
+}
+
+// SetDefaultSendWindow mengatur jam kirim global (misal "08:00-21:00" atau "always")
+func (s *AutoPromoteService) SetDefaultSendWindow(expr string) error {
+	window, err := ParseSendWindow(expr)
+	if err != nil {
+		return err
+	}
+
+	s.window = window
+	s.logger.Infof("Default send window set to %s", window.String())
+	return nil
+}
+
+// SetMissedSlotPolicy mengatur perlakuan jadwal yang terlewat saat startup (once, skip, spread)
+func (s *AutoPromoteService) SetMissedSlotPolicy(policy string) error {
+	switch MissedSlotPolicy(strings.ToLower(strings.TrimSpace(policy))) {
+	case MissedSlotOnce, "":
+		s.missedPolicy = MissedSlotOnce
+	case MissedSlotSkip:
+		s.missedPolicy = MissedSlotSkip
+	case MissedSlotSpread:
+		s.missedPolicy = MissedSlotSpread
+	default:
+		return fmt.Errorf("missed slot policy tidak dikenal: %s (gunakan once, skip, atau spread)", policy)
+	}
+	
+	s.logger.Infof("Missed slot policy set to %s", s.missedPolicy)
+	return nil
 }
 
 // StartAutoPromote mengaktifkan auto promote untuk grup tertentu
@@ -84,10 +376,8 @@ func (s *AutoPromoteService) StartAutoPromote(groupJID string) error {
 		return fmt.Errorf("failed to update group: %v", err)
 	}
 	
-	// Start scheduler jika belum berjalan
-	if !s.isRunning {
-		s.StartScheduler()
-	}
+	// Start scheduler jika belum berjalan (StartScheduler sendiri mengabaikan panggilan ganda)
+	s.StartScheduler()
 	
 	s.logger.Successf("Auto promote activated for group: %s", groupJID)
 	return nil
@@ -120,6 +410,13 @@ func (s *AutoPromoteService) StopAutoPromote(groupJID string) error {
 		return fmt.Errorf("failed to update group: %v", err)
 	}
 	
+	// Batalkan promosi yang masih menunggu di antrian
+	if cancelled, err := s.repository.CancelQueuedMessages(groupJID); err != nil {
+		s.logger.Errorf("Failed to cancel queued messages for group %s: %v", groupJID, err)
+	} else if cancelled > 0 {
+		s.logger.Infof("Cancelled %d queued messages for group %s", cancelled, groupJID)
+	}
+	
 	s.logger.Successf("Auto promote deactivated for group: %s", groupJID)
 	return nil
 }
@@ -131,19 +428,37 @@ func (s *AutoPromoteService) GetGroupStatus(groupJID string) (*database.AutoProm
 
 // StartScheduler memulai scheduler untuk auto promote
 func (s *AutoPromoteService) StartScheduler() {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	
 	if s.isRunning {
 		return
 	}
 	
+	// Instance standby menunggu lease, scheduler dijalankan lewat callback leader
+	if s.isStandby() {
+		s.logger.Info("Standby instance, scheduler will start when the lease is acquired")
+		return
+	}
+	
 	s.logger.Info("Starting auto promote scheduler...")
-	s.logger.Infof("Scheduler will run every %v", s.interval)
-	s.scheduler.Start(s.interval)
+	s.logger.Infof("Scheduler will check group schedules every %v (default interval %v)", schedulerTickInterval, s.GetInterval())
+	
+	// Pass pertama mengejar grup yang terlewat selama bot mati
+	s.processMutex.Lock()
+	s.catchUp = true
+	s.processMutex.Unlock()
+	
+	s.scheduler.Start(schedulerTickInterval)
 	s.isRunning = true
-	s.logger.Successf("Auto promote scheduler started with %v interval!", s.interval)
+	s.logger.Successf("Auto promote scheduler started with %v default interval!", s.GetInterval())
 }
 
 // StopScheduler menghentikan scheduler
 func (s *AutoPromoteService) StopScheduler() {
+	s.runMutex.Lock()
+	defer s.runMutex.Unlock()
+	
 	if !s.isRunning {
 		return
 	}
@@ -162,7 +477,23 @@ func (s *AutoPromoteService) processScheduledPromotes() {
 		}
 	}()
 
-	s.logger.Info("Processing scheduled promotes...")
+	s.processMutex.Lock()
+	defer s.processMutex.Unlock()
+	
+	// Pengaman tambahan jika lease hilang sebelum scheduler sempat dihentikan
+	if s.isStandby() {
+		s.logger.Warning("Skipping scheduler pass, this instance is not the leader")
+		return
+	}
+	
+	catchUp := s.catchUp
+	s.catchUp = false
+	
+	if catchUp {
+		s.logger.Infof("Running startup catch-up (missed slot policy: %s)...", s.missedPolicy)
+	} else {
+		s.logger.Info("Processing scheduled promotes...")
+	}
 	
 	// Ambil semua grup yang aktif dengan retry mechanism
 	activeGroups, err := s.getActiveGroupsWithRetry(3)
@@ -171,13 +502,22 @@ func (s *AutoPromoteService) processScheduledPromotes() {
 		return
 	}
 	
+	s.logger.Infof("Found %d active groups", len(activeGroups))
+	
+	// Template yang melewati valid_until dinonaktifkan sebelum dipakai campaign maupun rotasi
+	s.expireTemplates()
+	
+	// Campaign diprioritaskan: grup yang sedang ikut campaign tidak masuk rotasi default
+	campaignGroups := s.processCampaigns(activeGroups)
+	
+	// Broadcast terjadwal berjalan terpisah dari rotasi dan tidak menggeser jadwal grup
+	s.processScheduledJobs(activeGroups)
+	
 	if len(activeGroups) == 0 {
 		s.logger.Info("No active groups for auto promote")
 		return
 	}
 	
-	s.logger.Infof("Found %d active groups", len(activeGroups))
-	
 	// Ambil template aktif dengan retry mechanism
 	templates, err := s.getActiveTemplatesWithRetry(3)
 	if err != nil {
@@ -185,6 +525,9 @@ func (s *AutoPromoteService) processScheduledPromotes() {
 		return
 	}
 	
+	// Template di luar masa tayang (belum mulai, di luar hari/jam tayang) tidak ikut rotasi
+	templates = filterValidTemplates(templates, s.now())
+	
 	if len(templates) == 0 {
 		s.logger.Warning("No active templates available")
 		return
@@ -192,160 +535,579 @@ func (s *AutoPromoteService) processScheduledPromotes() {
 	
 	s.logger.Infof("Found %d active templates", len(templates))
 	
-	// Proses setiap grup dengan error handling individual
-	successCount := 0
+	// Kategori yang diikuti tiap grup; grup tanpa kategori menerima semua template
+	subscriptions, err := s.GetAllGroupCategories()
+	if err != nil {
+		s.logger.Errorf("Failed to get group categories: %v", err)
+		return
+	}
+	
+	// Masukkan grup yang jatuh tempo ke antrian kirim, pengiriman dilakukan bertahap oleh worker
+	queuedCount := 0
 	failCount := 0
 	skippedCount := 0
 	
+	// Saat startup, grup yang jadwalnya terlewat diperlakukan sesuai missed slot policy
+	missed := make(map[string]bool)
+	if catchUp {
+		missed = s.findMissedGroups(activeGroups)
+	}
+	spreadIndex := 0
+	
 	for _, group := range activeGroups {
-		// Cek apakah sudah waktunya untuk promote (4 jam sejak terakhir)
+		if campaignGroups[group.GroupJID] {
+			skippedCount++
+			continue
+		}
+		
+		if missed[group.GroupJID] && s.missedPolicy == MissedSlotSkip {
+			s.skipMissedSlot(&group)
+			skippedCount++
+			continue
+		}
+		
+		// Cek apakah jadwal grup sudah jatuh tempo dan jam kirim sedang terbuka
 		if s.shouldSkipGroup(&group) {
 			skippedCount++
-			s.logger.Debugf("Skipping group %s (not yet time)", group.GroupJID)
 			continue
 		}
 		
-		// Kirim promosi dengan retry mechanism
-		err := s.sendPromoteToGroupWithRetry(group.GroupJID, templates, 2)
+		// Jangan antrikan ulang grup yang pesannya belum terkirim
+		pending, err := s.queue.HasPending(group.GroupJID)
 		if err != nil {
-			s.logger.Errorf("Failed to send promote to group %s after retries: %v", group.GroupJID, err)
+			s.logger.Errorf("Failed to check queue for group %s: %v", group.GroupJID, err)
 			failCount++
-		} else {
-			successCount++
-			
-			// Update last promote time dengan error handling
-			now := time.Now()
-			group.LastPromoteAt = &now
-			updateErr := s.repository.UpdateAutoPromoteGroup(&group)
-			if updateErr != nil {
-				s.logger.Errorf("Failed to update group %s last promote time: %v", group.GroupJID, updateErr)
+			continue
+		}
+		if pending {
+			skippedCount++
+			s.logger.Debugf("Skipping group %s (previous promote still queued)", group.GroupJID)
+			continue
+		}
+		
+		// Jitter dibatasi agar pesan terkirim sebelum slot berikutnya
+		now := s.now()
+		next := s.scheduleForGroup(&group).Next(now)
+		
+		// Slot dilewati jika tidak ada template aktif di kategori grup
+		groupTemplates := filterTemplatesByCategories(templates, subscriptions[group.GroupJID])
+		if len(groupTemplates) == 0 {
+			s.logger.Warningf("Skipping group %s: no active templates in categories %s",
+				group.GroupJID, strings.Join(subscriptions[group.GroupJID], ", "))
+			skippedCount++
+			group.NextPromoteAt = &next
+			if updateErr := s.repository.UpdateAutoPromoteGroup(&group); updateErr != nil {
+				s.logger.Errorf("Failed to update group %s next promote time: %v", group.GroupJID, updateErr)
 			}
+			continue
+		}
+		delay := s.queue.RandomDelay(next.Sub(now))
+		
+		if missed[group.GroupJID] && s.missedPolicy == MissedSlotSpread {
+			delay = s.spreadDelay(spreadIndex, len(missed), next.Sub(now))
+			spreadIndex++
+		}
+		
+		err = s.sendPromoteToGroupWithRetry(group.GroupJID, groupTemplates, 3, delay)
+		if err != nil {
+			// Slot tetap digeser agar grup yang gagal (misal variabel tidak dikenal)
+			// tidak dicoba ulang setiap tick dan menahan grup lain
+			s.logger.Errorf("Failed to queue promote for group %s, skipping to next slot %s: %v",
+				group.GroupJID, next.Format(adminTimeLayout), err)
+			failCount++
+		} else {
+			queuedCount++
+		}
+		
+		// Jadwal berikutnya langsung digeser agar tick berikutnya tidak mengantrikan ulang.
+		// Waktu promosi terakhir diisi oleh send queue setelah pesan benar-benar terkirim.
+		group.NextPromoteAt = &next
+		if updateErr := s.repository.UpdateAutoPromoteGroup(&group); updateErr != nil {
+			s.logger.Errorf("Failed to update group %s next promote time: %v", group.GroupJID, updateErr)
 		}
 	}
 	
-	s.logger.Infof("Scheduled promotes completed: %d success, %d failed, %d skipped", successCount, failCount, skippedCount)
+	s.logger.Infof("Scheduled promotes completed: %d queued, %d failed, %d skipped", queuedCount, failCount, skippedCount)
+}
+
+// processCampaigns mengantrikan promosi untuk campaign yang sedang berjalan.
+// Mengembalikan grup yang sedang dipegang campaign agar dilewati oleh rotasi default.
+func (s *AutoPromoteService) processCampaigns(activeGroups []database.AutoPromoteGroup) map[string]bool {
+	handled := make(map[string]bool)
+	if s.campaigns == nil {
+		return handled
+	}
 	
-	// Update statistik dengan error handling
-	today := time.Now().Format("2006-01-02")
-	statsErr := s.repository.UpdateStats(today, len(activeGroups), successCount+failCount, successCount, failCount)
-	if statsErr != nil {
-		s.logger.Errorf("Failed to update stats: %v", statsErr)
+	now := s.now()
+	campaigns, err := s.campaigns.RunningCampaigns(now)
+	if err != nil {
+		s.logger.Errorf("Failed to get running campaigns: %v", err)
+		return handled
+	}
+	
+	for _, campaign := range campaigns {
+		templates, err := s.campaigns.GetCampaignTemplates(&campaign)
+		if err != nil {
+			s.logger.Warningf("Campaign %d has no usable templates: %v", campaign.ID, err)
+			continue
+		}
+		
+		templates = filterValidTemplates(templates, now)
+		if len(templates) == 0 {
+			s.logger.Warningf("Campaign %d skipped: no templates within their validity window", campaign.ID)
+			continue
+		}
+		
+		targets := campaign.GroupJIDs
+		if campaign.TargetAll {
+			targets = nil
+			for _, group := range activeGroups {
+				targets = append(targets, group.GroupJID)
+			}
+		}
+		
+		interval := time.Duration(campaign.IntervalMinutes) * time.Minute
+		queued := 0
+		
+		for _, groupJID := range targets {
+			// Satu grup hanya dipegang satu campaign per pass
+			if handled[groupJID] {
+				continue
+			}
+			handled[groupJID] = true
+			
+			if s.enqueueCampaignPromote(&campaign, groupJID, templates, interval, now) {
+				queued++
+			}
+		}
+		
+		if queued > 0 {
+			s.logger.Infof("Campaign %s queued %d promotes", campaign.Name, queued)
+		}
 	}
+	
+	return handled
 }
 
-// shouldSkipGroup mengecek apakah grup harus dilewati
-func (s *AutoPromoteService) shouldSkipGroup(group *database.AutoPromoteGroup) bool {
-	// Jika belum pernah kirim promosi, kirim sekarang
-	if group.LastPromoteAt == nil {
-		return false
+// processScheduledJobs menjalankan broadcast terjadwal yang sudah jatuh tempo.
+// Waktu kirim dipilih eksplisit oleh admin, sehingga jam kirim grup tidak diterapkan.
+func (s *AutoPromoteService) processScheduledJobs(activeGroups []database.AutoPromoteGroup) {
+	if s.jobs == nil {
+		return
 	}
 	
-	// Cek apakah sudah mencapai interval yang ditentukan sejak promosi terakhir
-	intervalAgo := time.Now().Add(-s.interval)
-	return group.LastPromoteAt.After(intervalAgo)
+	now := s.now()
+	jobs, err := s.jobs.DueJobs(now)
+	if err != nil {
+		s.logger.Errorf("Failed to get scheduled jobs: %v", err)
+		return
+	}
+	
+	for _, job := range jobs {
+		if s.jobs.IsMissed(&job, now) {
+			s.logger.Warningf("Scheduled job #%d missed (was due %s)", job.ID, s.jobs.FormatTime(job.RunAt))
+			s.jobs.FinishJob(&job, database.ScheduledJobMissed, 0, "terlewat saat bot tidak berjalan")
+			continue
+		}
+		
+		template, err := s.repository.GetTemplateByID(job.TemplateID)
+		if err != nil || template == nil {
+			s.logger.Errorf("Scheduled job #%d: template %d unavailable: %v", job.ID, job.TemplateID, err)
+			s.jobs.FinishJob(&job, database.ScheduledJobFailed, 0, "template tidak ditemukan")
+			continue
+		}
+		
+		targets := job.GroupJIDs
+		if job.TargetAll {
+			targets = nil
+			for _, group := range activeGroups {
+				targets = append(targets, group.GroupJID)
+			}
+		}
+		
+		queued := 0
+		for _, groupJID := range targets {
+			content, variantID, err := s.renderer.RenderTemplate(template, groupJID, s.now())
+			if err != nil {
+				s.logger.Errorf("Failed to render scheduled job #%d for %s: %v", job.ID, groupJID, err)
+				continue
+			}
+			
+			msg := &database.QueuedMessage{
+				GroupJID:    groupJID,
+				TemplateID:  template.ID,
+				Content:     content,
+				Source:      database.QueueSourceSchedule,
+				MaxAttempts: 3,
+				VariantID:   variantID,
+			}
+			if err := s.queue.EnqueueMessage(msg, 0); err != nil {
+				s.logger.Errorf("Failed to queue scheduled job #%d for %s: %v", job.ID, groupJID, err)
+				continue
+			}
+			queued++
+		}
+		
+		if queued == 0 {
+			s.jobs.FinishJob(&job, database.ScheduledJobFailed, 0, "tidak ada grup yang bisa dikirimi")
+			continue
+		}
+		
+		s.jobs.FinishJob(&job, database.ScheduledJobDone, queued, "")
+		s.logger.Successf("Scheduled job #%d queued %d messages", job.ID, queued)
+	}
 }
 
-// sendPromoteToGroup mengirim promosi ke grup tertentu
-func (s *AutoPromoteService) sendPromoteToGroup(groupJID string, templates []database.PromoteTemplate) error {
-	// Pilih template secara random
-	template := s.selectRandomTemplate(templates)
+// expireTemplates menonaktifkan template kedaluwarsa dan memberi tahu admin
+func (s *AutoPromoteService) expireTemplates() {
+	if s.templates == nil {
+		return
+	}
 	
-	// Parse JID grup
-	jid, err := types.ParseJID(groupJID)
+	expired, err := s.templates.ExpireTemplates(s.now())
 	if err != nil {
-		return fmt.Errorf("invalid group JID: %v", err)
+		s.logger.Errorf("Failed to check expired templates: %v", err)
+		return
 	}
 	
-	// Proses template (replace variables)
-	content := s.processTemplate(template.Content, jid)
+	if len(expired) > 0 {
+		s.logger.Infof("%d template(s) expired and deactivated", len(expired))
+		s.notifier.Notify(FormatExpiredNotice(expired, s.location))
+	}
+}
+
+// enqueueCampaignPromote mengantrikan satu promosi campaign jika grup sudah jatuh tempo
+func (s *AutoPromoteService) enqueueCampaignPromote(campaign *database.Campaign, groupJID string, templates []database.PromoteTemplate, interval time.Duration, now time.Time) bool {
+	last, err := s.repository.GetLastCampaignEnqueue(campaign.ID, groupJID)
+	if err != nil {
+		s.logger.Errorf("Failed to get last campaign send for %s: %v", groupJID, err)
+		return false
+	}
+	if last != nil && now.Before(last.Add(interval)) {
+		return false
+	}
 	
-	// Kirim pesan
-	err = s.sendMessage(jid, content)
+	// Jam kirim tetap mengikuti pengaturan grup
+	if !s.sendWindowFor(groupJID).IsOpen(now) {
+		return false
+	}
 	
-	// Log hasil
-	log := &database.PromoteLog{
-		GroupJID:   groupJID,
-		TemplateID: template.ID,
-		Content:    content,
-		SentAt:     time.Now(),
-		Success:    err == nil,
+	pending, err := s.queue.HasPending(groupJID)
+	if err != nil || pending {
+		return false
 	}
 	
+	template := s.selector.Select(groupJID, templates)
+	content, variantID, err := s.renderer.RenderTemplate(&template, groupJID, now)
 	if err != nil {
-		errorMsg := err.Error()
-		log.ErrorMsg = &errorMsg
+		s.logger.Errorf("Failed to render template %d for campaign %d: %v", template.ID, campaign.ID, err)
+		return false
 	}
 	
-	s.repository.CreateLog(log)
+	msg := &database.QueuedMessage{
+		GroupJID:    groupJID,
+		TemplateID:  template.ID,
+		Content:     content,
+		Source:      database.QueueSourceCampaign,
+		MaxAttempts: 3,
+		CampaignID:  campaign.ID,
+		VariantID:   variantID,
+	}
 	
-	return err
+	if err := s.queue.EnqueueMessage(msg, s.queue.RandomDelay(interval)); err != nil {
+		s.logger.Errorf("Failed to queue campaign promote for %s: %v", groupJID, err)
+		return false
+	}
+	return true
 }
 
-// selectRandomTemplate memilih template secara random
-func (s *AutoPromoteService) selectRandomTemplate(templates []database.PromoteTemplate) database.PromoteTemplate {
-	if len(templates) == 0 {
-		// Return empty template jika tidak ada
-		return database.PromoteTemplate{}
+// findMissedGroups mencari grup yang jadwalnya terlewat lebih dari missedSlotGrace
+func (s *AutoPromoteService) findMissedGroups(groups []database.AutoPromoteGroup) map[string]bool {
+	missed := make(map[string]bool)
+	now := s.now()
+	
+	for _, group := range groups {
+		due := s.dueTime(&group)
+		if !due.IsZero() && now.Sub(due) > missedSlotGrace {
+			missed[group.GroupJID] = true
+		}
 	}
 	
-	// Pilih index random
-	index := rand.Intn(len(templates))
-	return templates[index]
+	if len(missed) > 0 {
+		s.logger.Infof("Found %d groups with missed slots", len(missed))
+	}
+	return missed
 }
 
-// processTemplate memproses template dengan mengganti variables
-func (s *AutoPromoteService) processTemplate(content string, groupJID types.JID) string {
-	now := time.Now()
+// skipMissedSlot menggeser jadwal grup ke slot berikutnya tanpa mengirim promosi
+func (s *AutoPromoteService) skipMissedSlot(group *database.AutoPromoteGroup) {
+	next := s.scheduleForGroup(group).Next(s.now())
+	group.NextPromoteAt = &next
 	
-	// Replace variables yang tersedia
-	replacements := map[string]string{
-		"{DATE}":     now.Format("2006-01-02"),
-		"{TIME}":     now.Format("15:04"),
-		"{DAY}":      getDayName(now.Weekday()),
-		"{MONTH}":    getMonthName(now.Month()),
-		"{YEAR}":     fmt.Sprintf("%d", now.Year()),
-		"{GROUP_ID}": groupJID.User,
+	if err := s.repository.UpdateAutoPromoteGroup(group); err != nil {
+		s.logger.Errorf("Failed to skip missed slot for group %s: %v", group.GroupJID, err)
+		return
 	}
 	
-	result := content
-	for placeholder, value := range replacements {
-		result = strings.ReplaceAll(result, placeholder, value)
+	s.logger.Infof("Skipped missed slot for group %s, next promote at %s", group.GroupJID, next.Format("2006-01-02 15:04"))
+}
+
+// spreadDelay membagi grup yang terlewat secara merata sepanjang interval default
+func (s *AutoPromoteService) spreadDelay(index, total int, maxDelay time.Duration) time.Duration {
+	window := s.GetInterval()
+	if maxDelay > 0 && window > maxDelay {
+		window = maxDelay
+	}
+	if total <= 0 || window <= 0 {
+		return 0
 	}
 	
-	return result
+	slot := window / time.Duration(total)
+	delay := slot * time.Duration(index)
+	if slot > 0 {
+		delay += time.Duration(rand.Int63n(int64(slot)))
+	}
+	return delay
+}
+
+// shouldSkipGroup mengecek apakah grup harus dilewati.
+// Grup yang jatuh tempo di luar jam kirim tidak dibuang, hanya ditunda sampai window terbuka.
+func (s *AutoPromoteService) shouldSkipGroup(group *database.AutoPromoteGroup) bool {
+	now := s.now()
+
+	due := s.dueTime(group)
+	if now.Before(due) {
+		s.logger.Debugf("Skipping group %s (not yet time)", group.GroupJID)
+		return true
+	}
+
+	window := s.windowForGroup(group)
+	if !window.IsOpen(now) {
+		s.logger.Debugf("Deferring group %s (outside send window %s, reopens %s)",
+			group.GroupJID, window.String(), window.NextOpen(now).Format("2006-01-02 15:04"))
+		return true
+	}
+
+	return false
 }
 
-// sendMessage mengirim pesan ke grup
-func (s *AutoPromoteService) sendMessage(groupJID types.JID, content string) error {
-	// Buat pesan WhatsApp
-	msg := &waProto.Message{
-		Conversation: &content,
+// NextPromoteTime menghitung kapan grup berikutnya akan dikirimi promosi,
+// termasuk penundaan karena jam kirim. Waktu nol berarti grup langsung jatuh tempo.
+func (s *AutoPromoteService) NextPromoteTime(group *database.AutoPromoteGroup) time.Time {
+	now := s.now()
+
+	due := s.dueTime(group)
+	if due.Before(now) {
+		due = now
+	}
+
+	next := s.windowForGroup(group).NextOpen(due)
+	if !next.After(now) {
+		return time.Time{}
+	}
+	return next
+}
+
+// dueTime menghitung kapan jadwal grup jatuh tempo tanpa memperhitungkan jam kirim
+func (s *AutoPromoteService) dueTime(group *database.AutoPromoteGroup) time.Time {
+	if group.NextPromoteAt != nil {
+		return group.NextPromoteAt.In(s.location)
+	}
+
+	// Jika belum pernah kirim promosi, kirim sekarang
+	if group.LastPromoteAt == nil {
+		return time.Time{}
 	}
+
+	return s.scheduleForGroup(group).Next(group.LastPromoteAt.In(s.location))
+}
+
+// now mengembalikan waktu sekarang dalam timezone scheduler
+func (s *AutoPromoteService) now() time.Time {
+	return time.Now().In(s.location)
+}
+
+// scheduleForGroup mengembalikan jadwal grup, atau interval default jika tidak diatur
+func (s *AutoPromoteService) scheduleForGroup(group *database.AutoPromoteGroup) Schedule {
+	if group.Schedule != "" {
+		schedule, err := ParseSchedule(group.Schedule)
+		if err == nil {
+			return schedule
+		}
+		s.logger.Warningf("Invalid schedule %q for group %s, using default interval: %v", group.Schedule, group.GroupJID, err)
+	}
+
+	return NewIntervalSchedule(s.GetInterval())
+}
+
+// SetGroupSchedule mengatur ekspresi jadwal untuk grup tertentu.
+// Ekspresi kosong atau "default" mengembalikan grup ke interval default.
+func (s *AutoPromoteService) SetGroupSchedule(groupJID, expr string) (*database.AutoPromoteGroup, error) {
+	expr = strings.TrimSpace(expr)
+
+	normalized := ""
+	if expr != "" && strings.ToLower(expr) != "default" {
+		schedule, err := ParseSchedule(expr)
+		if err != nil {
+			return nil, err
+		}
+		normalized = schedule.String()
+	}
+
+	group, err := s.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %v", err)
+	}
+
+	if group == nil {
+		group, err = s.repository.CreateAutoPromoteGroup(groupJID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group: %v", err)
+		}
+	}
+
+	// Jadwal berikutnya dihitung ulang dari promosi terakhir dengan jadwal baru
+	group.Schedule = normalized
+	group.NextPromoteAt = nil
+
+	if err := s.repository.UpdateAutoPromoteGroup(group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %v", err)
+	}
+
+	s.logger.Infof("Schedule for group %s set to %q", groupJID, s.scheduleForGroup(group).String())
+	return group, nil
+}
+
+// GetScheduleDescription mengembalikan jadwal efektif grup dalam bentuk teks
+func (s *AutoPromoteService) GetScheduleDescription(group *database.AutoPromoteGroup) string {
+	if group.Schedule == "" {
+		return fmt.Sprintf("%s (default)", s.scheduleForGroup(group).String())
+	}
+	return s.scheduleForGroup(group).String()
+}
+
+// windowForGroup mengembalikan jam kirim grup, atau window global jika tidak diatur
+func (s *AutoPromoteService) windowForGroup(group *database.AutoPromoteGroup) *SendWindow {
+	if group.SendWindow != "" {
+		window, err := ParseSendWindow(group.SendWindow)
+		if err == nil {
+			return window
+		}
+		s.logger.Warningf("Invalid send window %q for group %s, using default window: %v", group.SendWindow, group.GroupJID, err)
+	}
+
+	return s.window
+}
+
+// sendWindowFor mengembalikan jam kirim efektif grup berdasarkan JID.
+// Grup yang tidak terdaftar (atau gagal dibaca) memakai window global.
+func (s *AutoPromoteService) sendWindowFor(groupJID string) *SendWindow {
+	group, err := s.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		s.logger.Errorf("Failed to get group %s: %v", groupJID, err)
+		return s.window
+	}
+	if group == nil {
+		return s.window
+	}
+	return s.windowForGroup(group)
+}
+
+// SetGroupSendWindow mengatur jam kirim untuk grup tertentu.
+// Ekspresi kosong atau "default" mengembalikan grup ke window global, "always" berarti tanpa batasan.
+func (s *AutoPromoteService) SetGroupSendWindow(groupJID, expr string) (*database.AutoPromoteGroup, error) {
+	expr = strings.TrimSpace(expr)
+
+	normalized := ""
+	if expr != "" && strings.ToLower(expr) != "default" {
+		window, err := ParseSendWindow(expr)
+		if err != nil {
+			return nil, err
+		}
+		normalized = window.String()
+	}
+
+	group, err := s.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %v", err)
+	}
+
+	if group == nil {
+		group, err = s.repository.CreateAutoPromoteGroup(groupJID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group: %v", err)
+		}
+	}
+
+	group.SendWindow = normalized
+
+	if err := s.repository.UpdateAutoPromoteGroup(group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %v", err)
+	}
+
+	s.logger.Infof("Send window for group %s set to %q", groupJID, s.windowForGroup(group).String())
+	return group, nil
+}
+
+// GetSendWindowDescription mengembalikan jam kirim efektif grup dalam bentuk teks
+func (s *AutoPromoteService) GetSendWindowDescription(group *database.AutoPromoteGroup) string {
+	window := s.windowForGroup(group)
+
+	description := "24 jam"
+	if !window.IsAlwaysOpen() {
+		description = window.String()
+	}
+
+	if group.SendWindow == "" {
+		description += " (default)"
+	}
+	return fmt.Sprintf("%s %s", description, s.location.String())
+}
+
+// enqueuePromote memilih template, memprosesnya, lalu memasukkannya ke antrian kirim
+func (s *AutoPromoteService) enqueuePromote(groupJID string, templates []database.PromoteTemplate, source string, delay time.Duration, maxAttempts int) error {
+	msg, err := s.buildPromote(groupJID, templates, source, maxAttempts)
+	if err != nil {
+		return err
+	}
+	return s.queue.EnqueueMessage(msg, delay)
+}
+
+// buildPromote memilih template sesuai strategi grup lalu merendernya menjadi pesan antrian
+func (s *AutoPromoteService) buildPromote(groupJID string, templates []database.PromoteTemplate, source string, maxAttempts int) (*database.QueuedMessage, error) {
+	// Pilih template sesuai strategi grup (atau strategi global)
+	template := s.selector.Select(groupJID, templates)
 	
-	// Kirim pesan
-	_, err := s.client.SendMessage(context.Background(), groupJID, msg)
+	// Pilih varian A/B lalu render (variabel, kondisi, loop)
+	content, variantID, err := s.renderer.RenderTemplate(&template, groupJID, s.now())
 	if err != nil {
-		return fmt.Errorf("failed to send message: %v", err)
+		return nil, fmt.Errorf("template %d: %v", template.ID, err)
 	}
 	
-	s.logger.Infof("Promote message sent to group: %s", groupJID.String())
-	return nil
+	return &database.QueuedMessage{
+		GroupJID:    groupJID,
+		TemplateID:  template.ID,
+		Content:     content,
+		Source:      source,
+		MaxAttempts: maxAttempts,
+		VariantID:   variantID,
+	}, nil
 }
 
-// SendManualPromote mengirim promosi manual (untuk testing)
+// SendManualPromote memasukkan promosi manual ke antrian tanpa jitter (untuk testing)
 func (s *AutoPromoteService) SendManualPromote(groupJID string) error {
-	// Ambil template aktif
-	templates, err := s.repository.GetActiveTemplates()
+	// Ambil template aktif sesuai kategori grup
+	templates, categories, err := s.GetTemplatesForGroup(groupJID)
 	if err != nil {
-		return fmt.Errorf("failed to get templates: %v", err)
+		return err
 	}
 	
 	if len(templates) == 0 {
-		return fmt.Errorf("no active templates available")
+		return noTemplatesForCategoriesError(categories)
 	}
 	
-	// Kirim promosi
-	return s.sendPromoteToGroup(groupJID, templates)
+	// Antrikan promosi, tetap tunduk pada rate limit
+	return s.enqueuePromote(groupJID, templates, database.QueueSourceManual, 0, 1)
 }
 
 // GetActiveGroupsCount mendapatkan jumlah grup aktif
@@ -404,41 +1166,30 @@ func (s *AutoPromoteService) getActiveTemplatesWithRetry(maxRetries int) ([]data
 	return nil, lastErr
 }
 
-// sendPromoteToGroupWithRetry mengirim promosi dengan retry mechanism
-func (s *AutoPromoteService) sendPromoteToGroupWithRetry(groupJID string, templates []database.PromoteTemplate, maxRetries int) error {
+// sendPromoteToGroupWithRetry memasukkan promosi ke antrian kirim.
+// Percobaan ulang saat gagal kirim ditangani oleh worker antrian hingga maxRetries kali.
+// Gagal render tidak dicoba ulang karena hasilnya akan tetap sama.
+func (s *AutoPromoteService) sendPromoteToGroupWithRetry(groupJID string, templates []database.PromoteTemplate, maxRetries int, delay time.Duration) error {
+	msg, err := s.buildPromote(groupJID, templates, database.QueueSourceAuto, maxRetries)
+	if err != nil {
+		return err
+	}
+	
 	var lastErr error
 	
 	for i := 0; i < maxRetries; i++ {
-		err := s.sendPromoteToGroup(groupJID, templates)
+		err := s.queue.EnqueueMessage(msg, delay)
 		if err == nil {
 			return nil
 		}
 		
 		lastErr = err
-		s.logger.Warningf("Retry %d/%d sending promote to %s failed: %v", i+1, maxRetries, groupJID, err)
+		s.logger.Warningf("Retry %d/%d queueing promote for %s failed: %v", i+1, maxRetries, groupJID, err)
 		
 		if i < maxRetries-1 {
-			time.Sleep(time.Duration(i+1) * 2 * time.Second) // Longer backoff for network issues
+			time.Sleep(time.Duration(i+1) * time.Second) // Exponential backoff
 		}
 	}
 	
 	return lastErr
 }
-
-// Helper functions
-
-func getDayName(day time.Weekday) string {
-	days := []string{
-		"Minggu", "Senin", "Selasa", "Rabu", 
-		"Kamis", "Jumat", "Sabtu",
-	}
-	return days[day]
-}
-
-func getMonthName(month time.Month) string {
-	months := []string{
-		"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
-		"Juli", "Agustus", "September", "Oktober", "November", "Desember",
-	}
-	return months[month]
-}
\ No newline at end of file
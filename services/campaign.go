@@ -0,0 +1,383 @@
+// Package services - Campaign promosi berbatas waktu (flash sale, Ramadan, dll)
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// adminTimeLayout adalah format waktu yang diketik admin untuk campaign dan broadcast terjadwal
+const adminTimeLayout = "2006-01-02 15:04"
+
+// CampaignService mengelola campaign promosi
+type CampaignService struct {
+	repository database.Repository
+	logger     *utils.Logger
+	location   *time.Location
+}
+
+// CampaignReport berisi ringkasan hasil campaign dari promote_logs
+type CampaignReport struct {
+	Campaign    *database.Campaign
+	State       string
+	Total       int
+	Success     int
+	Failed      int
+	FirstSentAt *time.Time
+	LastSentAt  *time.Time
+	ByGroup     map[string]int // Jumlah pesan berhasil per grup
+	ByTemplate  map[int]int    // Jumlah pesan berhasil per template
+}
+
+// NewCampaignService membuat service campaign baru
+func NewCampaignService(repo database.Repository, logger *utils.Logger) *CampaignService {
+	return &CampaignService{
+		repository: repo,
+		logger:     logger,
+		location:   time.Local,
+	}
+}
+
+// SetLocation mengatur timezone untuk membaca dan menampilkan waktu campaign
+func (c *CampaignService) SetLocation(location *time.Location) {
+	c.location = location
+}
+
+// ParseCampaignTime memparse waktu campaign format "YYYY-MM-DD HH:MM" dalam timezone scheduler
+func (c *CampaignService) ParseCampaignTime(value string) (time.Time, error) {
+	t, err := time.ParseInLocation(adminTimeLayout, strings.TrimSpace(value), c.location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format waktu tidak valid: %s (gunakan YYYY-MM-DD HH:MM)", value)
+	}
+	return t, nil
+}
+
+// FormatTime memformat waktu dalam timezone scheduler
+func (c *CampaignService) FormatTime(t time.Time) string {
+	return t.In(c.location).Format(adminTimeLayout)
+}
+
+// CreateCampaign membuat campaign baru.
+// templateSpec berisi daftar ID template ("1,2,3") atau kategori ("kategori:diskon").
+// Jika targetAll bernilai true, campaign dikirim ke semua grup yang aktif auto promote.
+func (c *CampaignService) CreateCampaign(name, start, end, templateSpec string, groupJIDs []string, targetAll bool, interval string) (*database.Campaign, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("nama campaign tidak boleh kosong")
+	}
+
+	startAt, err := c.ParseCampaignTime(start)
+	if err != nil {
+		return nil, err
+	}
+	endAt, err := c.ParseCampaignTime(end)
+	if err != nil {
+		return nil, err
+	}
+	if !endAt.After(startAt) {
+		return nil, fmt.Errorf("waktu selesai harus setelah waktu mulai")
+	}
+	if !endAt.After(time.Now()) {
+		return nil, fmt.Errorf("waktu selesai sudah lewat")
+	}
+
+	every, err := ParseInterval(interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if !targetAll && len(groupJIDs) == 0 {
+		return nil, fmt.Errorf("minimal satu grup target harus dipilih")
+	}
+
+	campaign := &database.Campaign{
+		Name:            name,
+		StartAt:         startAt,
+		EndAt:           endAt,
+		TargetAll:       targetAll,
+		IntervalMinutes: int(every / time.Minute),
+		Status:          database.CampaignStatusActive,
+		GroupJIDs:       groupJIDs,
+	}
+
+	if err := c.applyTemplateSpec(campaign, templateSpec); err != nil {
+		return nil, err
+	}
+
+	if _, err := c.GetCampaignTemplates(campaign); err != nil {
+		return nil, err
+	}
+
+	if err := c.repository.CreateCampaign(campaign); err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %v", err)
+	}
+
+	c.logger.Successf("Campaign created: %s (ID: %d)", campaign.Name, campaign.ID)
+	return campaign, nil
+}
+
+// applyTemplateSpec mengisi TemplateIDs atau Category campaign dari input admin
+func (c *CampaignService) applyTemplateSpec(campaign *database.Campaign, spec string) error {
+	spec = strings.TrimSpace(spec)
+	lower := strings.ToLower(spec)
+
+	for _, prefix := range []string{"kategori:", "category:"} {
+		if strings.HasPrefix(lower, prefix) {
+			category := strings.TrimSpace(lower[len(prefix):])
+			if category == "" {
+				return fmt.Errorf("kategori template tidak boleh kosong")
+			}
+			campaign.Category = category
+			return nil
+		}
+	}
+
+	ids, err := parseIDList(spec)
+	if err != nil {
+		return err
+	}
+
+	var parts []string
+	for _, id := range ids {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	campaign.TemplateIDs = strings.Join(parts, ",")
+	return nil
+}
+
+// GetCampaignTemplates mengambil template campaign.
+// Template tidak harus aktif, sehingga template campaign bisa dinonaktifkan dari rotasi default.
+func (c *CampaignService) GetCampaignTemplates(campaign *database.Campaign) ([]database.PromoteTemplate, error) {
+	var templates []database.PromoteTemplate
+
+	if campaign.Category != "" {
+		all, err := c.repository.GetAllTemplates()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get templates: %v", err)
+		}
+		for _, template := range all {
+			if strings.EqualFold(template.Category, campaign.Category) {
+				templates = append(templates, template)
+			}
+		}
+		if len(templates) == 0 {
+			return nil, fmt.Errorf("tidak ada template dengan kategori '%s'", campaign.Category)
+		}
+		return templates, nil
+	}
+
+	ids, err := parseIDList(campaign.TemplateIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		template, err := c.repository.GetTemplateByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get template %d: %v", id, err)
+		}
+		if template == nil {
+			return nil, fmt.Errorf("template dengan ID %d tidak ditemukan", id)
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, nil
+}
+
+// GetCampaign mengambil campaign berdasarkan ID
+func (c *CampaignService) GetCampaign(id int) (*database.Campaign, error) {
+	campaign, err := c.repository.GetCampaignByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %v", err)
+	}
+	if campaign == nil {
+		return nil, fmt.Errorf("campaign dengan ID %d tidak ditemukan", id)
+	}
+	return campaign, nil
+}
+
+// GetAllCampaigns mengambil semua campaign
+func (c *CampaignService) GetAllCampaigns() ([]database.Campaign, error) {
+	return c.repository.GetAllCampaigns()
+}
+
+// PauseCampaign menjeda campaign dan membatalkan pesan yang masih di antrian
+func (c *CampaignService) PauseCampaign(id int) (*database.Campaign, error) {
+	campaign, err := c.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != database.CampaignStatusActive {
+		return nil, fmt.Errorf("campaign tidak sedang aktif (status: %s)", campaign.Status)
+	}
+
+	if err := c.repository.UpdateCampaignStatus(id, database.CampaignStatusPaused); err != nil {
+		return nil, fmt.Errorf("failed to pause campaign: %v", err)
+	}
+	c.cancelQueued(campaign)
+
+	campaign.Status = database.CampaignStatusPaused
+	c.logger.Infof("Campaign paused: %s (ID: %d)", campaign.Name, id)
+	return campaign, nil
+}
+
+// ResumeCampaign melanjutkan campaign yang dijeda
+func (c *CampaignService) ResumeCampaign(id int) (*database.Campaign, error) {
+	campaign, err := c.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status != database.CampaignStatusPaused {
+		return nil, fmt.Errorf("campaign tidak sedang dijeda (status: %s)", campaign.Status)
+	}
+	if !campaign.EndAt.After(time.Now()) {
+		return nil, fmt.Errorf("periode campaign sudah berakhir")
+	}
+
+	if err := c.repository.UpdateCampaignStatus(id, database.CampaignStatusActive); err != nil {
+		return nil, fmt.Errorf("failed to resume campaign: %v", err)
+	}
+
+	campaign.Status = database.CampaignStatusActive
+	c.logger.Infof("Campaign resumed: %s (ID: %d)", campaign.Name, id)
+	return campaign, nil
+}
+
+// RunningCampaigns mengembalikan campaign yang sedang dalam periode aktif.
+// Campaign yang periodenya sudah lewat otomatis diakhiri.
+func (c *CampaignService) RunningCampaigns(now time.Time) ([]database.Campaign, error) {
+	campaigns, err := c.repository.GetAllCampaigns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaigns: %v", err)
+	}
+
+	var running []database.Campaign
+	for _, campaign := range campaigns {
+		if campaign.Status != database.CampaignStatusActive {
+			continue
+		}
+
+		if !now.Before(campaign.EndAt) {
+			c.endCampaign(&campaign)
+			continue
+		}
+
+		if !now.Before(campaign.StartAt) {
+			running = append(running, campaign)
+		}
+	}
+
+	// Campaign yang dimulai paling akhir diprioritaskan jika grup tumpang tindih
+	sort.SliceStable(running, func(i, j int) bool {
+		return running[i].StartAt.After(running[j].StartAt)
+	})
+
+	return running, nil
+}
+
+// endCampaign menandai campaign selesai dan membatalkan sisa antriannya
+func (c *CampaignService) endCampaign(campaign *database.Campaign) {
+	if err := c.repository.UpdateCampaignStatus(campaign.ID, database.CampaignStatusEnded); err != nil {
+		c.logger.Errorf("Failed to end campaign %d: %v", campaign.ID, err)
+		return
+	}
+	c.cancelQueued(campaign)
+	c.logger.Successf("Campaign ended: %s (ID: %d)", campaign.Name, campaign.ID)
+}
+
+// cancelQueued membatalkan pesan campaign yang belum terkirim
+func (c *CampaignService) cancelQueued(campaign *database.Campaign) {
+	cancelled, err := c.repository.CancelCampaignQueuedMessages(campaign.ID)
+	if err != nil {
+		c.logger.Errorf("Failed to cancel queued messages for campaign %d: %v", campaign.ID, err)
+		return
+	}
+	if cancelled > 0 {
+		c.logger.Infof("Cancelled %d queued messages for campaign %d", cancelled, campaign.ID)
+	}
+}
+
+// GetCampaignState mengembalikan status campaign relatif terhadap periodenya
+func (c *CampaignService) GetCampaignState(campaign *database.Campaign, now time.Time) string {
+	switch {
+	case campaign.Status == database.CampaignStatusPaused:
+		return "⏸️ Dijeda"
+	case campaign.Status == database.CampaignStatusEnded || !now.Before(campaign.EndAt):
+		return "🏁 Selesai"
+	case now.Before(campaign.StartAt):
+		return "⏳ Terjadwal"
+	default:
+		return "🟢 Berjalan"
+	}
+}
+
+// GetCampaignReport menyusun laporan campaign dari promote_logs
+func (c *CampaignService) GetCampaignReport(id int) (*CampaignReport, error) {
+	campaign, err := c.GetCampaign(id)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.repository.GetLogsByCampaign(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign logs: %v", err)
+	}
+
+	report := &CampaignReport{
+		Campaign:   campaign,
+		State:      c.GetCampaignState(campaign, time.Now()),
+		ByGroup:    make(map[string]int),
+		ByTemplate: make(map[int]int),
+	}
+
+	for i := range logs {
+		log := &logs[i]
+		report.Total++
+
+		if !log.Success {
+			report.Failed++
+			continue
+		}
+
+		report.Success++
+		report.ByGroup[log.GroupJID]++
+		report.ByTemplate[log.TemplateID]++
+
+		if report.FirstSentAt == nil {
+			report.FirstSentAt = &log.SentAt
+		}
+		report.LastSentAt = &log.SentAt
+	}
+
+	return report, nil
+}
+
+// parseIDList memparse daftar ID angka yang dipisah koma
+func parseIDList(value string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("ID '%s' bukan angka", part)
+		}
+		ids = append(ids, id)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("minimal satu ID harus diisi")
+	}
+	return ids, nil
+}
@@ -0,0 +1,107 @@
+// Package services - Pencatat interaksi (read receipt dan balasan) pada pesan promosi
+package services
+
+import (
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// EngagementTracker mencocokkan read receipt dan balasan grup dengan log promosi
+// (lewat ID pesan WhatsApp) untuk laporan A/B. Read receipt hanya terkirim dari
+// anggota yang mengaktifkan laporan dibaca, jadi angkanya adalah batas bawah.
+type EngagementTracker struct {
+	repository database.Repository
+	logger     *utils.Logger
+}
+
+// NewEngagementTracker membuat pencatat interaksi dan mendaftarkannya ke client
+func NewEngagementTracker(client *whatsmeow.Client, repo database.Repository, logger *utils.Logger) *EngagementTracker {
+	tracker := &EngagementTracker{
+		repository: repo,
+		logger:     logger,
+	}
+
+	if client != nil {
+		client.AddEventHandler(func(evt interface{}) {
+			switch v := evt.(type) {
+			case *events.Receipt:
+				tracker.handleReceipt(v)
+			case *events.Message:
+				tracker.handleMessage(v)
+			}
+		})
+	}
+
+	return tracker
+}
+
+// handleReceipt mencatat read receipt anggota grup untuk pesan promosi
+func (t *EngagementTracker) handleReceipt(evt *events.Receipt) {
+	if evt.Type != types.ReceiptTypeRead || !evt.IsGroup || evt.IsFromMe {
+		return
+	}
+
+	for _, messageID := range evt.MessageIDs {
+		t.record(messageID, database.EngagementRead, evt.Sender.User, "")
+	}
+}
+
+// handleMessage mencatat pesan grup yang membalas (quote) pesan promosi
+func (t *EngagementTracker) handleMessage(evt *events.Message) {
+	if !evt.Info.IsGroup || evt.Info.IsFromMe {
+		return
+	}
+
+	contextInfo := quotedContextInfo(evt.Message)
+	if contextInfo == nil || contextInfo.GetStanzaID() == "" {
+		return
+	}
+
+	t.record(contextInfo.GetStanzaID(), database.EngagementReply, evt.Info.Sender.User, evt.Info.ID)
+}
+
+// record menyimpan interaksi jika ID pesan milik log promosi
+func (t *EngagementTracker) record(messageID, kind, participant, refID string) {
+	log, err := t.repository.GetLogByMessageID(messageID)
+	if err != nil {
+		t.logger.Errorf("Failed to look up promote log for message %s: %v", messageID, err)
+		return
+	}
+	if log == nil {
+		return
+	}
+
+	recorded, err := t.repository.RecordEngagement(log.ID, kind, participant, refID)
+	if err != nil {
+		t.logger.Errorf("Failed to record %s for promote log %d: %v", kind, log.ID, err)
+		return
+	}
+	if recorded {
+		t.logger.Debugf("Recorded %s from %s on promote log %d", kind, participant, log.ID)
+	}
+}
+
+// quotedContextInfo mengambil ContextInfo dari jenis pesan yang bisa membalas pesan lain
+func quotedContextInfo(msg *waProto.Message) *waProto.ContextInfo {
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetContextInfo()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetContextInfo()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetContextInfo()
+	case msg.GetAudioMessage() != nil:
+		return msg.GetAudioMessage().GetContextInfo()
+	case msg.GetStickerMessage() != nil:
+		return msg.GetStickerMessage().GetContextInfo()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetContextInfo()
+	default:
+		return nil
+	}
+}
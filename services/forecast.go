@@ -0,0 +1,487 @@
+// Package services - Simulasi jadwal promosi tanpa mengirim pesan (dry run)
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// maxForecastEntries membatasi jumlah slot yang disimulasikan per grup
+const maxForecastEntries = 500
+
+// ForecastEntry adalah satu pengiriman yang diperkirakan akan terjadi
+type ForecastEntry struct {
+	Time          time.Time
+	GroupJID      string
+	TemplateID    int // 0 jika tidak ada template yang bisa dipakai
+	TemplateTitle string
+	Source        string    // auto, campaign, schedule (lihat database.QueueSource*)
+	Label         string    // Nama campaign atau ID jadwal
+	DueAt         time.Time // Waktu jatuh tempo sebelum ditunda jam kirim
+	Deferred      bool      // Ditunda karena di luar jam kirim
+	Hypothetical  bool      // Grup belum aktif, disimulasikan seolah sudah diaktifkan
+}
+
+// Forecast berisi hasil simulasi processScheduledPromotes untuk rentang waktu tertentu
+type Forecast struct {
+	From          time.Time
+	Until         time.Time
+	GroupCount    int
+	TemplateCount int
+	Entries       []ForecastEntry
+	Warnings      []ForecastWarning
+}
+
+// ForecastWarning adalah konflik yang ditemukan saat simulasi
+type ForecastWarning struct {
+	GroupJID string // Kosong jika tidak terkait grup tertentu
+	Message  string
+}
+
+func (f *Forecast) warn(format string, args ...interface{}) {
+	f.warnGroup("", format, args...)
+}
+
+func (f *Forecast) warnGroup(groupJID, format string, args ...interface{}) {
+	f.Warnings = append(f.Warnings, ForecastWarning{GroupJID: groupJID, Message: fmt.Sprintf(format, args...)})
+}
+
+// forecastPeriod adalah rentang waktu grup dipegang campaign
+type forecastPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// Forecast mensimulasikan jadwal grup, interval, campaign, dan broadcast terjadwal
+// selama horizon ke depan tanpa mengirim pesan atau mengubah database.
+// extraGroupJIDs disimulasikan seolah baru diaktifkan (misal sebelum .enablemulti).
+func (s *AutoPromoteService) Forecast(horizon time.Duration, extraGroupJIDs []string) (*Forecast, error) {
+	from := s.now()
+	forecast := &Forecast{
+		From:  from,
+		Until: from.Add(horizon),
+	}
+
+	groups, err := s.repository.GetActiveGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active groups: %v", err)
+	}
+
+	hypothetical := make(map[string]bool)
+	active := make(map[string]bool)
+	for _, group := range groups {
+		active[group.GroupJID] = true
+	}
+	for _, groupJID := range extraGroupJIDs {
+		if active[groupJID] {
+			continue
+		}
+
+		// Pakai pengaturan grup yang sudah ada (jadwal, jam kirim) jika pernah diaktifkan
+		group, err := s.repository.GetAutoPromoteGroup(groupJID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group %s: %v", groupJID, err)
+		}
+		if group == nil {
+			group = &database.AutoPromoteGroup{GroupJID: groupJID}
+		}
+		group.IsActive = true
+		group.NextPromoteAt = nil
+
+		groups = append(groups, *group)
+		active[groupJID] = true
+		hypothetical[groupJID] = true
+	}
+	forecast.GroupCount = len(groups)
+
+	templates, err := s.repository.GetActiveTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active templates: %v", err)
+	}
+	forecast.TemplateCount = len(templates)
+
+	if len(groups) == 0 {
+		forecast.warn("Tidak ada grup aktif, scheduler tidak akan mengirim apa pun")
+	} else if len(templates) == 0 {
+		forecast.warn("Pool template kosong: tidak ada template aktif, rotasi default tidak akan mengirim")
+	}
+	s.warnForecastExpiry(forecast, templates)
+
+	// Template yang terpilih dalam simulasi ikut dihitung sebagai riwayat agar strategi
+	// seperti roundrobin dan norepeat terlihat bergiliran
+	history := newForecastHistory(repositoryHistory{repository: s.repository})
+
+	periods := s.forecastCampaigns(forecast, groups, hypothetical, history)
+	s.forecastScheduledJobs(forecast, groups)
+
+	subscriptions, err := s.GetAllGroupCategories()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group categories: %v", err)
+	}
+
+	for _, group := range groups {
+		// Rotasi default hanya memakai template di kategori grup (campaign tidak terpengaruh)
+		groupTemplates := filterTemplatesByCategories(templates, subscriptions[group.GroupJID])
+		if len(templates) > 0 && len(groupTemplates) == 0 {
+			forecast.warnGroup(group.GroupJID, "Tidak ada template aktif di kategori %s, rotasi default grup ini dilewati",
+				strings.Join(subscriptions[group.GroupJID], ", "))
+			continue
+		}
+
+		s.forecastGroup(forecast, &group, groupTemplates, periods[group.GroupJID], hypothetical[group.GroupJID], history)
+	}
+
+	sort.SliceStable(forecast.Entries, func(i, j int) bool {
+		return forecast.Entries[i].Time.Before(forecast.Entries[j].Time)
+	})
+
+	s.checkForecastLimits(forecast)
+	return forecast, nil
+}
+
+// forecastGroup mensimulasikan rotasi default satu grup
+func (s *AutoPromoteService) forecastGroup(forecast *Forecast, group *database.AutoPromoteGroup, templates []database.PromoteTemplate, periods []forecastPeriod, hypothetical bool, history *forecastHistory) {
+	schedule := s.scheduleForGroup(group)
+	window := s.windowForGroup(group)
+
+	due := s.dueTime(group)
+	if due.Before(forecast.From) {
+		due = forecast.From
+	}
+
+	deferredCount := 0
+	invalidCount := 0
+	for i := 0; i < maxForecastEntries; i++ {
+		// Selama campaign berjalan grup dilewati rotasi default dan langsung jatuh tempo setelahnya
+		due = skipForecastPeriods(due, periods)
+
+		at := window.NextOpen(due)
+		if at.After(forecast.Until) {
+			break
+		}
+
+		// Sama seperti scheduler: hanya template dalam masa tayang pada waktu slot yang dipakai,
+		// slot tanpa template yang berlaku dilewati
+		valid := filterValidTemplates(templates, at)
+		if len(templates) > 0 && len(valid) == 0 {
+			invalidCount++
+			due = schedule.Next(at)
+			continue
+		}
+
+		entry := ForecastEntry{
+			Time:         at,
+			GroupJID:     group.GroupJID,
+			Source:       database.QueueSourceAuto,
+			DueAt:        due,
+			Deferred:     at.After(due),
+			Hypothetical: hypothetical,
+		}
+		if len(valid) > 0 {
+			template := s.selector.SelectWithHistory(group.GroupJID, valid, history)
+			history.record(group.GroupJID, template.ID, at)
+			entry.TemplateID = template.ID
+			entry.TemplateTitle = template.Title
+		}
+		if entry.Deferred {
+			deferredCount++
+		}
+
+		forecast.Entries = append(forecast.Entries, entry)
+		due = schedule.Next(at)
+	}
+
+	if deferredCount > 0 {
+		forecast.warnGroup(group.GroupJID, "%d jadwal jatuh di luar jam kirim (%s) dan akan ditunda",
+			deferredCount, window.String())
+	}
+	if invalidCount > 0 {
+		forecast.warnGroup(group.GroupJID, "%d jadwal dilewati karena tidak ada template dalam masa berlaku", invalidCount)
+	}
+}
+
+// warnForecastExpiry menandai template yang masa berlakunya habis di dalam horizon.
+// Template tersebut dinonaktifkan otomatis oleh scheduler saat until lewat.
+func (s *AutoPromoteService) warnForecastExpiry(forecast *Forecast, templates []database.PromoteTemplate) {
+	for _, template := range templates {
+		if template.ValidUntil == nil || template.ValidUntil.After(forecast.Until) {
+			continue
+		}
+		if !template.ValidUntil.After(forecast.From) {
+			forecast.warn("Template %d (%s) sudah kedaluwarsa dan akan dinonaktifkan", template.ID, template.Title)
+			continue
+		}
+		forecast.warn("Template %d (%s) kedaluwarsa %s dan dinonaktifkan otomatis",
+			template.ID, template.Title, template.ValidUntil.In(s.location).Format(adminTimeLayout))
+	}
+}
+
+// forecastCampaigns mensimulasikan campaign aktif dan mengembalikan periode campaign per grup
+func (s *AutoPromoteService) forecastCampaigns(forecast *Forecast, groups []database.AutoPromoteGroup, hypothetical map[string]bool, history *forecastHistory) map[string][]forecastPeriod {
+	periods := make(map[string][]forecastPeriod)
+	if s.campaigns == nil {
+		return periods
+	}
+
+	campaigns, err := s.campaigns.GetAllCampaigns()
+	if err != nil {
+		forecast.warn("Gagal membaca campaign: %v", err)
+		return periods
+	}
+
+	// Sama seperti scheduler: campaign yang dimulai paling akhir diprioritaskan
+	sort.SliceStable(campaigns, func(i, j int) bool {
+		return campaigns[i].StartAt.After(campaigns[j].StartAt)
+	})
+
+	for _, campaign := range campaigns {
+		if campaign.Status != database.CampaignStatusActive ||
+			!campaign.EndAt.After(forecast.From) || campaign.StartAt.After(forecast.Until) {
+			continue
+		}
+
+		templates, err := s.campaigns.GetCampaignTemplates(&campaign)
+		if err != nil {
+			forecast.warn("Campaign %s: pool template kosong (%v)", campaign.Name, err)
+		}
+
+		targets := campaign.GroupJIDs
+		if campaign.TargetAll {
+			targets = nil
+			for _, group := range groups {
+				targets = append(targets, group.GroupJID)
+			}
+		}
+
+		start := campaign.StartAt.In(s.location)
+		if start.Before(forecast.From) {
+			start = forecast.From
+		}
+		interval := time.Duration(campaign.IntervalMinutes) * time.Minute
+
+		for _, groupJID := range targets {
+			if forecastOverlaps(periods[groupJID], start, campaign.EndAt) {
+				continue
+			}
+			periods[groupJID] = append(periods[groupJID], forecastPeriod{start: start, end: campaign.EndAt})
+
+			window := s.window
+			if group, err := s.repository.GetAutoPromoteGroup(groupJID); err == nil && group != nil {
+				window = s.windowForGroup(group)
+			}
+
+			due := start
+			if last, err := s.repository.GetLastCampaignEnqueue(campaign.ID, groupJID); err == nil && last != nil {
+				if next := last.Add(interval).In(s.location); next.After(due) {
+					due = next
+				}
+			}
+
+			invalidCount := 0
+			for i := 0; i < maxForecastEntries; i++ {
+				at := window.NextOpen(due)
+				if !at.Before(campaign.EndAt) || at.After(forecast.Until) {
+					break
+				}
+
+				valid := filterValidTemplates(templates, at)
+				if len(templates) > 0 && len(valid) == 0 {
+					invalidCount++
+					due = at.Add(interval)
+					continue
+				}
+
+				entry := ForecastEntry{
+					Time:         at,
+					GroupJID:     groupJID,
+					Source:       database.QueueSourceCampaign,
+					Label:        campaign.Name,
+					DueAt:        due,
+					Deferred:     at.After(due),
+					Hypothetical: hypothetical[groupJID],
+				}
+				if len(valid) > 0 {
+					template := s.selector.SelectWithHistory(groupJID, valid, history)
+					history.record(groupJID, template.ID, at)
+					entry.TemplateID = template.ID
+					entry.TemplateTitle = template.Title
+				}
+
+				forecast.Entries = append(forecast.Entries, entry)
+				due = at.Add(interval)
+			}
+
+			if invalidCount > 0 {
+				forecast.warnGroup(groupJID, "Campaign %s: %d slot dilewati karena tidak ada template dalam masa berlaku",
+					campaign.Name, invalidCount)
+			}
+		}
+	}
+
+	return periods
+}
+
+// forecastScheduledJobs menambahkan broadcast terjadwal yang jatuh dalam horizon
+func (s *AutoPromoteService) forecastScheduledJobs(forecast *Forecast, groups []database.AutoPromoteGroup) {
+	if s.jobs == nil {
+		return
+	}
+
+	jobs, err := s.jobs.GetJobs(false)
+	if err != nil {
+		forecast.warn("Gagal membaca jadwal broadcast: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		at := job.RunAt.In(s.location)
+		if at.After(forecast.Until) {
+			continue
+		}
+		if at.Before(forecast.From) {
+			at = forecast.From
+		}
+
+		template, err := s.repository.GetTemplateByID(job.TemplateID)
+		if err != nil || template == nil {
+			forecast.warn("Jadwal #%d: template %d tidak ditemukan, broadcast akan gagal", job.ID, job.TemplateID)
+			continue
+		}
+
+		targets := job.GroupJIDs
+		if job.TargetAll {
+			targets = nil
+			for _, group := range groups {
+				targets = append(targets, group.GroupJID)
+			}
+		}
+
+		for _, groupJID := range targets {
+			forecast.Entries = append(forecast.Entries, ForecastEntry{
+				Time:          at,
+				GroupJID:      groupJID,
+				TemplateID:    template.ID,
+				TemplateTitle: template.Title,
+				Source:        database.QueueSourceSchedule,
+				Label:         fmt.Sprintf("Jadwal #%d", job.ID),
+				DueAt:         at,
+			})
+		}
+	}
+}
+
+// checkForecastLimits menandai hari yang melebihi batas harian atau menit dengan burst
+func (s *AutoPromoteService) checkForecastLimits(forecast *Forecast) {
+	status := s.queue.GetStatus()
+
+	perDay := make(map[string]int)
+	perMinute := make(map[string]int)
+	for _, entry := range forecast.Entries {
+		perDay[entry.Time.Format("2006-01-02")]++
+		perMinute[entry.Time.Format("2006-01-02 15:04")]++
+	}
+
+	var days []string
+	for day := range perDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		if status.DailyLimit > 0 && perDay[day] > status.DailyLimit {
+			forecast.warn("%s: %d pesan melebihi batas harian %d, sisanya tertunda ke hari berikutnya",
+				day, perDay[day], status.DailyLimit)
+		}
+	}
+
+	bursts := 0
+	for _, count := range perMinute {
+		if status.PerMinuteLimit > 0 && count > status.PerMinuteLimit {
+			bursts++
+		}
+	}
+	if bursts > 0 {
+		forecast.warn("%d menit memiliki lebih dari %d pesan sekaligus, antrian akan menyebarnya",
+			bursts, status.PerMinuteLimit)
+	}
+}
+
+// forecastHistory menambahkan kiriman simulasi di atas riwayat promote_logs
+type forecastHistory struct {
+	base TemplateHistory
+	sent map[string][]forecastSend // Kiriman simulasi per grup, terlama dulu
+}
+
+type forecastSend struct {
+	templateID int
+	at         time.Time
+}
+
+func newForecastHistory(base TemplateHistory) *forecastHistory {
+	return &forecastHistory{
+		base: base,
+		sent: make(map[string][]forecastSend),
+	}
+}
+
+func (h *forecastHistory) record(groupJID string, templateID int, at time.Time) {
+	h.sent[groupJID] = append(h.sent[groupJID], forecastSend{templateID: templateID, at: at})
+}
+
+func (h *forecastHistory) RecentTemplateIDs(groupJID string, limit int) ([]int, error) {
+	var ids []int
+	sent := h.sent[groupJID]
+	for i := len(sent) - 1; i >= 0 && len(ids) < limit; i-- {
+		ids = append(ids, sent[i].templateID)
+	}
+	if len(ids) >= limit {
+		return ids, nil
+	}
+
+	base, err := h.base.RecentTemplateIDs(groupJID, limit-len(ids))
+	if err != nil {
+		return nil, err
+	}
+	return append(ids, base...), nil
+}
+
+func (h *forecastHistory) LastSentTimes(groupJID string) (map[int]time.Time, error) {
+	lastSent, err := h.base.LastSentTimes(groupJID)
+	if err != nil {
+		return nil, err
+	}
+	for _, send := range h.sent[groupJID] {
+		if send.at.After(lastSent[send.templateID]) {
+			lastSent[send.templateID] = send.at
+		}
+	}
+	return lastSent, nil
+}
+
+// skipForecastPeriods memajukan waktu ke akhir periode campaign yang sedang berjalan
+func skipForecastPeriods(t time.Time, periods []forecastPeriod) time.Time {
+	for moved := true; moved; {
+		moved = false
+		for _, period := range periods {
+			if !t.Before(period.start) && t.Before(period.end) {
+				t = period.end.In(t.Location())
+				moved = true
+			}
+		}
+	}
+	return t
+}
+
+// forecastOverlaps mengecek apakah rentang waktu beririsan dengan periode yang sudah ada
+func forecastOverlaps(periods []forecastPeriod, start, end time.Time) bool {
+	for _, period := range periods {
+		if start.Before(period.end) && period.start.Before(end) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+// Package services - Cache info grup WhatsApp untuk variabel template
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// groupInfoCacheTTL adalah lama info grup disimpan sebelum diambil ulang dari WhatsApp
+const groupInfoCacheTTL = 30 * time.Minute
+
+// GroupInfoCache menyimpan types.GroupInfo sementara agar render template
+// tidak memanggil WhatsApp untuk setiap pesan. Entry dihapus otomatis saat
+// ada event perubahan info grup.
+type GroupInfoCache struct {
+	client  *whatsmeow.Client
+	logger  *utils.Logger
+	mutex   sync.Mutex
+	entries map[string]groupInfoEntry
+}
+
+type groupInfoEntry struct {
+	info      *types.GroupInfo
+	fetchedAt time.Time
+}
+
+// NewGroupInfoCache membuat cache info grup baru
+func NewGroupInfoCache(client *whatsmeow.Client, logger *utils.Logger) *GroupInfoCache {
+	cache := &GroupInfoCache{
+		client:  client,
+		logger:  logger,
+		entries: make(map[string]groupInfoEntry),
+	}
+
+	if client != nil {
+		client.AddEventHandler(func(evt interface{}) {
+			if change, ok := evt.(*events.GroupInfo); ok {
+				cache.Invalidate(change.JID.String())
+			}
+		})
+	}
+
+	return cache
+}
+
+// Get mengambil info grup dari cache atau dari WhatsApp jika sudah kedaluwarsa.
+// Jika WhatsApp gagal dihubungi, info lama tetap dipakai bila ada.
+func (c *GroupInfoCache) Get(groupJID string) (*types.GroupInfo, error) {
+	c.mutex.Lock()
+	entry, ok := c.entries[groupJID]
+	c.mutex.Unlock()
+
+	if ok && time.Since(entry.fetchedAt) < groupInfoCacheTTL {
+		return entry.info, nil
+	}
+
+	if c.client == nil {
+		return nil, fmt.Errorf("client WhatsApp tidak tersedia")
+	}
+
+	jid, err := types.ParseJID(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid group JID: %v", err)
+	}
+
+	info, err := c.client.GetGroupInfo(jid)
+	if err != nil {
+		if ok {
+			c.logger.Warningf("Failed to refresh group info for %s, using cached copy: %v", groupJID, err)
+			return entry.info, nil
+		}
+		return nil, fmt.Errorf("failed to get group info: %v", err)
+	}
+
+	c.Store(info)
+	return info, nil
+}
+
+// Store menyimpan info grup yang sudah didapat dari tempat lain (misal GetJoinedGroups)
+func (c *GroupInfoCache) Store(info *types.GroupInfo) {
+	if info == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[info.JID.String()] = groupInfoEntry{info: info, fetchedAt: time.Now()}
+}
+
+// Invalidate menghapus info grup dari cache
+func (c *GroupInfoCache) Invalidate(groupJID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.entries, groupJID)
+}
@@ -2,15 +2,10 @@
 package services
 
 import (
-	"context"
 	"fmt"
-	"math/rand"
-	"strings"
 	"time"
 
 	"go.mau.fi/whatsmeow"
-	waProto "go.mau.fi/whatsmeow/binary/proto"
-	"go.mau.fi/whatsmeow/types"
 
 	"github.com/nabilulilalbab/promote/database"
 	"github.com/nabilulilalbab/promote/utils"
@@ -30,21 +25,31 @@ type GroupInfo struct {
 type GroupManagerService struct {
 	client     *whatsmeow.Client
 	repository database.Repository
+	queue      *SendQueueService
+	selector   *TemplateSelector
+	renderer   *TemplateRenderer
 	logger     *utils.Logger
+	location   *time.Location // Timezone scheduler untuk masa tayang dan variabel waktu
 }
 
 // NewGroupManagerService membuat service baru
-func NewGroupManagerService(client *whatsmeow.Client, repo database.Repository, logger *utils.Logger) *GroupManagerService {
-	// Inisialisasi random seed untuk template selection
-	rand.Seed(time.Now().UnixNano())
-
+func NewGroupManagerService(client *whatsmeow.Client, repo database.Repository, queue *SendQueueService, selector *TemplateSelector, renderer *TemplateRenderer, logger *utils.Logger) *GroupManagerService {
 	return &GroupManagerService{
 		client:     client,
 		repository: repo,
+		queue:      queue,
+		selector:   selector,
+		renderer:   renderer,
 		logger:     logger,
+		location:   time.Local,
 	}
 }
 
+// SetLocation mengatur timezone scheduler agar test kirim sama dengan kiriman sebenarnya
+func (s *GroupManagerService) SetLocation(location *time.Location) {
+	s.location = location
+}
+
 // GetAllJoinedGroups mengambil semua grup yang diikuti bot dari WhatsApp
 func (s *GroupManagerService) GetAllJoinedGroups() ([]GroupInfo, error) {
 	s.logger.Info("Getting all joined groups from WhatsApp...")
@@ -62,6 +67,9 @@ func (s *GroupManagerService) GetAllJoinedGroups() ([]GroupInfo, error) {
 		// group adalah *types.GroupInfo, bukan JID
 		groupJID := group.JID
 
+		// Simpan ke cache agar variabel {GROUP_NAME} dkk tidak perlu memanggil WhatsApp lagi
+		s.renderer.CacheGroupInfo(group)
+
 		// Cek status auto promote dari database
 		dbGroup, err := s.repository.GetAutoPromoteGroup(groupJID.String())
 		isActive := false
@@ -202,7 +210,7 @@ func (s *GroupManagerService) GetGroupStatus(groupID int) (*GroupInfo, *database
 	return groupInfo, dbGroup, nil
 }
 
-// SendTestPromoteToGroup mengirim test promosi ke grup tertentu
+// SendTestPromoteToGroup memasukkan test promosi ke antrian kirim untuk grup tertentu
 func (s *GroupManagerService) SendTestPromoteToGroup(groupID int) error {
 	// Ambil info grup
 	groupInfo, err := s.GetGroupByID(groupID)
@@ -212,105 +220,39 @@ func (s *GroupManagerService) SendTestPromoteToGroup(groupID int) error {
 
 	s.logger.Infof("Sending test promote to group: %s (%s)", groupInfo.Name, groupInfo.JID)
 
-	// Ambil template aktif
-	templates, err := s.repository.GetActiveTemplates()
+	// Ambil template aktif sesuai kategori grup
+	now := time.Now().In(s.location)
+	templates, categories, err := activeTemplatesForGroup(s.repository, groupInfo.JID, now)
 	if err != nil {
-		return fmt.Errorf("failed to get templates: %v", err)
+		return err
 	}
 
 	if len(templates) == 0 {
-		return fmt.Errorf("no active templates available")
-	}
-
-	// Pilih template secara random untuk test (sama seperti auto promote)
-	template := s.selectRandomTemplate(templates)
-
-	// Parse JID grup
-	jid, err := types.ParseJID(groupInfo.JID)
-	if err != nil {
-		return fmt.Errorf("invalid group JID: %v", err)
+		return noTemplatesForCategoriesError(categories)
 	}
 
-	// Proses template (replace variables)
-	content := s.processTemplate(template.Content, jid)
+	// Pilih template dengan strategi yang sama seperti auto promote
+	template := s.selector.Select(groupInfo.JID, templates)
 
-	// Kirim pesan promosi natural (tanpa embel-embel test)
-	err = s.sendMessage(jid, content)
+	// Pilih varian A/B lalu render (variabel, kondisi, loop)
+	content, variantID, err := s.renderer.RenderTemplate(&template, groupInfo.JID, now)
 	if err != nil {
-		return fmt.Errorf("failed to send test message: %v", err)
-	}
-
-	s.logger.Successf("Test promote sent to group: %s", groupInfo.Name)
-	return nil
-}
-
-// processTemplate memproses template dengan mengganti variables
-func (s *GroupManagerService) processTemplate(content string, groupJID types.JID) string {
-	now := time.Now()
-
-	// Replace variables yang tersedia
-	replacements := map[string]string{
-		"{DATE}":     now.Format("2006-01-02"),
-		"{TIME}":     now.Format("15:04"),
-		"{DAY}":      s.getDayName(now.Weekday()),
-		"{MONTH}":    s.getMonthName(now.Month()),
-		"{YEAR}":     fmt.Sprintf("%d", now.Year()),
-		"{GROUP_ID}": groupJID.User,
-	}
-
-	result := content
-	for placeholder, value := range replacements {
-		result = strings.ReplaceAll(result, placeholder, value)
+		return err
 	}
 
-	return result
-}
-
-// getDayName helper function
-func (s *GroupManagerService) getDayName(day time.Weekday) string {
-	days := []string{
-		"Minggu", "Senin", "Selasa", "Rabu",
-		"Kamis", "Jumat", "Sabtu",
+	// Antrikan pesan promosi natural (tanpa embel-embel test), tetap tunduk pada rate limit
+	msg := &database.QueuedMessage{
+		GroupJID:    groupInfo.JID,
+		TemplateID:  template.ID,
+		Content:     content,
+		Source:      database.QueueSourceTest,
+		MaxAttempts: 1,
+		VariantID:   variantID,
 	}
-	return days[day]
-}
-
-// getMonthName helper function
-func (s *GroupManagerService) getMonthName(month time.Month) string {
-	months := []string{
-		"", "Januari", "Februari", "Maret", "April", "Mei", "Juni",
-		"Juli", "Agustus", "September", "Oktober", "November", "Desember",
-	}
-	return months[month]
-}
-
-// sendMessage mengirim pesan ke grup
-func (s *GroupManagerService) sendMessage(groupJID types.JID, content string) error {
-	// Buat pesan WhatsApp
-	msg := &waProto.Message{
-		Conversation: &content,
+	if err := s.queue.EnqueueMessage(msg, 0); err != nil {
+		return fmt.Errorf("failed to queue test message: %v", err)
 	}
 
-	// Kirim pesan
-	_, err := s.client.SendMessage(context.Background(), groupJID, msg)
-	if err != nil {
-		return fmt.Errorf("failed to send message: %v", err)
-	}
-
-	s.logger.Infof("Message sent to group: %s", groupJID.String())
+	s.logger.Successf("Test promote queued for group: %s", groupInfo.Name)
 	return nil
 }
-
-// selectRandomTemplate memilih template secara random
-func (s *GroupManagerService) selectRandomTemplate(templates []database.PromoteTemplate) database.PromoteTemplate {
-	if len(templates) == 0 {
-		// Return empty template jika tidak ada
-		return database.PromoteTemplate{}
-	}
-
-	// Pilih index random
-	index := rand.Intn(len(templates))
-	return templates[index]
-}
-
-// Helper functions - menggunakan yang sudah ada di auto_promote.go
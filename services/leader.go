@@ -0,0 +1,219 @@
+// Package services - Leader election berbasis lease di database promote
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// schedulerLeaseName adalah nama lease untuk scheduler dan send queue
+const schedulerLeaseName = "scheduler"
+
+// leaseTTL adalah lama lease berlaku tanpa heartbeat sebelum standby boleh mengambil alih
+const leaseTTL = 45 * time.Second
+
+// leaseHeartbeatInterval adalah seberapa sering leader memperpanjang lease
+// (dan standby mencoba mengambil alih). Harus jauh lebih kecil dari leaseTTL.
+const leaseHeartbeatInterval = 15 * time.Second
+
+// LeaderService memastikan hanya satu proses yang menjalankan scheduler
+// saat beberapa proses memakai volume data/ yang sama (misal saat deploy/restart).
+type LeaderService struct {
+	repository database.Repository
+	logger     *utils.Logger
+	instanceID string
+
+	onElected func() // Dipanggil saat instance ini menjadi leader
+	onDemoted func() // Dipanggil saat instance ini kehilangan leadership
+
+	mutex     sync.RWMutex
+	isLeader  bool
+	isRunning bool
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+}
+
+// LeaderStatus berisi ringkasan kondisi lease untuk ditampilkan ke admin
+type LeaderStatus struct {
+	InstanceID  string
+	IsLeader    bool
+	Holder      string     // Kosong jika belum ada yang memegang lease
+	AcquiredAt  *time.Time // Kapan leader saat ini mengambil lease
+	HeartbeatAt *time.Time // Heartbeat terakhir leader
+	Expired     bool       // Lease kedaluwarsa (leader mati tanpa melepas lease)
+}
+
+// NewLeaderService membuat service leader election baru.
+// instanceID kosong berarti memakai hostname dan PID proses.
+func NewLeaderService(repo database.Repository, instanceID string, logger *utils.Logger) *LeaderService {
+	if instanceID == "" {
+		instanceID = defaultInstanceID()
+	}
+
+	return &LeaderService{
+		repository: repo,
+		logger:     logger,
+		instanceID: instanceID,
+	}
+}
+
+// OnElected mengatur callback saat instance ini menjadi leader
+func (l *LeaderService) OnElected(fn func()) {
+	l.onElected = fn
+}
+
+// OnDemoted mengatur callback saat instance ini berhenti menjadi leader
+func (l *LeaderService) OnDemoted(fn func()) {
+	l.onDemoted = fn
+}
+
+// Start mulai mencoba mengambil lease dan mengirim heartbeat secara berkala
+func (l *LeaderService) Start() {
+	l.mutex.Lock()
+	if l.isRunning {
+		l.mutex.Unlock()
+		return
+	}
+	l.isRunning = true
+	l.stopChan = make(chan struct{})
+	l.doneChan = make(chan struct{})
+	stopChan, doneChan := l.stopChan, l.doneChan
+	l.mutex.Unlock()
+
+	l.logger.Infof("Starting leader election as %s", l.instanceID)
+
+	// Percobaan pertama dilakukan langsung agar leader tidak menunggu satu interval
+	l.heartbeat()
+
+	go func() {
+		defer close(doneChan)
+
+		ticker := time.NewTicker(leaseHeartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				l.heartbeat()
+			case <-stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// Stop menghentikan heartbeat, melepas leadership, dan melepas lease
+func (l *LeaderService) Stop() {
+	l.mutex.Lock()
+	if !l.isRunning {
+		l.mutex.Unlock()
+		return
+	}
+	l.isRunning = false
+	close(l.stopChan)
+	doneChan := l.doneChan
+	l.mutex.Unlock()
+
+	<-doneChan
+
+	if l.IsLeader() {
+		l.demote()
+		if err := l.repository.ReleaseLease(schedulerLeaseName, l.instanceID); err != nil {
+			l.logger.Errorf("Failed to release scheduler lease: %v", err)
+		} else {
+			l.logger.Info("Scheduler lease released")
+		}
+	}
+}
+
+// IsLeader mengecek apakah instance ini sedang memegang lease
+func (l *LeaderService) IsLeader() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.isLeader
+}
+
+// GetInstanceID mengembalikan ID instance ini
+func (l *LeaderService) GetInstanceID() string {
+	return l.instanceID
+}
+
+// GetStatus mengembalikan kondisi lease dari database
+func (l *LeaderService) GetStatus() (LeaderStatus, error) {
+	status := LeaderStatus{
+		InstanceID: l.instanceID,
+		IsLeader:   l.IsLeader(),
+	}
+
+	lease, err := l.repository.GetLease(schedulerLeaseName)
+	if err != nil {
+		return status, fmt.Errorf("failed to get scheduler lease: %v", err)
+	}
+	if lease == nil {
+		return status, nil
+	}
+
+	status.Holder = lease.Holder
+	status.AcquiredAt = &lease.AcquiredAt
+	status.HeartbeatAt = &lease.HeartbeatAt
+	status.Expired = !time.Now().Before(lease.ExpiresAt)
+	return status, nil
+}
+
+// heartbeat mengambil atau memperpanjang lease, lalu menyesuaikan status leader
+func (l *LeaderService) heartbeat() {
+	acquired, err := l.repository.AcquireLease(schedulerLeaseName, l.instanceID, time.Now(), leaseTTL)
+	if err != nil {
+		// Jika heartbeat gagal, lease bisa kedaluwarsa dan diambil instance lain.
+		// Berhenti lebih awal lebih aman daripada dua scheduler berjalan bersamaan.
+		l.logger.Errorf("Failed to renew scheduler lease: %v", err)
+		acquired = false
+	}
+
+	switch {
+	case acquired && !l.IsLeader():
+		l.promote()
+	case !acquired && l.IsLeader():
+		l.logger.Warning("Lost scheduler lease, switching to standby")
+		l.demote()
+	case !acquired && err == nil:
+		l.logger.Debugf("Standby: scheduler lease held by another instance")
+	}
+}
+
+// promote menjadikan instance ini leader dan menjalankan callback
+func (l *LeaderService) promote() {
+	l.mutex.Lock()
+	l.isLeader = true
+	l.mutex.Unlock()
+
+	l.logger.Successf("Acquired scheduler lease, %s is now the leader", l.instanceID)
+	if l.onElected != nil {
+		l.onElected()
+	}
+}
+
+// demote melepas status leader dan menjalankan callback
+func (l *LeaderService) demote() {
+	l.mutex.Lock()
+	l.isLeader = false
+	l.mutex.Unlock()
+
+	if l.onDemoted != nil {
+		l.onDemoted()
+	}
+}
+
+// defaultInstanceID membuat ID instance dari hostname dan PID
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
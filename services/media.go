@@ -0,0 +1,246 @@
+// Package services - Penyimpanan media template dan pembuatan pesan media WhatsApp
+package services
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// MaxMediaSize adalah ukuran maksimal media template (batas WhatsApp untuk gambar/video)
+const MaxMediaSize = 16 << 20
+
+// MediaStore menyimpan file media template di folder lokal (default data/media).
+// Database hanya menyimpan nama file sehingga folder bisa dipindah bersama database.
+type MediaStore struct {
+	dir    string
+	logger *utils.Logger
+}
+
+// NewMediaStore membuat penyimpanan media dan memastikan foldernya ada
+func NewMediaStore(dir string, logger *utils.Logger) (*MediaStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+
+	return &MediaStore{
+		dir:    dir,
+		logger: logger,
+	}, nil
+}
+
+// Save menyimpan media untuk template dan mengembalikan nama filenya
+func (m *MediaStore) Save(templateID int, data []byte, mimetype string) (string, error) {
+	if len(data) > MaxMediaSize {
+		return "", fmt.Errorf("media terlalu besar (maksimal %d MB)", MaxMediaSize>>20)
+	}
+
+	ext := ".bin"
+	if exts, err := mime.ExtensionsByType(mimetype); err == nil && len(exts) > 0 {
+		ext = exts[0]
+	}
+
+	name := fmt.Sprintf("template_%d_%d%s", templateID, time.Now().UnixNano(), ext)
+	if err := os.WriteFile(filepath.Join(m.dir, name), data, 0644); err != nil {
+		return "", fmt.Errorf("gagal menyimpan media: %v", err)
+	}
+	return name, nil
+}
+
+// Read membaca media berdasarkan nama file yang tersimpan di template
+func (m *MediaStore) Read(name string) ([]byte, error) {
+	return os.ReadFile(m.path(name))
+}
+
+// Exists mengecek apakah file media ada di folder media
+func (m *MediaStore) Exists(name string) bool {
+	_, err := os.Stat(m.path(name))
+	return err == nil
+}
+
+// Remove menghapus file media yang sudah tidak dipakai
+func (m *MediaStore) Remove(name string) {
+	if name == "" {
+		return
+	}
+	if err := os.Remove(m.path(name)); err != nil && !os.IsNotExist(err) {
+		m.logger.Warningf("Failed to remove media %s: %v", name, err)
+	}
+}
+
+// path membatasi nama file ke dalam folder media
+func (m *MediaStore) path(name string) string {
+	return filepath.Join(m.dir, filepath.Base(name))
+}
+
+// NewMediaMessage mengunggah media lalu membuat pesan gambar, video atau dokumen
+// dengan caption. fileName hanya dipakai untuk dokumen.
+func NewMediaMessage(ctx context.Context, client *whatsmeow.Client, mediaType string, data []byte, mimetype, fileName, caption string) (*waProto.Message, error) {
+	appInfo, err := whatsmeowMediaType(mediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := client.Upload(ctx, data, appInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload media: %v", err)
+	}
+
+	fileLength := uploaded.FileLength
+	switch mediaType {
+	case database.MediaImage:
+		return &waProto.Message{
+			ImageMessage: &waProto.ImageMessage{
+				URL:           &uploaded.URL,
+				DirectPath:    &uploaded.DirectPath,
+				MediaKey:      uploaded.MediaKey,
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    &fileLength,
+				Mimetype:      &mimetype,
+				Caption:       &caption,
+			},
+		}, nil
+	case database.MediaVideo:
+		return &waProto.Message{
+			VideoMessage: &waProto.VideoMessage{
+				URL:           &uploaded.URL,
+				DirectPath:    &uploaded.DirectPath,
+				MediaKey:      uploaded.MediaKey,
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    &fileLength,
+				Mimetype:      &mimetype,
+				Caption:       &caption,
+			},
+		}, nil
+	default:
+		return &waProto.Message{
+			DocumentMessage: &waProto.DocumentMessage{
+				URL:           &uploaded.URL,
+				DirectPath:    &uploaded.DirectPath,
+				MediaKey:      uploaded.MediaKey,
+				FileEncSHA256: uploaded.FileEncSHA256,
+				FileSHA256:    uploaded.FileSHA256,
+				FileLength:    &fileLength,
+				Mimetype:      &mimetype,
+				FileName:      &fileName,
+				Title:         &fileName,
+				Caption:       &caption,
+			},
+		}, nil
+	}
+}
+
+// whatsmeowMediaType memetakan jenis media template ke jenis upload whatsmeow
+func whatsmeowMediaType(mediaType string) (whatsmeow.MediaType, error) {
+	switch mediaType {
+	case database.MediaImage:
+		return whatsmeow.MediaImage, nil
+	case database.MediaVideo:
+		return whatsmeow.MediaVideo, nil
+	case database.MediaDocument:
+		return whatsmeow.MediaDocument, nil
+	default:
+		return "", fmt.Errorf("jenis media '%s' tidak dikenal", mediaType)
+	}
+}
+
+// MediaTypeLabel menampilkan jenis media dalam bahasa Indonesia
+func MediaTypeLabel(mediaType string) string {
+	switch mediaType {
+	case database.MediaImage:
+		return "🖼️ Gambar"
+	case database.MediaVideo:
+		return "🎬 Video"
+	case database.MediaDocument:
+		return "📄 Dokumen"
+	default:
+		return "Tanpa media"
+	}
+}
+
+// templateMediaLine menampilkan media template di preview (kosong jika tanpa media)
+func templateMediaLine(template *database.PromoteTemplate) string {
+	if template.MediaType == "" {
+		return ""
+	}
+	return fmt.Sprintf("📎 *Media:* %s (%s) - konten dikirim sebagai caption\n", MediaTypeLabel(template.MediaType), template.MediaName)
+}
+
+// AttachTemplateMedia menyimpan media lalu memasangnya ke template.
+// Media lama template dihapus dari folder media.
+func (s *TemplateService) AttachTemplateMedia(templateID int, mediaType string, data []byte, mimetype, name string) (*database.PromoteTemplate, error) {
+	if s.media == nil {
+		return nil, fmt.Errorf("penyimpanan media belum diatur")
+	}
+
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := whatsmeowMediaType(mediaType); err != nil {
+		return nil, err
+	}
+
+	mimetype = strings.TrimSpace(strings.Split(mimetype, ";")[0])
+	path, err := s.media.Save(templateID, data, mimetype)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = path
+	}
+
+	if _, err := s.repository.SetTemplateMedia(templateID, mediaType, path, mimetype, name); err != nil {
+		s.media.Remove(path)
+		s.logger.Errorf("Failed to attach media to template %d: %v", templateID, err)
+		return nil, fmt.Errorf("gagal menyimpan media template: %v", err)
+	}
+
+	s.media.Remove(template.MediaPath)
+
+	template.MediaType = mediaType
+	template.MediaPath = path
+	template.MediaMimetype = mimetype
+	template.MediaName = name
+
+	s.logger.Successf("Media %s attached to template %d (%s)", mediaType, templateID, path)
+	return template, nil
+}
+
+// DetachTemplateMedia melepas media template sehingga kembali dikirim sebagai teks
+func (s *TemplateService) DetachTemplateMedia(templateID int) error {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return err
+	}
+
+	if template.MediaType == "" {
+		return fmt.Errorf("template ID %d tidak punya media", templateID)
+	}
+
+	if _, err := s.repository.SetTemplateMedia(templateID, "", "", "", ""); err != nil {
+		s.logger.Errorf("Failed to detach media from template %d: %v", templateID, err)
+		return fmt.Errorf("gagal melepas media template: %v", err)
+	}
+
+	if s.media != nil {
+		s.media.Remove(template.MediaPath)
+	}
+
+	s.logger.Infof("Media detached from template %d", templateID)
+	return nil
+}
@@ -0,0 +1,345 @@
+// Package services - Parser jadwal promosi per grup (interval, jam tetap, cron)
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule menentukan kapan promosi berikutnya jatuh tempo
+type Schedule interface {
+	// Next mengembalikan waktu jatuh tempo pertama setelah waktu yang diberikan
+	Next(after time.Time) time.Time
+
+	// String mengembalikan ekspresi jadwal dalam format yang bisa diparse ulang
+	String() string
+}
+
+// ParseSchedule memparse ekspresi jadwal grup.
+// Format yang didukung:
+//   - "every 3h", "every 90m", "every 1h30m" (interval tetap sejak promosi terakhir)
+//   - "08:00,12:00,19:00" (jam tetap setiap hari)
+//   - "0 8,12,19 * * *" atau "cron 0 8 * * 1-5" (cron 5 kolom: menit jam tanggal bulan hari)
+func ParseSchedule(expr string) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("ekspresi jadwal tidak boleh kosong")
+	}
+
+	lower := strings.ToLower(expr)
+
+	switch {
+	case strings.HasPrefix(lower, "every "):
+		return parseIntervalSchedule(strings.TrimSpace(lower[len("every "):]))
+	case strings.HasPrefix(lower, "cron "):
+		return parseCronSchedule(strings.TrimSpace(lower[len("cron "):]))
+	case len(strings.Fields(lower)) == 5:
+		return parseCronSchedule(lower)
+	case strings.Contains(lower, ":"):
+		return parseDailySchedule(lower)
+	}
+
+	return nil, fmt.Errorf("format jadwal tidak dikenal: %s", expr)
+}
+
+// NewIntervalSchedule membuat jadwal interval tetap
+func NewIntervalSchedule(interval time.Duration) Schedule {
+	return intervalSchedule{interval: interval}
+}
+
+// ParseInterval memparse durasi interval seperti "90m", "2h", atau "1h30m".
+// Angka tanpa satuan dianggap jam.
+func ParseInterval(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), " ", ""))
+
+	if hours, err := strconv.Atoi(value); err == nil {
+		value = fmt.Sprintf("%dh", hours)
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("interval tidak valid: %s (contoh: 90m, 2h, 1h30m)", value)
+	}
+
+	if interval < time.Minute {
+		return 0, fmt.Errorf("interval minimal 1 menit")
+	}
+
+	return interval, nil
+}
+
+// intervalSchedule jatuh tempo setiap interval tertentu
+type intervalSchedule struct {
+	interval time.Duration
+}
+
+func parseIntervalSchedule(value string) (Schedule, error) {
+	interval, err := time.ParseDuration(strings.ReplaceAll(value, " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("interval tidak valid: %s", value)
+	}
+
+	if interval < time.Minute {
+		return nil, fmt.Errorf("interval minimal 1 menit")
+	}
+
+	return intervalSchedule{interval: interval}, nil
+}
+
+func (s intervalSchedule) Next(after time.Time) time.Time {
+	return after.Add(s.interval)
+}
+
+func (s intervalSchedule) String() string {
+	return "every " + formatScheduleDuration(s.interval)
+}
+
+// dailySchedule jatuh tempo pada jam-jam tetap setiap hari
+type dailySchedule struct {
+	minutes []int // Menit sejak tengah malam, terurut
+}
+
+func parseDailySchedule(value string) (Schedule, error) {
+	var minutes []int
+	seen := make(map[int]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		minute, err := parseClock(part)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[minute] {
+			seen[minute] = true
+			minutes = append(minutes, minute)
+		}
+	}
+
+	if len(minutes) == 0 {
+		return nil, fmt.Errorf("minimal satu jam harus ditentukan")
+	}
+
+	sort.Ints(minutes)
+	return dailySchedule{minutes: minutes}, nil
+}
+
+func (s dailySchedule) Next(after time.Time) time.Time {
+	midnight := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())
+
+	for day := 0; day <= 1; day++ {
+		base := midnight.AddDate(0, 0, day)
+		for _, minute := range s.minutes {
+			candidate := base.Add(time.Duration(minute) * time.Minute)
+			if candidate.After(after) {
+				return candidate
+			}
+		}
+	}
+
+	// Tidak terjangkau: selalu ada slot di hari berikutnya
+	return midnight.AddDate(0, 0, 1).Add(time.Duration(s.minutes[0]) * time.Minute)
+}
+
+func (s dailySchedule) String() string {
+	var parts []string
+	for _, minute := range s.minutes {
+		parts = append(parts, formatClock(minute))
+	}
+	return strings.Join(parts, ",")
+}
+
+// cronSchedule jatuh tempo mengikuti spesifikasi cron 5 kolom
+type cronSchedule struct {
+	spec   string
+	minute map[int]bool
+	hour   map[int]bool
+	dom    map[int]bool
+	month  map[int]bool
+	dow    map[int]bool
+	anyDom bool
+	anyDow bool
+}
+
+func parseCronSchedule(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron harus terdiri dari 5 kolom (menit jam tanggal bulan hari)")
+	}
+
+	minute, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("kolom menit: %v", err)
+	}
+	hour, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("kolom jam: %v", err)
+	}
+	dom, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("kolom tanggal: %v", err)
+	}
+	month, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("kolom bulan: %v", err)
+	}
+	dow, err := parseCronField(fields[4], 0, 7)
+	if err != nil {
+		return nil, fmt.Errorf("kolom hari: %v", err)
+	}
+
+	// 7 juga berarti Minggu
+	if dow[7] {
+		dow[0] = true
+		delete(dow, 7)
+	}
+
+	return cronSchedule{
+		spec:   strings.Join(fields, " "),
+		minute: minute,
+		hour:   hour,
+		dom:    dom,
+		month:  month,
+		dow:    dow,
+		anyDom: fields[2] == "*",
+		anyDow: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField memparse satu kolom cron (mendukung *, a-b, a,b dan /step)
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("step tidak valid: %s", part)
+			}
+			step = s
+			part = part[:idx]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			a, errA := strconv.Atoi(bounds[0])
+			b, errB := strconv.Atoi(bounds[1])
+			if errA != nil || errB != nil {
+				return nil, fmt.Errorf("rentang tidak valid: %s", part)
+			}
+			start, end = a, b
+		default:
+			v, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("nilai tidak valid: %s", part)
+			}
+			start, end = v, v
+			if step > 1 {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("nilai di luar rentang %d-%d: %s", min, max, part)
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+func (s cronSchedule) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !s.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !s.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	// Spesifikasi yang tidak pernah terpenuhi (misal 31 Februari)
+	return limit
+}
+
+// matchDay mengikuti aturan cron: jika tanggal dan hari sama-sama dibatasi, salah satu cukup
+func (s cronSchedule) matchDay(t time.Time) bool {
+	domMatch := s.dom[t.Day()]
+	dowMatch := s.dow[int(t.Weekday())]
+
+	switch {
+	case s.anyDom && s.anyDow:
+		return true
+	case s.anyDom:
+		return dowMatch
+	case s.anyDow:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+func (s cronSchedule) String() string {
+	return "cron " + s.spec
+}
+
+// parseClock memparse jam format HH:MM menjadi menit sejak tengah malam
+func parseClock(value string) (int, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), ":", 2)
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("format jam tidak valid: %s (gunakan HH:MM)", value)
+	}
+
+	hour, errH := strconv.Atoi(parts[0])
+	minute, errM := strconv.Atoi(parts[1])
+	if errH != nil || errM != nil || hour < 0 || hour > 24 || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("format jam tidak valid: %s (gunakan HH:MM)", value)
+	}
+
+	return hour*60 + minute, nil
+}
+
+// formatClock memformat menit sejak tengah malam menjadi HH:MM
+func formatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// formatScheduleDuration memformat durasi tanpa sufiks "0s" yang tidak perlu
+func formatScheduleDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = s[:len(s)-2]
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = s[:len(s)-2]
+	}
+	return s
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// wib dipakai semua test jadwal agar hasil tidak bergantung timezone mesin
+var wib = time.FixedZone("WIB", 7*3600)
+
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, wib)
+}
+
+func TestParseScheduleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"interval", "every 90m", at(2026, 3, 2, 9, 30), at(2026, 3, 2, 11, 0)},
+		{"interval melewati tengah malam", "every 3h", at(2026, 3, 2, 22, 0), at(2026, 3, 3, 1, 0)},
+		{"jam tetap hari yang sama", "08:00,12:00,19:00", at(2026, 3, 2, 9, 30), at(2026, 3, 2, 12, 0)},
+		{"jam tetap tepat di slot", "08:00,12:00", at(2026, 3, 2, 12, 0), at(2026, 3, 3, 8, 0)},
+		{"jam tetap pindah hari", "08:00,12:00,19:00", at(2026, 3, 2, 19, 30), at(2026, 3, 3, 8, 0)},
+		{"jam tetap 24:00", "24:00", at(2026, 3, 2, 23, 0), at(2026, 3, 3, 0, 0)},
+		{"cron daftar jam", "0 8,12,19 * * *", at(2026, 3, 2, 9, 30), at(2026, 3, 2, 12, 0)},
+		{"cron dengan prefix", "cron 0 8,12,19 * * *", at(2026, 3, 2, 9, 30), at(2026, 3, 2, 12, 0)},
+		{"cron step", "*/15 * * * *", at(2026, 3, 2, 10, 7), at(2026, 3, 2, 10, 15)},
+		{"cron step dengan awal", "5/20 * * * *", at(2026, 3, 2, 10, 30), at(2026, 3, 2, 10, 45)},
+		{"cron tepat di menit", "0 12 * * *", at(2026, 3, 2, 12, 0), at(2026, 3, 3, 12, 0)},
+		{"cron detik dibulatkan", "0 12 * * *", time.Date(2026, 3, 2, 11, 59, 30, 0, wib), at(2026, 3, 2, 12, 0)},
+		{"cron hari kerja dari jumat", "0 9 * * 1-5", at(2026, 10, 16, 10, 0), at(2026, 10, 19, 9, 0)},
+		{"cron 7 berarti minggu", "0 10 * * 7", at(2026, 10, 16, 10, 0), at(2026, 10, 18, 10, 0)},
+		{"cron 0 berarti minggu", "0 10 * * 0", at(2026, 10, 16, 10, 0), at(2026, 10, 18, 10, 0)},
+		{"cron tanggal atau hari (hari lebih dulu)", "0 0 12 * 5", at(2026, 11, 1, 0, 0), at(2026, 11, 6, 0, 0)},
+		{"cron tanggal atau hari (tanggal lebih dulu)", "0 0 12 * 5", at(2026, 11, 9, 0, 0), at(2026, 11, 12, 0, 0)},
+		{"cron ganti tahun", "0 0 1 * *", at(2026, 12, 15, 8, 0), at(2027, 1, 1, 0, 0)},
+		{"cron bulan tertentu", "30 7 1 3 *", at(2026, 3, 2, 0, 0), at(2027, 3, 1, 7, 30)},
+		{"cron 29 februari", "0 0 29 2 *", at(2026, 1, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"cron tidak pernah terpenuhi", "0 0 31 2 *", at(2026, 1, 1, 0, 0), at(2031, 1, 1, 0, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error: %v", tt.expr, err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleString(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"every 90m", "every 1h30m"},
+		{"EVERY 2h", "every 2h"},
+		{"every 1h 30m", "every 1h30m"},
+		{"19:00, 08:00,12:00,08:00", "08:00,12:00,19:00"},
+		{"0  8   * * *", "cron 0 8 * * *"},
+		{"cron 0 9 * * 1-5", "cron 0 9 * * 1-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error: %v", tt.expr, err)
+			}
+			got := schedule.String()
+			if got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+
+			// Hasil String harus bisa diparse ulang menjadi jadwal yang sama
+			again, err := ParseSchedule(got)
+			if err != nil {
+				t.Fatalf("ParseSchedule(%q) error: %v", got, err)
+			}
+			if again.String() != got {
+				t.Errorf("round trip = %q, want %q", again.String(), got)
+			}
+		})
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{"kosong", "   ", "tidak boleh kosong"},
+		{"format tidak dikenal", "tiap jam", "format jadwal tidak dikenal"},
+		{"cron 4 kolom", "cron * * * *", "5 kolom"},
+		{"interval kurang dari semenit", "every 30s", "minimal 1 menit"},
+		{"interval tidak valid", "every sejam", "interval tidak valid"},
+		{"jam lebih dari 24", "25:00", "format jam tidak valid"},
+		{"menit 60", "08:60", "format jam tidak valid"},
+		{"24 lewat menit", "24:30", "format jam tidak valid"},
+		{"menit di luar rentang", "60 * * * *", "kolom menit"},
+		{"jam di luar rentang", "0 24 * * *", "kolom jam"},
+		{"tanggal 0", "0 0 0 * *", "kolom tanggal"},
+		{"bulan 13", "0 0 1 13 *", "kolom bulan"},
+		{"hari 8", "0 0 * * 8", "kolom hari"},
+		{"step nol", "*/0 * * * *", "step tidak valid"},
+		{"rentang terbalik", "5-1 * * * *", "di luar rentang"},
+		{"rentang bukan angka", "a-b * * * *", "rentang tidak valid"},
+		{"nilai bukan angka", "x * * * *", "nilai tidak valid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.expr)
+			if err == nil {
+				t.Fatalf("ParseSchedule(%q) tidak mengembalikan error", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want mengandung %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCronField(t *testing.T) {
+	tests := []struct {
+		field    string
+		min, max int
+		want     []int
+	}{
+		{"*", 0, 6, []int{0, 1, 2, 3, 4, 5, 6}},
+		{"*/20", 0, 59, []int{0, 20, 40}},
+		{"1-5", 0, 7, []int{1, 2, 3, 4, 5}},
+		{"1-10/3", 1, 31, []int{1, 4, 7, 10}},
+		{"50/5", 0, 59, []int{50, 55}},
+		{"8,12,8", 0, 23, []int{8, 12}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			got, err := parseCronField(tt.field, tt.min, tt.max)
+			if err != nil {
+				t.Fatalf("parseCronField(%q) error: %v", tt.field, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseCronField(%q) = %v, want %v", tt.field, got, tt.want)
+			}
+			for _, v := range tt.want {
+				if !got[v] {
+					t.Errorf("parseCronField(%q) tidak berisi %d", tt.field, v)
+				}
+			}
+		})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"2", 2 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"1h 30m", 90 * time.Minute, false},
+		{"1H30M", 90 * time.Minute, false},
+		{"30s", 0, true},
+		{"0", 0, true},
+		{"sejam", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseInterval(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseInterval(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseInterval(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package services - Broadcast sekali jalan yang dijadwalkan admin (.schedule)
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// scheduledJobGrace adalah batas keterlambatan job sebelum dianggap terlewat.
+// Broadcast "jam 19:30" yang baru jalan tengah malam (misal bot mati) lebih baik tidak dikirim.
+const scheduledJobGrace = 30 * time.Minute
+
+// ScheduledJobService mengelola broadcast terjadwal
+type ScheduledJobService struct {
+	repository database.Repository
+	logger     *utils.Logger
+	location   *time.Location
+}
+
+// NewScheduledJobService membuat service broadcast terjadwal baru
+func NewScheduledJobService(repo database.Repository, logger *utils.Logger) *ScheduledJobService {
+	return &ScheduledJobService{
+		repository: repo,
+		logger:     logger,
+		location:   time.Local,
+	}
+}
+
+// SetLocation mengatur timezone untuk membaca dan menampilkan waktu job
+func (j *ScheduledJobService) SetLocation(location *time.Location) {
+	j.location = location
+}
+
+// ParseRunTime memparse waktu kirim dalam timezone scheduler.
+// Format yang diterima: "YYYY-MM-DD HH:MM", "HH:MM" (jam terdekat berikutnya), atau "besok HH:MM".
+func (j *ScheduledJobService) ParseRunTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	now = now.In(j.location)
+
+	if t, err := time.ParseInLocation(adminTimeLayout, value, j.location); err == nil {
+		return t, nil
+	}
+
+	dayOffset := -1
+	clock := value
+	lower := strings.ToLower(value)
+	for _, prefix := range []string{"besok ", "tomorrow "} {
+		if strings.HasPrefix(lower, prefix) {
+			dayOffset = 1
+			clock = strings.TrimSpace(value[len(prefix):])
+			break
+		}
+	}
+
+	minutes, err := parseClock(clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format waktu tidak valid: %s (gunakan YYYY-MM-DD HH:MM, HH:MM, atau besok HH:MM)", value)
+	}
+
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, j.location)
+	if dayOffset > 0 {
+		return midnight.AddDate(0, 0, dayOffset).Add(time.Duration(minutes) * time.Minute), nil
+	}
+
+	// Tanpa tanggal: pakai jam tersebut hari ini, atau besok jika sudah lewat
+	t := midnight.Add(time.Duration(minutes) * time.Minute)
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// FormatTime memformat waktu dalam timezone scheduler
+func (j *ScheduledJobService) FormatTime(t time.Time) string {
+	return t.In(j.location).Format(adminTimeLayout)
+}
+
+// CreateJob menjadwalkan template untuk dikirim ke grup pada waktu tertentu.
+// Jika targetAll bernilai true, job dikirim ke semua grup yang aktif auto promote saat dijalankan.
+func (j *ScheduledJobService) CreateJob(templateID int, groupJIDs []string, targetAll bool, runAt, createdBy string) (*database.ScheduledJob, error) {
+	template, err := j.repository.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %v", err)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template dengan ID %d tidak ditemukan", templateID)
+	}
+
+	now := time.Now()
+	at, err := j.ParseRunTime(runAt, now)
+	if err != nil {
+		return nil, err
+	}
+	if !at.After(now) {
+		return nil, fmt.Errorf("waktu kirim sudah lewat: %s", j.FormatTime(at))
+	}
+
+	if !targetAll && len(groupJIDs) == 0 {
+		return nil, fmt.Errorf("minimal satu grup target harus dipilih")
+	}
+
+	job := &database.ScheduledJob{
+		TemplateID: templateID,
+		GroupJIDs:  groupJIDs,
+		TargetAll:  targetAll,
+		RunAt:      at,
+		Status:     database.ScheduledJobPending,
+		CreatedBy:  createdBy,
+	}
+
+	if err := j.repository.CreateScheduledJob(job); err != nil {
+		return nil, fmt.Errorf("failed to create scheduled job: %v", err)
+	}
+
+	j.logger.Successf("Scheduled job #%d created: template %d at %s", job.ID, templateID, j.FormatTime(job.RunAt))
+	return job, nil
+}
+
+// GetJobs mengambil job yang menunggu, atau semua job jika includeFinished true
+func (j *ScheduledJobService) GetJobs(includeFinished bool) ([]database.ScheduledJob, error) {
+	return j.repository.GetScheduledJobs(includeFinished)
+}
+
+// CancelJob membatalkan job yang belum dijalankan
+func (j *ScheduledJobService) CancelJob(id int) (*database.ScheduledJob, error) {
+	job, err := j.repository.GetScheduledJobByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled job: %v", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("jadwal dengan ID %d tidak ditemukan", id)
+	}
+	if job.Status != database.ScheduledJobPending {
+		return nil, fmt.Errorf("jadwal sudah tidak menunggu (status: %s)", job.Status)
+	}
+
+	job.Status = database.ScheduledJobCancelled
+	if err := j.repository.UpdateScheduledJob(job); err != nil {
+		return nil, fmt.Errorf("failed to cancel scheduled job: %v", err)
+	}
+
+	j.logger.Infof("Scheduled job #%d cancelled", id)
+	return job, nil
+}
+
+// DueJobs mengambil job yang sudah waktunya dijalankan
+func (j *ScheduledJobService) DueJobs(now time.Time) ([]database.ScheduledJob, error) {
+	jobs, err := j.repository.GetDueScheduledJobs(now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due scheduled jobs: %v", err)
+	}
+	return jobs, nil
+}
+
+// IsMissed mengecek apakah job sudah terlambat melebihi scheduledJobGrace
+func (j *ScheduledJobService) IsMissed(job *database.ScheduledJob, now time.Time) bool {
+	return now.Sub(job.RunAt) > scheduledJobGrace
+}
+
+// FinishJob menyimpan hasil eksekusi job
+func (j *ScheduledJobService) FinishJob(job *database.ScheduledJob, status string, queued int, reason string) {
+	executedAt := time.Now()
+	job.Status = status
+	job.QueuedCount = queued
+	job.ExecutedAt = &executedAt
+	job.ErrorMsg = nil
+	if reason != "" {
+		job.ErrorMsg = &reason
+	}
+
+	if err := j.repository.UpdateScheduledJob(job); err != nil {
+		j.logger.Errorf("Failed to update scheduled job #%d: %v", job.ID, err)
+	}
+}
+
+// GetJobState mengembalikan label status job untuk ditampilkan ke admin
+func (j *ScheduledJobService) GetJobState(job *database.ScheduledJob) string {
+	switch job.Status {
+	case database.ScheduledJobPending:
+		return "⏳ Menunggu"
+	case database.ScheduledJobDone:
+		return fmt.Sprintf("✅ Dijalankan (%d pesan)", job.QueuedCount)
+	case database.ScheduledJobCancelled:
+		return "🚫 Dibatalkan"
+	case database.ScheduledJobMissed:
+		return "⌛ Terlewat"
+	default:
+		return "❌ Gagal"
+	}
+}
@@ -28,7 +28,8 @@ func NewSchedulerService(task func(), logger *utils.Logger) *SchedulerService {
 	}
 }
 
-// Start memulai scheduler dengan interval tertentu
+// Start memulai scheduler dengan interval tertentu.
+// Task langsung dijalankan sekali saat start, lalu setiap interval.
 func (s *SchedulerService) Start(interval time.Duration) {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -44,6 +45,9 @@ func (s *SchedulerService) Start(interval time.Duration) {
 	s.isRunning = true
 
 	go func() {
+		// Evaluasi pertama langsung dijalankan agar restart tidak menunggu satu interval penuh
+		s.executeTask()
+
 		for {
 			select {
 			case <-s.ticker.C:
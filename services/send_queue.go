@@ -0,0 +1,520 @@
+// Package services - Antrian kirim promosi yang persisten dengan rate limit dan jitter
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/types"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// queuePollInterval adalah seberapa sering worker mengecek antrian
+const queuePollInterval = 5 * time.Second
+
+// queueRetryBackoff adalah jeda dasar sebelum mencoba ulang pesan yang gagal
+const queueRetryBackoff = 30 * time.Second
+
+// SendQueueService mengirim pesan dari tabel promote_queue secara bertahap.
+// Semua pengiriman promosi ke grup melewati antrian ini agar tidak terjadi burst.
+type SendQueueService struct {
+	client     *whatsmeow.Client
+	repository database.Repository
+	media      *MediaStore // Media template yang dikirim bersama caption (nil = teks saja)
+	logger     *utils.Logger
+
+	// windowFor mengembalikan jam kirim grup (nil = tanpa pengecekan jam kirim)
+	windowFor func(groupJID string) *SendWindow
+
+	perMinuteLimit int            // Maksimal pesan per menit (global)
+	dailyLimit     int            // Maksimal pesan per hari untuk akun ini
+	spread         time.Duration  // Rentang jitter untuk promosi terjadwal
+	location       *time.Location // Timezone untuk pergantian hari daily cap
+
+	mutex        sync.Mutex
+	isRunning    bool
+	stopChan     chan struct{}
+	lastSentAt   time.Time
+	limitWarnDay string
+}
+
+// QueueStatus berisi ringkasan kondisi antrian kirim
+type QueueStatus struct {
+	IsRunning      bool
+	Pending        int
+	SentToday      int
+	FailedTotal    int
+	PerMinuteLimit int
+	DailyLimit     int
+	Spread         time.Duration
+}
+
+// NewSendQueueService membuat service antrian kirim baru
+func NewSendQueueService(client *whatsmeow.Client, repo database.Repository, logger *utils.Logger) *SendQueueService {
+	return &SendQueueService{
+		client:         client,
+		repository:     repo,
+		logger:         logger,
+		perMinuteLimit: 4,
+		dailyLimit:     200,
+		spread:         30 * time.Minute,
+		location:       time.Local,
+	}
+}
+
+// SetLimits mengatur batas pesan per menit dan per hari
+func (q *SendQueueService) SetLimits(perMinute, daily int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if perMinute > 0 {
+		q.perMinuteLimit = perMinute
+	}
+	if daily > 0 {
+		q.dailyLimit = daily
+	}
+	q.logger.Infof("Send queue limits set to %d/minute, %d/day", q.perMinuteLimit, q.dailyLimit)
+}
+
+// SetSpread mengatur rentang jitter untuk promosi terjadwal
+func (q *SendQueueService) SetSpread(spread time.Duration) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if spread < 0 {
+		spread = 0
+	}
+	q.spread = spread
+	q.logger.Infof("Send queue spread set to %v", spread)
+}
+
+// SetMediaStore mengatur penyimpanan media agar template bermedia dikirim sebagai gambar/video/dokumen
+func (q *SendQueueService) SetMediaStore(media *MediaStore) {
+	q.media = media
+}
+
+// SetWindowResolver mengatur sumber jam kirim grup yang dicek ulang saat pesan dikirim
+func (q *SendQueueService) SetWindowResolver(windowFor func(groupJID string) *SendWindow) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.windowFor = windowFor
+}
+
+// SetLocation mengatur timezone untuk perhitungan batas harian
+func (q *SendQueueService) SetLocation(location *time.Location) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.location = location
+}
+
+// Enqueue menambahkan pesan ke antrian untuk dikirim setelah delay tertentu
+func (q *SendQueueService) Enqueue(groupJID string, templateID int, content, source string, delay time.Duration, maxAttempts int) (*database.QueuedMessage, error) {
+	msg := &database.QueuedMessage{
+		GroupJID:    groupJID,
+		TemplateID:  templateID,
+		Content:     content,
+		Source:      source,
+		MaxAttempts: maxAttempts,
+	}
+
+	if err := q.EnqueueMessage(msg, delay); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// EnqueueMessage menambahkan pesan yang sudah disiapkan (misal dengan CampaignID) ke antrian
+func (q *SendQueueService) EnqueueMessage(msg *database.QueuedMessage, delay time.Duration) error {
+	if msg.MaxAttempts < 1 {
+		msg.MaxAttempts = 1
+	}
+	msg.Status = database.QueueStatusPending
+	msg.ScheduledAt = time.Now().Add(delay)
+
+	if err := q.repository.EnqueueMessage(msg); err != nil {
+		return fmt.Errorf("failed to enqueue message: %v", err)
+	}
+
+	q.logger.Debugf("Queued %s message #%d for %s at %s", msg.Source, msg.ID, msg.GroupJID, msg.ScheduledAt.In(q.location).Format("15:04:05"))
+	return nil
+}
+
+// RandomDelay menghasilkan jitter acak dalam rentang spread, dibatasi maxDelay jika > 0
+func (q *SendQueueService) RandomDelay(maxDelay time.Duration) time.Duration {
+	q.mutex.Lock()
+	spread := q.spread
+	q.mutex.Unlock()
+
+	if maxDelay > 0 && spread > maxDelay {
+		spread = maxDelay
+	}
+	if spread <= 0 {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(spread)))
+}
+
+// HasPending mengecek apakah grup masih memiliki pesan yang belum terkirim
+func (q *SendQueueService) HasPending(groupJID string) (bool, error) {
+	return q.repository.HasPendingQueuedMessage(groupJID)
+}
+
+// Start menjalankan worker antrian
+func (q *SendQueueService) Start() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.isRunning {
+		q.logger.Warning("Send queue is already running")
+		return
+	}
+
+	q.stopChan = make(chan struct{})
+	q.isRunning = true
+
+	go q.run(q.stopChan)
+
+	q.logger.Successf("Send queue started (%d/minute, %d/day)", q.perMinuteLimit, q.dailyLimit)
+}
+
+// Stop menghentikan worker antrian. Pesan yang belum terkirim tetap tersimpan di database.
+func (q *SendQueueService) Stop() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if !q.isRunning {
+		return
+	}
+
+	close(q.stopChan)
+	q.isRunning = false
+	q.logger.Info("Send queue stopped")
+}
+
+// GetStatus mengembalikan ringkasan kondisi antrian
+func (q *SendQueueService) GetStatus() QueueStatus {
+	q.mutex.Lock()
+	status := QueueStatus{
+		IsRunning:      q.isRunning,
+		PerMinuteLimit: q.perMinuteLimit,
+		DailyLimit:     q.dailyLimit,
+		Spread:         q.spread,
+	}
+	location := q.location
+	q.mutex.Unlock()
+
+	status.Pending, _ = q.repository.CountQueuedMessages(database.QueueStatusPending)
+	status.FailedTotal, _ = q.repository.CountQueuedMessages(database.QueueStatusFailed)
+	status.SentToday, _ = q.repository.CountSentMessagesSince(startOfDay(time.Now().In(location)))
+
+	return status
+}
+
+// run adalah loop worker yang berjalan di goroutine terpisah
+func (q *SendQueueService) run(stopChan chan struct{}) {
+	ticker := time.NewTicker(queuePollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			q.processNext()
+		case <-stopChan:
+			return
+		}
+	}
+}
+
+// processNext mengirim maksimal satu pesan yang jatuh tempo jika rate limit mengizinkan
+func (q *SendQueueService) processNext() {
+	defer func() {
+		if r := recover(); r != nil {
+			q.logger.Errorf("Send queue panic recovered: %v", r)
+		}
+	}()
+
+	q.mutex.Lock()
+	perMinute := q.perMinuteLimit
+	daily := q.dailyLimit
+	lastSentAt := q.lastSentAt
+	location := q.location
+	q.mutex.Unlock()
+
+	now := time.Now()
+
+	// Jarak minimal antar pesan agar pengiriman dalam satu menit tidak menumpuk
+	minGap := time.Minute / time.Duration(perMinute)
+	if now.Sub(lastSentAt) < minGap {
+		return
+	}
+
+	sentLastMinute, err := q.repository.CountSentMessagesSince(now.Add(-time.Minute))
+	if err != nil {
+		q.logger.Errorf("Failed to count sent messages: %v", err)
+		return
+	}
+	if sentLastMinute >= perMinute {
+		return
+	}
+
+	today := now.In(location)
+	sentToday, err := q.repository.CountSentMessagesSince(startOfDay(today))
+	if err != nil {
+		q.logger.Errorf("Failed to count sent messages: %v", err)
+		return
+	}
+	if sentToday >= daily {
+		q.warnDailyLimit(today.Format("2006-01-02"), daily)
+		return
+	}
+
+	due, err := q.repository.GetDueQueuedMessages(now, 1)
+	if err != nil {
+		q.logger.Errorf("Failed to get queued messages: %v", err)
+		return
+	}
+	if len(due) == 0 {
+		return
+	}
+
+	q.deliver(&due[0])
+}
+
+// deliver mengirim satu pesan antrian lalu mencatat hasilnya
+func (q *SendQueueService) deliver(msg *database.QueuedMessage) {
+	now := time.Now()
+
+	q.mutex.Lock()
+	location := q.location
+	windowFor := q.windowFor
+	q.mutex.Unlock()
+
+	// Pesan bisa tertahan lama oleh jitter, batas per menit atau daily cap,
+	// jadi jam kirim grup dan template dicek ulang saat benar-benar dikirim
+	if next, held := holdUntilWindow(msg, windowFor, now.In(location)); held {
+		msg.ScheduledAt = next
+		if err := q.repository.UpdateQueuedMessage(msg); err != nil {
+			q.logger.Errorf("Failed to update queued message #%d: %v", msg.ID, err)
+		}
+		q.logger.Infof("Queued message #%d to %s held until send window opens at %s",
+			msg.ID, msg.GroupJID, next.Format(adminTimeLayout))
+		return
+	}
+
+	if reason := q.skipReason(msg, now.In(location)); reason != "" {
+		msg.Status = database.QueueStatusCancelled
+		msg.LastError = &reason
+		if err := q.repository.UpdateQueuedMessage(msg); err != nil {
+			q.logger.Errorf("Failed to update queued message #%d: %v", msg.ID, err)
+		}
+		q.logger.Infof("Queued message #%d to %s skipped: %s", msg.ID, msg.GroupJID, reason)
+		return
+	}
+
+	q.mutex.Lock()
+	q.lastSentAt = now
+	q.mutex.Unlock()
+
+	msg.Attempts++
+	messageID, err := q.send(msg)
+
+	if err != nil {
+		errorMsg := err.Error()
+		msg.LastError = &errorMsg
+
+		if msg.Attempts < msg.MaxAttempts {
+			// Coba lagi nanti dengan backoff, pesan tetap di antrian
+			msg.ScheduledAt = now.Add(time.Duration(msg.Attempts) * queueRetryBackoff)
+			q.logger.Warningf("Queued message #%d to %s failed (attempt %d/%d): %v",
+				msg.ID, msg.GroupJID, msg.Attempts, msg.MaxAttempts, err)
+		} else {
+			msg.Status = database.QueueStatusFailed
+			q.logger.Errorf("Queued message #%d to %s failed after %d attempts: %v",
+				msg.ID, msg.GroupJID, msg.Attempts, err)
+		}
+	} else {
+		msg.Status = database.QueueStatusSent
+		msg.SentAt = &now
+		msg.LastError = nil
+		q.logger.Infof("Promote message sent to group: %s", msg.GroupJID)
+	}
+
+	if updateErr := q.repository.UpdateQueuedMessage(msg); updateErr != nil {
+		q.logger.Errorf("Failed to update queued message #%d: %v", msg.ID, updateErr)
+	}
+
+	// Pesan yang masih akan dicoba ulang belum dicatat ke log
+	if msg.Status == database.QueueStatusPending {
+		return
+	}
+
+	q.recordResult(msg, messageID, now.In(location), err)
+}
+
+// holdUntilWindow mengecek jam kirim grup untuk promosi rotasi dan campaign.
+// Jika window sedang tertutup, pesan ditunda sampai window terbuka lagi.
+func holdUntilWindow(msg *database.QueuedMessage, windowFor func(string) *SendWindow, now time.Time) (time.Time, bool) {
+	if windowFor == nil {
+		return time.Time{}, false
+	}
+	if msg.Source != database.QueueSourceAuto && msg.Source != database.QueueSourceCampaign {
+		return time.Time{}, false
+	}
+
+	window := windowFor(msg.GroupJID)
+	if window.IsOpen(now) {
+		return time.Time{}, false
+	}
+	return window.NextOpen(now), true
+}
+
+// skipReason mengembalikan alasan pesan tidak jadi dikirim karena templatenya sudah
+// nonaktif atau di luar masa berlaku. Hanya berlaku untuk pesan dari rotasi (auto,
+// campaign, manual); .schedule dan .testgroup memakai template pilihan admin.
+func (q *SendQueueService) skipReason(msg *database.QueuedMessage, now time.Time) string {
+	if msg.TemplateID == 0 {
+		return ""
+	}
+	switch msg.Source {
+	case database.QueueSourceAuto, database.QueueSourceCampaign, database.QueueSourceManual:
+	default:
+		return ""
+	}
+
+	template, err := q.repository.GetTemplateByID(msg.TemplateID)
+	if err != nil {
+		// Dibiarkan terkirim; kegagalan baca database tidak boleh membuang promosi
+		q.logger.Warningf("Failed to check template %d before sending: %v", msg.TemplateID, err)
+		return ""
+	}
+
+	switch {
+	case template == nil:
+		return "template sudah dihapus"
+	case !template.IsActive:
+		return "template sudah nonaktif"
+	case TemplateExpired(template, now):
+		return "masa berlaku template sudah berakhir"
+	case !TemplateValidAt(template, now):
+		return "di luar masa tayang template"
+	}
+	return ""
+}
+
+// recordResult mencatat hasil akhir pengiriman ke log, statistik, dan status grup.
+// ID pesan WhatsApp disimpan agar read receipt dan balasan bisa dicocokkan ke log.
+func (q *SendQueueService) recordResult(msg *database.QueuedMessage, messageID string, sentAt time.Time, sendErr error) {
+	log := &database.PromoteLog{
+		GroupJID:   msg.GroupJID,
+		TemplateID: msg.TemplateID,
+		Content:    msg.Content,
+		SentAt:     sentAt,
+		Success:    sendErr == nil,
+		ErrorMsg:   msg.LastError,
+		CampaignID: msg.CampaignID,
+		VariantID:  msg.VariantID,
+		MessageID:  messageID,
+	}
+	if err := q.repository.CreateLog(log); err != nil {
+		q.logger.Errorf("Failed to create promote log: %v", err)
+	}
+
+	if err := q.repository.IncrementStats(sentAt.Format("2006-01-02"), sendErr == nil); err != nil {
+		q.logger.Errorf("Failed to update stats: %v", err)
+	}
+
+	// Hanya promosi terjadwal yang menggeser waktu promosi terakhir grup
+	if sendErr == nil && msg.Source == database.QueueSourceAuto {
+		if err := q.repository.MarkGroupPromoted(msg.GroupJID, sentAt); err != nil {
+			q.logger.Errorf("Failed to update group %s last promote time: %v", msg.GroupJID, err)
+		}
+	}
+}
+
+// send mengirim isi pesan antrian ke grup tujuan dan mengembalikan ID pesan WhatsApp
+func (q *SendQueueService) send(msg *database.QueuedMessage) (string, error) {
+	jid, err := types.ParseJID(msg.GroupJID)
+	if err != nil {
+		return "", fmt.Errorf("invalid group JID: %v", err)
+	}
+
+	waMsg, err := q.buildMessage(msg)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := q.client.SendMessage(context.Background(), jid, waMsg)
+	if err != nil {
+		return "", fmt.Errorf("failed to send message: %v", err)
+	}
+
+	return resp.ID, nil
+}
+
+// buildMessage membuat pesan WhatsApp untuk pesan antrian. Template dengan media
+// dikirim sebagai gambar/video/dokumen dengan konten sebagai caption.
+func (q *SendQueueService) buildMessage(msg *database.QueuedMessage) (*waProto.Message, error) {
+	content := msg.Content
+	if q.media == nil || msg.TemplateID == 0 {
+		return &waProto.Message{Conversation: &content}, nil
+	}
+
+	template, err := q.repository.GetTemplateByID(msg.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template media: %v", err)
+	}
+	if template == nil {
+		return &waProto.Message{Conversation: &content}, nil
+	}
+
+	return buildTemplateMessage(context.Background(), q.client, q.media, template, content, q.logger)
+}
+
+// buildTemplateMessage membuat pesan WhatsApp dari konten template yang sudah dirender.
+// Dipakai antrian kirim dan preview grup agar hasilnya sama persis.
+func buildTemplateMessage(ctx context.Context, client *whatsmeow.Client, media *MediaStore, template *database.PromoteTemplate, content string, logger *utils.Logger) (*waProto.Message, error) {
+	textMsg := &waProto.Message{
+		Conversation: &content,
+	}
+
+	if media == nil || template.MediaType == "" {
+		return textMsg, nil
+	}
+
+	// File yang hilang tidak akan kembali dengan retry, jadi promosi tetap dikirim sebagai teks
+	data, err := media.Read(template.MediaPath)
+	if err != nil {
+		logger.Warningf("Media of template %d unavailable, sending text only: %v", template.ID, err)
+		return textMsg, nil
+	}
+
+	return NewMediaMessage(ctx, client, template.MediaType, data,
+		template.MediaMimetype, template.MediaName, content)
+}
+
+// warnDailyLimit mencatat peringatan batas harian sekali per hari
+func (q *SendQueueService) warnDailyLimit(day string, limit int) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.limitWarnDay == day {
+		return
+	}
+	q.limitWarnDay = day
+	q.logger.Warningf("Daily send limit reached (%d messages), remaining queue deferred to tomorrow", limit)
+}
+
+// startOfDay mengembalikan tengah malam pada hari dan timezone yang sama
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,125 @@
+// Package services - Jendela waktu pengiriman (quiet hours / jam operasional)
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SendWindow mendefinisikan rentang jam harian yang diizinkan untuk mengirim promosi.
+// Window tanpa rentang berarti pengiriman diizinkan 24 jam.
+type SendWindow struct {
+	ranges []clockRange
+}
+
+// clockRange adalah rentang jam dalam menit sejak tengah malam.
+// Jika end <= start, rentang melewati tengah malam (misal 22:00-02:00).
+type clockRange struct {
+	start int
+	end   int
+}
+
+// ParseSendWindow memparse ekspresi window seperti "08:00-21:00" atau "06:00-11:00,13:00-22:00".
+// Ekspresi "always" atau "24h" berarti tidak ada batasan jam.
+func ParseSendWindow(expr string) (*SendWindow, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	if expr == "" || expr == "always" || expr == "24h" {
+		return &SendWindow{}, nil
+	}
+
+	var ranges []clockRange
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		bounds := strings.SplitN(part, "-", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("format window tidak valid: %s (gunakan HH:MM-HH:MM)", part)
+		}
+
+		start, err := parseClock(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(bounds[1])
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, fmt.Errorf("jam mulai dan selesai tidak boleh sama: %s", part)
+		}
+
+		ranges = append(ranges, clockRange{start: start, end: end})
+	}
+
+	return &SendWindow{ranges: ranges}, nil
+}
+
+// IsAlwaysOpen mengecek apakah window tidak memiliki batasan jam
+func (w *SendWindow) IsAlwaysOpen() bool {
+	return w == nil || len(w.ranges) == 0
+}
+
+// IsOpen mengecek apakah waktu t berada di dalam window (t harus sudah dalam timezone yang benar)
+func (w *SendWindow) IsOpen(t time.Time) bool {
+	if w.IsAlwaysOpen() {
+		return true
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	for _, r := range w.ranges {
+		if r.contains(minute) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen mengembalikan waktu terdekat (>= t) saat window terbuka
+func (w *SendWindow) NextOpen(t time.Time) time.Time {
+	if w.IsOpen(t) {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	var best time.Time
+
+	for day := 0; day <= 1; day++ {
+		base := midnight.AddDate(0, 0, day)
+		for _, r := range w.ranges {
+			candidate := base.Add(time.Duration(r.start) * time.Minute)
+			if candidate.After(t) && (best.IsZero() || candidate.Before(best)) {
+				best = candidate
+			}
+		}
+		if !best.IsZero() {
+			return best
+		}
+	}
+
+	return t
+}
+
+// String mengembalikan representasi window yang bisa diparse ulang
+func (w *SendWindow) String() string {
+	if w.IsAlwaysOpen() {
+		return "always"
+	}
+
+	var parts []string
+	for _, r := range w.ranges {
+		parts = append(parts, formatClock(r.start)+"-"+formatClock(r.end))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r clockRange) contains(minute int) bool {
+	if r.start < r.end {
+		return minute >= r.start && minute < r.end
+	}
+	// Rentang melewati tengah malam
+	return minute >= r.start || minute < r.end
+}
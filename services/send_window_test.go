@@ -0,0 +1,137 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendWindowIsOpen(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		hour int
+		min  int
+		want bool
+	}{
+		{"siang di dalam window", "08:00-21:00", 12, 0, true},
+		{"tepat jam mulai", "08:00-21:00", 8, 0, true},
+		{"tepat jam selesai tertutup", "08:00-21:00", 21, 0, false},
+		{"semenit sebelum mulai", "08:00-21:00", 7, 59, false},
+		{"lewat tengah malam sebelum 24:00", "22:00-02:00", 23, 0, true},
+		{"lewat tengah malam setelah 00:00", "22:00-02:00", 1, 59, true},
+		{"lewat tengah malam tepat selesai", "22:00-02:00", 2, 0, false},
+		{"lewat tengah malam siang hari", "22:00-02:00", 12, 0, false},
+		{"lewat tengah malam tepat mulai", "22:00-02:00", 22, 0, true},
+		{"sampai 24:00", "22:00-24:00", 23, 59, true},
+		{"sampai 24:00 tengah malam", "22:00-24:00", 0, 0, false},
+		{"dua rentang di jeda", "06:00-11:00,13:00-22:00", 12, 0, false},
+		{"dua rentang kedua", "06:00-11:00,13:00-22:00", 13, 30, true},
+		{"always", "always", 3, 0, true},
+		{"kosong", "", 3, 0, true},
+		{"24h", "24H", 3, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParseSendWindow(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSendWindow(%q) error: %v", tt.expr, err)
+			}
+			now := at(2026, 3, 2, tt.hour, tt.min)
+			if got := window.IsOpen(now); got != tt.want {
+				t.Errorf("IsOpen(%02d:%02d) = %v, want %v", tt.hour, tt.min, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendWindowNextOpen(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		now  time.Time
+		want time.Time
+	}{
+		{"sedang terbuka", "08:00-21:00", at(2026, 3, 2, 12, 0), at(2026, 3, 2, 12, 0)},
+		{"sebelum buka", "08:00-21:00", at(2026, 3, 2, 6, 15), at(2026, 3, 2, 8, 0)},
+		{"setelah tutup ke hari berikutnya", "08:00-21:00", at(2026, 3, 2, 21, 0), at(2026, 3, 3, 8, 0)},
+		{"lewat tengah malam siang hari", "22:00-02:00", at(2026, 3, 2, 3, 0), at(2026, 3, 2, 22, 0)},
+		{"dua rentang di jeda", "06:00-11:00,13:00-22:00", at(2026, 3, 2, 12, 0), at(2026, 3, 2, 13, 0)},
+		{"dua rentang setelah tutup", "06:00-11:00,13:00-22:00", at(2026, 3, 2, 22, 30), at(2026, 3, 3, 6, 0)},
+		{"ganti bulan", "08:00-21:00", at(2026, 3, 31, 23, 0), at(2026, 4, 1, 8, 0)},
+		{"always", "always", at(2026, 3, 2, 3, 0), at(2026, 3, 2, 3, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := ParseSendWindow(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSendWindow(%q) error: %v", tt.expr, err)
+			}
+			got := window.NextOpen(tt.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("NextOpen(%s) = %s, want %s", tt.now, got, tt.want)
+			}
+			if !window.IsOpen(got) {
+				t.Errorf("NextOpen(%s) = %s masih di luar window", tt.now, got)
+			}
+		})
+	}
+}
+
+func TestParseSendWindowString(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"08:00-21:00", "08:00-21:00"},
+		{" 8:00-21:00 , 22:00-2:00 ", "08:00-21:00,22:00-02:00"},
+		{"06:00-11:00,,13:00-22:00", "06:00-11:00,13:00-22:00"},
+		{"22:00-24:00", "22:00-24:00"},
+		{"Always", "always"},
+		{"", "always"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			window, err := ParseSendWindow(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseSendWindow(%q) error: %v", tt.expr, err)
+			}
+			if got := window.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	var window *SendWindow
+	if !window.IsAlwaysOpen() || !window.IsOpen(at(2026, 3, 2, 3, 0)) {
+		t.Errorf("window nil harus selalu terbuka")
+	}
+}
+
+func TestParseSendWindowErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"08:00", "HH:MM-HH:MM"},
+		{"08:00-08:00", "tidak boleh sama"},
+		{"8-21", "format jam tidak valid"},
+		{"25:00-26:00", "format jam tidak valid"},
+		{"08:00-21:00,22:00", "HH:MM-HH:MM"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseSendWindow(tt.expr)
+			if err == nil {
+				t.Fatalf("ParseSendWindow(%q) tidak mengembalikan error", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want mengandung %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
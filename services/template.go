@@ -4,26 +4,42 @@ package services
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/render"
 	"github.com/nabilulilalbab/promote/utils"
 )
 
+// previewSampleCount adalah jumlah contoh variasi yang ditampilkan untuk template dengan spintax
+const previewSampleCount = 3
+
 // TemplateService mengelola template promosi
 type TemplateService struct {
 	repository database.Repository
+	renderer   *TemplateRenderer
+	media      *MediaStore
 	logger     *utils.Logger
 }
 
 // NewTemplateService membuat service baru
-func NewTemplateService(repo database.Repository, logger *utils.Logger) *TemplateService {
+func NewTemplateService(repo database.Repository, renderer *TemplateRenderer, logger *utils.Logger) *TemplateService {
 	return &TemplateService{
 		repository: repo,
+		renderer:   renderer,
 		logger:     logger,
 	}
 }
 
+// SetMediaStore mengatur penyimpanan media untuk .attachmedia
+func (s *TemplateService) SetMediaStore(media *MediaStore) {
+	s.media = media
+}
+
+// GetRenderer mengembalikan renderer yang dipakai untuk preview dan validasi
+func (s *TemplateService) GetRenderer() *TemplateRenderer {
+	return s.renderer
+}
+
 // GetAllTemplates mendapatkan semua template
 func (s *TemplateService) GetAllTemplates() ([]database.PromoteTemplate, error) {
 	templates, err := s.repository.GetAllTemplates()
@@ -63,8 +79,8 @@ func (s *TemplateService) GetTemplateByID(id int) (*database.PromoteTemplate, er
 	return template, nil
 }
 
-// CreateTemplate membuat template baru
-func (s *TemplateService) CreateTemplate(title, content, category string) (*database.PromoteTemplate, error) {
+// CreateTemplate membuat template baru. author dicatat di riwayat template.
+func (s *TemplateService) CreateTemplate(title, content, category, author string) (*database.PromoteTemplate, error) {
 	// Validasi input
 	if err := s.validateTemplate(title, content, category); err != nil {
 		return nil, err
@@ -83,12 +99,14 @@ func (s *TemplateService) CreateTemplate(title, content, category string) (*data
 		return nil, fmt.Errorf("gagal membuat template: %v", err)
 	}
 
+	s.recordRevision(template, database.RevisionCreate, author)
+
 	s.logger.Successf("Template created: %s (ID: %d)", template.Title, template.ID)
 	return template, nil
 }
 
-// UpdateTemplate mengupdate template yang ada
-func (s *TemplateService) UpdateTemplate(id int, title, content, category string, isActive bool) error {
+// UpdateTemplate mengupdate template yang ada. Isi sebelumnya tetap tersimpan di riwayat.
+func (s *TemplateService) UpdateTemplate(id int, title, content, category string, isActive bool, author string) error {
 	// Cek apakah template ada
 	existing, err := s.repository.GetTemplateByID(id)
 	if err != nil {
@@ -104,6 +122,8 @@ func (s *TemplateService) UpdateTemplate(id int, title, content, category string
 		return err
 	}
 
+	s.ensureBaselineRevision(existing)
+
 	// Update template
 	existing.Title = strings.TrimSpace(title)
 	existing.Content = strings.TrimSpace(content)
@@ -116,12 +136,47 @@ func (s *TemplateService) UpdateTemplate(id int, title, content, category string
 		return fmt.Errorf("gagal mengupdate template: %v", err)
 	}
 
+	s.recordRevision(existing, database.RevisionEdit, author)
+
 	s.logger.Successf("Template updated: %s (ID: %d)", existing.Title, existing.ID)
 	return nil
 }
 
-// DeleteTemplate menghapus template
-func (s *TemplateService) DeleteTemplate(id int) error {
+// SetTemplateWeight mengatur bobot template untuk strategi weighted random
+func (s *TemplateService) SetTemplateWeight(id, weight int, author string) (*database.PromoteTemplate, error) {
+	if weight < 1 || weight > 100 {
+		return nil, fmt.Errorf("bobot harus antara 1-100")
+	}
+
+	template, err := s.repository.GetTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if template == nil {
+		return nil, fmt.Errorf("template dengan ID %d tidak ditemukan", id)
+	}
+
+	s.ensureBaselineRevision(template)
+	template.Weight = weight
+
+	err = s.repository.UpdateTemplate(template)
+	if err != nil {
+		s.logger.Errorf("Failed to update template %d weight: %v", id, err)
+		return nil, fmt.Errorf("gagal mengubah bobot template: %v", err)
+	}
+
+	s.recordRevision(template, database.RevisionWeight, author)
+
+	s.logger.Successf("Template weight set: %s (ID: %d) = %d", template.Title, template.ID, weight)
+	return template, nil
+}
+
+// DeleteTemplate menghapus template beserta varian A/B dan terjemahannya, dan
+// membatalkan antrian kirimnya. Isi terakhir disimpan di riwayat sehingga template
+// bisa dipulihkan dengan RollbackTemplate. File media sengaja tidak dihapus karena
+// revisi masih merujuknya; pemulihan memasang media itu kembali.
+func (s *TemplateService) DeleteTemplate(id int, author string) error {
 	// Cek apakah template ada
 	existing, err := s.repository.GetTemplateByID(id)
 	if err != nil {
@@ -132,6 +187,13 @@ func (s *TemplateService) DeleteTemplate(id int) error {
 		return fmt.Errorf("template dengan ID %d tidak ditemukan", id)
 	}
 
+	// Simpan isi terakhir dulu; tanpa itu penghapusan tidak bisa dibatalkan
+	s.ensureBaselineRevision(existing)
+	if err := s.saveRevision(existing, database.RevisionDelete, author); err != nil {
+		s.logger.Errorf("Failed to save revision before deleting template %d: %v", id, err)
+		return fmt.Errorf("gagal menyimpan riwayat template: %v", err)
+	}
+
 	err = s.repository.DeleteTemplate(id)
 	if err != nil {
 		s.logger.Errorf("Failed to delete template %d: %v", id, err)
@@ -143,7 +205,7 @@ func (s *TemplateService) DeleteTemplate(id int) error {
 }
 
 // ToggleTemplateStatus mengaktifkan/menonaktifkan template
-func (s *TemplateService) ToggleTemplateStatus(id int) error {
+func (s *TemplateService) ToggleTemplateStatus(id int, author string) error {
 	template, err := s.repository.GetTemplateByID(id)
 	if err != nil {
 		return err
@@ -153,6 +215,8 @@ func (s *TemplateService) ToggleTemplateStatus(id int) error {
 		return fmt.Errorf("template dengan ID %d tidak ditemukan", id)
 	}
 
+	s.ensureBaselineRevision(template)
+
 	// Toggle status
 	template.IsActive = !template.IsActive
 
@@ -162,6 +226,8 @@ func (s *TemplateService) ToggleTemplateStatus(id int) error {
 		return fmt.Errorf("gagal mengubah status template: %v", err)
 	}
 
+	s.recordRevision(template, database.RevisionStatus, author)
+
 	status := "dinonaktifkan"
 	if template.IsActive {
 		status = "diaktifkan"
@@ -222,8 +288,29 @@ func (s *TemplateService) PreviewTemplate(templateID int) (string, error) {
 		return "", fmt.Errorf("template tidak ditemukan")
 	}
 
-	// Proses template dengan sample data
-	preview := s.processTemplateForPreview(template.Content)
+	// Render template dengan contoh data, sama seperti saat dikirim.
+	// Template dengan spintax ditampilkan beberapa variasi.
+	sampleCount := 1
+	if render.HasSpintax(template.Content) {
+		sampleCount = previewSampleCount
+	}
+
+	samples, err := s.renderer.PreviewSamples(template.Content, sampleCount)
+	if err != nil {
+		return "", err
+	}
+
+	preview := samples[0]
+	if len(samples) > 1 {
+		var variants strings.Builder
+		for i, sample := range samples {
+			if i > 0 {
+				variants.WriteString("\n\n┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈┈\n\n")
+			}
+			variants.WriteString(fmt.Sprintf("🎲 *Variasi %d/%d*\n\n%s", i+1, len(samples), sample))
+		}
+		preview = variants.String()
+	}
 
 	return fmt.Sprintf(`📋 *PREVIEW TEMPLATE*
 
@@ -234,7 +321,7 @@ func (s *TemplateService) PreviewTemplate(templateID int) (string, error) {
 🏷️ *Judul:* %s
 📂 *Kategori:* %s
 📈 *Status:* %s
-
+%s
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 	          *KONTEN PREVIEW*
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
@@ -245,10 +332,11 @@ func (s *TemplateService) PreviewTemplate(templateID int) (string, error) {
 	          *INFORMASI*
 ━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━
 
-💡 Variabel dinamis seperti *{DATE}* dan *{TIME}* akan diganti saat promosi dikirim.`,
+💡 Variabel dinamis seperti *{DATE}* dan *{TIME}* akan diganti saat promosi dikirim. Blok *{IF ...}* dan *{EACH ...}* dirender sesuai data saat itu, dan spintax *{Halo|Hai}* dipilih acak untuk setiap grup.`,
 		template.Title,
 		template.Category,
 		getStatusText(template.IsActive),
+		templateMediaLine(template),
 		preview), nil
 }
 
@@ -282,27 +370,12 @@ func (s *TemplateService) validateTemplate(title, content, category string) erro
 		return fmt.Errorf("kategori template maksimal 50 karakter")
 	}
 
-	return nil
-}
-
-// processTemplateForPreview memproses template untuk preview
-func (s *TemplateService) processTemplateForPreview(content string) string {
-	now := time.Now()
-
-	replacements := map[string]string{
-		"{DATE}":  now.Format("2006-01-02"),
-		"{TIME}":  now.Format("15:04"),
-		"{DAY}":   getDayName(now.Weekday()),
-		"{MONTH}": getMonthName(now.Month()),
-		"{YEAR}":  fmt.Sprintf("%d", now.Year()),
-	}
-
-	result := content
-	for placeholder, value := range replacements {
-		result = strings.ReplaceAll(result, placeholder, value)
+	// Pastikan template bisa dirender (blok lengkap, variabel dikenal, spintax valid)
+	if err := s.renderer.Validate(content); err != nil {
+		return err
 	}
 
-	return result
+	return nil
 }
 
 // getStatusText mengkonversi boolean status ke teks
@@ -0,0 +1,698 @@
+// Package services - Ekspor dan impor template dalam bentuk file JSON (bundle)
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/render"
+)
+
+// templateBundleFormat menandai file JSON sebagai bundle template bot ini
+const templateBundleFormat = "promote-templates"
+
+// templateBundleVersion adalah versi format bundle yang ditulis saat ekspor.
+// Versi 2 menambahkan media template (file disematkan sebagai base64),
+// versi 3 menambahkan masa berlaku dan terjemahan template.
+const templateBundleVersion = 3
+
+// bundleValidityVersion adalah versi bundle pertama yang membawa masa berlaku.
+// Bundle lama tidak mengubah masa berlaku template yang sudah ada.
+const bundleValidityVersion = 3
+
+// MaxTemplateBundleSize adalah ukuran maksimal file bundle yang diterima saat impor
+// (cukup untuk beberapa template bermedia)
+const MaxTemplateBundleSize = 64 << 20
+
+// TemplateBundle adalah isi file ekspor template
+type TemplateBundle struct {
+	Format     string           `json:"format"`
+	Version    int              `json:"version"`
+	ExportedAt time.Time        `json:"exported_at"`
+	Templates  []BundleTemplate `json:"templates"`
+}
+
+// BundleTemplate adalah satu template di dalam bundle. ID hanya informasi
+// asal; saat impor template dicocokkan berdasarkan judul karena ID di
+// nomor lain (staging/production) bisa berbeda.
+type BundleTemplate struct {
+	ID       int      `json:"id,omitempty"`
+	Title    string   `json:"title"`
+	Category string   `json:"category"`
+	Content  string   `json:"content"`
+	IsActive bool     `json:"is_active"`
+	Weight   int      `json:"weight,omitempty"`
+	ABSplit  string   `json:"ab_split,omitempty"`
+	Variants []string `json:"variants,omitempty"` // Konten varian A/B yang aktif
+
+	Translations map[string]string `json:"translations,omitempty"` // Konten terjemahan per locale
+
+	Media    *BundleMedia    `json:"media,omitempty"`
+	Validity *BundleValidity `json:"validity,omitempty"`
+}
+
+// BundleValidity adalah masa berlaku template di dalam bundle. Waktu ditulis
+// lengkap dengan zona waktunya sehingga tetap sama di nomor tujuan.
+type BundleValidity struct {
+	From  *time.Time `json:"from,omitempty"`
+	Until *time.Time `json:"until,omitempty"`
+	Days  string     `json:"days,omitempty"`  // Hari tayang, misal "sen,sel,rab"
+	Hours string     `json:"hours,omitempty"` // Jam tayang, misal "08:00-21:00"
+}
+
+// BundleMedia adalah media template di dalam bundle. Data kosong berarti file
+// media tidak tersedia saat ekspor sehingga hanya referensinya yang tercatat.
+type BundleMedia struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Mimetype string `json:"mimetype,omitempty"`
+	Data     []byte `json:"data,omitempty"` // Isi file, base64 di JSON
+}
+
+// Jenis hasil impor per template
+const (
+	ImportCreate    = "create"    // Template baru
+	ImportUpdate    = "update"    // Template dengan judul sama akan diperbarui
+	ImportUnchanged = "unchanged" // Sama persis dengan template yang ada
+	ImportConflict  = "conflict"  // Tidak bisa diimpor, lihat Reason
+)
+
+// ImportItem adalah rencana (atau hasil) impor satu template dari bundle
+type ImportItem struct {
+	Action     string
+	Title      string
+	ExistingID int      // ID template yang dicocokkan (0 untuk template baru)
+	Changes    []string // Field yang berubah untuk ImportUpdate
+	Reason     string   // Alasan untuk ImportConflict atau kegagalan saat diterapkan
+	MediaNote  string   // Media di bundle yang tidak ikut diimpor (misal file tidak ada di bundle)
+}
+
+// ImportResult adalah ringkasan impor bundle. Applied false berarti dry run.
+type ImportResult struct {
+	Items   []ImportItem
+	Applied bool
+	Failed  int // Jumlah create/update yang gagal saat diterapkan
+}
+
+// Count menghitung item dengan jenis hasil tertentu
+func (r *ImportResult) Count(action string) int {
+	count := 0
+	for _, item := range r.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// ExportTemplates membuat bundle JSON dari template dengan ID tertentu
+// (kosong = semua template). Mengembalikan isi file dan jumlah template.
+func (s *TemplateService) ExportTemplates(ids []int) ([]byte, int, error) {
+	var templates []database.PromoteTemplate
+	if len(ids) == 0 {
+		all, err := s.repository.GetAllTemplates()
+		if err != nil {
+			s.logger.Errorf("Failed to get templates for export: %v", err)
+			return nil, 0, fmt.Errorf("gagal mengambil template: %v", err)
+		}
+		templates = all
+	} else {
+		for _, id := range ids {
+			template, err := s.GetTemplateByID(id)
+			if err != nil {
+				return nil, 0, err
+			}
+			templates = append(templates, *template)
+		}
+	}
+
+	if len(templates) == 0 {
+		return nil, 0, fmt.Errorf("tidak ada template untuk diekspor")
+	}
+
+	bundle := TemplateBundle{
+		Format:     templateBundleFormat,
+		Version:    templateBundleVersion,
+		ExportedAt: time.Now(),
+	}
+
+	for _, template := range templates {
+		entry := BundleTemplate{
+			ID:       template.ID,
+			Title:    template.Title,
+			Category: template.Category,
+			Content:  template.Content,
+			IsActive: template.IsActive,
+			Weight:   template.Weight,
+			ABSplit:  template.ABSplit,
+			Media:    s.bundleMedia(&template),
+		}
+		if HasValidity(&template) {
+			entry.Validity = &BundleValidity{
+				From:  template.ValidFrom,
+				Until: template.ValidUntil,
+				Days:  template.ValidDays,
+				Hours: template.ValidHours,
+			}
+		}
+
+		variants, err := s.repository.GetTemplateVariants(template.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gagal memuat varian template %d: %v", template.ID, err)
+		}
+		for _, variant := range variants {
+			if variant.IsActive {
+				entry.Variants = append(entry.Variants, variant.Content)
+			}
+		}
+
+		translations, err := s.repository.GetTemplateTranslations(template.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gagal memuat terjemahan template %d: %v", template.ID, err)
+		}
+		for _, translation := range translations {
+			if entry.Translations == nil {
+				entry.Translations = make(map[string]string)
+			}
+			entry.Translations[translation.Locale] = translation.Content
+		}
+
+		bundle.Templates = append(bundle.Templates, entry)
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("gagal membuat file ekspor: %v", err)
+	}
+
+	s.logger.Infof("Exported %d templates", len(bundle.Templates))
+	return data, len(bundle.Templates), nil
+}
+
+// ImportTemplates membaca bundle JSON dan mencocokkannya dengan template yang ada.
+// Dengan apply false hanya ringkasan (dry run) yang dibuat tanpa mengubah database.
+func (s *TemplateService) ImportTemplates(data []byte, apply bool, author string) (*ImportResult, error) {
+	bundle, err := parseTemplateBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repository.GetAllTemplates()
+	if err != nil {
+		s.logger.Errorf("Failed to get templates for import: %v", err)
+		return nil, fmt.Errorf("gagal mengambil template: %v", err)
+	}
+
+	byTitle := make(map[string][]database.PromoteTemplate)
+	for _, template := range existing {
+		key := bundleTitleKey(template.Title)
+		byTitle[key] = append(byTitle[key], template)
+	}
+
+	result := &ImportResult{Applied: apply}
+	seen := make(map[string]bool)
+
+	for _, entry := range bundle.Templates {
+		item := ImportItem{Title: strings.TrimSpace(entry.Title)}
+		key := bundleTitleKey(entry.Title)
+		matches := byTitle[key]
+
+		switch {
+		case seen[key]:
+			item.Action = ImportConflict
+			item.Reason = "judul muncul lebih dari sekali di file"
+		case len(matches) > 1:
+			item.Action = ImportConflict
+			item.Reason = fmt.Sprintf("ada %d template dengan judul yang sama", len(matches))
+		default:
+			if err := s.validateBundleTemplate(&entry); err != nil {
+				item.Action = ImportConflict
+				item.Reason = err.Error()
+			} else if len(matches) == 0 {
+				item.Action = ImportCreate
+				item.MediaNote = s.bundleMediaNote(entry)
+			} else {
+				item.ExistingID = matches[0].ID
+				item.Changes = s.bundleTemplateChanges(&matches[0], entry)
+				item.Action = ImportUpdate
+				if len(item.Changes) == 0 {
+					item.Action = ImportUnchanged
+				}
+				item.MediaNote = s.bundleMediaNote(entry)
+			}
+		}
+		seen[key] = true
+
+		if apply {
+			switch item.Action {
+			case ImportCreate:
+				template, err := s.createFromBundle(entry, author)
+				if template != nil {
+					item.ExistingID = template.ID
+				}
+				if err != nil {
+					item.Reason = err.Error()
+					result.Failed++
+				}
+			case ImportUpdate:
+				if err := s.updateFromBundle(&matches[0], entry, author); err != nil {
+					item.Reason = err.Error()
+					result.Failed++
+				}
+			}
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	if apply {
+		s.logger.Successf("Imported templates: %d created, %d updated, %d conflicts, %d failed",
+			result.Count(ImportCreate), result.Count(ImportUpdate), result.Count(ImportConflict), result.Failed)
+	}
+	return result, nil
+}
+
+// parseTemplateBundle membaca dan memeriksa format file bundle
+func parseTemplateBundle(data []byte) (*TemplateBundle, error) {
+	if len(data) > MaxTemplateBundleSize {
+		return nil, fmt.Errorf("file terlalu besar (maksimal %d KB)", MaxTemplateBundleSize>>10)
+	}
+
+	var bundle TemplateBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("file bukan JSON bundle template yang valid: %v", err)
+	}
+
+	if bundle.Format != templateBundleFormat {
+		return nil, fmt.Errorf("file bukan hasil .exporttemplates (format '%s')", bundle.Format)
+	}
+
+	if bundle.Version < 1 || bundle.Version > templateBundleVersion {
+		return nil, fmt.Errorf("versi bundle %d belum didukung, perbarui bot terlebih dahulu", bundle.Version)
+	}
+
+	if len(bundle.Templates) == 0 {
+		return nil, fmt.Errorf("bundle tidak berisi template")
+	}
+
+	// Sejak versi 3 template tanpa masa berlaku memang tidak dibatasi, jadi
+	// masa berlaku di nomor tujuan ikut dihapus. Validity nil hanya untuk bundle lama.
+	if bundle.Version >= bundleValidityVersion {
+		for i := range bundle.Templates {
+			if bundle.Templates[i].Validity == nil {
+				bundle.Templates[i].Validity = &BundleValidity{}
+			}
+		}
+	}
+
+	return &bundle, nil
+}
+
+// validateBundleTemplate memvalidasi satu template dari bundle seperti template baru.
+// Locale terjemahan serta hari dan jam tayang dinormalisasi di entry agar sama
+// dengan hasil .translate dan .setvalidity.
+func (s *TemplateService) validateBundleTemplate(entry *BundleTemplate) error {
+	if err := s.validateTemplate(entry.Title, entry.Content, entry.Category); err != nil {
+		return err
+	}
+
+	if entry.Weight < 0 || entry.Weight > 100 {
+		return fmt.Errorf("bobot harus antara 1-100")
+	}
+
+	if entry.ABSplit != "" && entry.ABSplit != database.ABSplitSend && entry.ABSplit != database.ABSplitGroup {
+		return fmt.Errorf("pembagian varian '%s' tidak dikenal", entry.ABSplit)
+	}
+
+	if len(entry.Variants) > maxTemplateVariants {
+		return fmt.Errorf("maksimal %d varian per template", maxTemplateVariants)
+	}
+
+	for i, variant := range entry.Variants {
+		if err := s.renderer.Validate(variant); err != nil {
+			return fmt.Errorf("varian ke-%d: %v", i+1, err)
+		}
+	}
+
+	if len(entry.Translations) > 0 {
+		translations := make(map[string]string, len(entry.Translations))
+		for value, content := range entry.Translations {
+			locale, err := render.ParseLocale(value)
+			if err != nil {
+				return fmt.Errorf("terjemahan: %v", err)
+			}
+			if _, ok := translations[locale]; ok {
+				return fmt.Errorf("terjemahan %s muncul lebih dari sekali", locale)
+			}
+			content = strings.TrimSpace(content)
+			if content == "" {
+				return fmt.Errorf("konten terjemahan %s tidak boleh kosong", locale)
+			}
+			if err := s.renderer.Validate(content); err != nil {
+				return fmt.Errorf("terjemahan %s: %v", locale, err)
+			}
+			translations[locale] = content
+		}
+		entry.Translations = translations
+	}
+
+	if entry.Media != nil {
+		if _, err := whatsmeowMediaType(entry.Media.Type); err != nil {
+			return err
+		}
+		if len(entry.Media.Data) > MaxMediaSize {
+			return fmt.Errorf("media terlalu besar (maksimal %d MB)", MaxMediaSize>>20)
+		}
+	}
+
+	if entry.Validity != nil {
+		if err := normalizeBundleValidity(entry.Validity); err != nil {
+			return fmt.Errorf("masa berlaku: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// normalizeBundleValidity memeriksa masa berlaku dari bundle dengan aturan yang
+// sama seperti .setvalidity. Waktu berakhir yang sudah lewat tetap diterima karena
+// template seperti itu tidak akan dikirim dan akan dinonaktifkan otomatis.
+func normalizeBundleValidity(validity *BundleValidity) error {
+	days, err := ParseWeekdays(validity.Days)
+	if err != nil {
+		return err
+	}
+
+	window, err := ParseSendWindow(validity.Hours)
+	if err != nil {
+		return err
+	}
+	hours := ""
+	if !window.IsAlwaysOpen() {
+		hours = window.String()
+	}
+
+	if validity.From != nil && validity.Until != nil && !validity.Until.After(*validity.From) {
+		return fmt.Errorf("waktu berakhir harus setelah waktu mulai")
+	}
+
+	validity.Days, validity.Hours = days, hours
+	return nil
+}
+
+// bundleValidityChanged mengecek apakah masa berlaku di bundle berbeda dengan template
+func bundleValidityChanged(existing *database.PromoteTemplate, validity *BundleValidity) bool {
+	if validity == nil {
+		return false
+	}
+	return !sameValidityTime(existing.ValidFrom, validity.From) ||
+		!sameValidityTime(existing.ValidUntil, validity.Until) ||
+		existing.ValidDays != validity.Days || existing.ValidHours != validity.Hours
+}
+
+// sameValidityTime membandingkan dua batas waktu yang boleh kosong
+func sameValidityTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// applyBundleValidity menyalin masa berlaku dari bundle ke template
+func applyBundleValidity(template *database.PromoteTemplate, validity *BundleValidity) {
+	if validity == nil {
+		return
+	}
+	template.ValidFrom, template.ValidUntil = validity.From, validity.Until
+	template.ValidDays, template.ValidHours = validity.Days, validity.Hours
+}
+
+// bundleTemplateChanges mendaftar field yang berbeda antara template yang ada dan bundle
+func (s *TemplateService) bundleTemplateChanges(existing *database.PromoteTemplate, entry BundleTemplate) []string {
+	var changes []string
+
+	if existing.Title != strings.TrimSpace(entry.Title) {
+		changes = append(changes, "judul")
+	}
+	if existing.Category != strings.ToLower(strings.TrimSpace(entry.Category)) {
+		changes = append(changes, "kategori")
+	}
+	if existing.Content != strings.TrimSpace(entry.Content) {
+		changes = append(changes, "konten")
+	}
+	if existing.IsActive != entry.IsActive {
+		changes = append(changes, "status")
+	}
+	if entry.Weight > 0 && existing.Weight != entry.Weight {
+		changes = append(changes, "bobot")
+	}
+	if entry.ABSplit != "" && existing.ABSplit != entry.ABSplit {
+		changes = append(changes, "pembagian A/B")
+	}
+
+	if len(s.newBundleVariants(existing.ID, entry.Variants)) > 0 {
+		changes = append(changes, "varian")
+	}
+	if len(s.changedBundleTranslations(existing.ID, entry.Translations)) > 0 {
+		changes = append(changes, "terjemahan")
+	}
+	if s.bundleMediaChanged(existing, entry.Media) {
+		changes = append(changes, "media")
+	}
+	if bundleValidityChanged(existing, entry.Validity) {
+		changes = append(changes, "masa berlaku")
+	}
+
+	return changes
+}
+
+// bundleMedia menyiapkan media template untuk bundle beserta isi filenya.
+// Jika file tidak bisa dibaca, hanya referensinya yang diekspor.
+func (s *TemplateService) bundleMedia(template *database.PromoteTemplate) *BundleMedia {
+	if template.MediaType == "" {
+		return nil
+	}
+
+	media := &BundleMedia{
+		Type:     template.MediaType,
+		Name:     template.MediaName,
+		Mimetype: template.MediaMimetype,
+	}
+	if s.media == nil {
+		s.logger.Warningf("Media store not configured, exporting media reference of template %d only", template.ID)
+		return media
+	}
+
+	data, err := s.media.Read(template.MediaPath)
+	if err != nil {
+		s.logger.Warningf("Media of template %d unavailable, exporting reference only: %v", template.ID, err)
+		return media
+	}
+	media.Data = data
+	return media
+}
+
+// bundleMediaChanged mengecek apakah media di bundle berbeda dengan media template.
+// Bundle tanpa media (atau tanpa isi file) tidak mengubah media yang sudah ada.
+func (s *TemplateService) bundleMediaChanged(existing *database.PromoteTemplate, media *BundleMedia) bool {
+	if media == nil || len(media.Data) == 0 {
+		return false
+	}
+	if existing.MediaType != media.Type || s.media == nil {
+		return true
+	}
+
+	current, err := s.media.Read(existing.MediaPath)
+	return err != nil || !bytes.Equal(current, media.Data)
+}
+
+// bundleMediaNote menjelaskan media di bundle yang tidak bisa diimpor
+func (s *TemplateService) bundleMediaNote(entry BundleTemplate) string {
+	switch {
+	case entry.Media == nil:
+		return ""
+	case len(entry.Media.Data) == 0:
+		return fmt.Sprintf("file %s tidak ada di bundle, media tidak diimpor", MediaTypeLabel(entry.Media.Type))
+	case s.media == nil:
+		return "penyimpanan media belum diatur, media tidak diimpor"
+	}
+	return ""
+}
+
+// applyBundleMedia memasang media dari bundle ke template jika berbeda
+func (s *TemplateService) applyBundleMedia(template *database.PromoteTemplate, entry BundleTemplate) error {
+	if s.bundleMediaNote(entry) != "" || !s.bundleMediaChanged(template, entry.Media) {
+		return nil
+	}
+
+	media := entry.Media
+	updated, err := s.AttachTemplateMedia(template.ID, media.Type, media.Data, media.Mimetype, media.Name)
+	if err != nil {
+		return fmt.Errorf("template tersimpan, tetapi media gagal: %v", err)
+	}
+
+	template.MediaType = updated.MediaType
+	template.MediaPath = updated.MediaPath
+	template.MediaMimetype = updated.MediaMimetype
+	template.MediaName = updated.MediaName
+	return nil
+}
+
+// newBundleVariants mengambil konten varian dari bundle yang belum ada di template
+func (s *TemplateService) newBundleVariants(templateID int, contents []string) []string {
+	if len(contents) == 0 {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	if templateID > 0 {
+		variants, err := s.repository.GetTemplateVariants(templateID)
+		if err != nil {
+			s.logger.Warningf("Failed to get variants of template %d: %v", templateID, err)
+		}
+		for _, variant := range variants {
+			known[variant.Content] = true
+		}
+	}
+
+	var added []string
+	for _, content := range contents {
+		content = strings.TrimSpace(content)
+		if content == "" || known[content] {
+			continue
+		}
+		known[content] = true
+		added = append(added, content)
+	}
+	return added
+}
+
+// changedBundleTranslations mengambil locale terjemahan di bundle yang belum ada
+// atau isinya berbeda dengan terjemahan template, urut locale
+func (s *TemplateService) changedBundleTranslations(templateID int, translations map[string]string) []string {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	known := make(map[string]string)
+	if templateID > 0 {
+		existing, err := s.repository.GetTemplateTranslations(templateID)
+		if err != nil {
+			s.logger.Warningf("Failed to get translations of template %d: %v", templateID, err)
+		}
+		for _, translation := range existing {
+			known[translation.Locale] = translation.Content
+		}
+	}
+
+	var changed []string
+	for locale, content := range translations {
+		if current, ok := known[locale]; !ok || current != content {
+			changed = append(changed, locale)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// createFromBundle membuat template baru dari bundle beserta variannya
+func (s *TemplateService) createFromBundle(entry BundleTemplate, author string) (*database.PromoteTemplate, error) {
+	template := &database.PromoteTemplate{
+		Title:    strings.TrimSpace(entry.Title),
+		Content:  strings.TrimSpace(entry.Content),
+		Category: strings.ToLower(strings.TrimSpace(entry.Category)),
+		IsActive: entry.IsActive,
+		Weight:   entry.Weight,
+	}
+	applyBundleValidity(template, entry.Validity)
+
+	if err := s.repository.CreateTemplate(template); err != nil {
+		s.logger.Errorf("Failed to create imported template %s: %v", template.Title, err)
+		return nil, fmt.Errorf("gagal membuat template: %v", err)
+	}
+
+	s.recordRevision(template, database.RevisionCreate, author)
+	s.importBundleExtras(template, entry, author)
+	if err := s.applyBundleMedia(template, entry); err != nil {
+		return template, err
+	}
+	return template, nil
+}
+
+// updateFromBundle memperbarui template yang ada dengan isi bundle
+func (s *TemplateService) updateFromBundle(existing *database.PromoteTemplate, entry BundleTemplate, author string) error {
+	s.ensureBaselineRevision(existing)
+
+	existing.Title = strings.TrimSpace(entry.Title)
+	existing.Content = strings.TrimSpace(entry.Content)
+	existing.Category = strings.ToLower(strings.TrimSpace(entry.Category))
+	existing.IsActive = entry.IsActive
+	if entry.Weight > 0 {
+		existing.Weight = entry.Weight
+	}
+	applyBundleValidity(existing, entry.Validity)
+
+	if err := s.repository.UpdateTemplate(existing); err != nil {
+		s.logger.Errorf("Failed to update imported template %d: %v", existing.ID, err)
+		return fmt.Errorf("gagal mengupdate template: %v", err)
+	}
+
+	s.recordRevision(existing, database.RevisionEdit, author)
+	s.importBundleExtras(existing, entry, author)
+	return s.applyBundleMedia(existing, entry)
+}
+
+// importBundleExtras menyimpan pembagian A/B, terjemahan dan varian baru dari bundle
+func (s *TemplateService) importBundleExtras(template *database.PromoteTemplate, entry BundleTemplate, author string) {
+	if entry.ABSplit != "" && entry.ABSplit != template.ABSplit {
+		if err := s.repository.SetTemplateABSplit(template.ID, entry.ABSplit); err != nil {
+			s.logger.Errorf("Failed to set A/B split of imported template %d: %v", template.ID, err)
+		}
+	}
+
+	for _, locale := range s.changedBundleTranslations(template.ID, entry.Translations) {
+		translation := &database.TemplateTranslation{
+			TemplateID: template.ID,
+			Locale:     locale,
+			Content:    entry.Translations[locale],
+			UpdatedBy:  author,
+		}
+		if err := s.repository.SetTemplateTranslation(translation); err != nil {
+			s.logger.Errorf("Failed to import %s translation for template %d: %v", locale, template.ID, err)
+		}
+	}
+
+	existing, err := s.repository.GetTemplateVariants(template.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to get variants of imported template %d: %v", template.ID, err)
+		return
+	}
+
+	count := len(existing)
+	for _, content := range s.newBundleVariants(template.ID, entry.Variants) {
+		if count >= maxTemplateVariants {
+			s.logger.Warningf("Imported template %d reached %d variants, skipping the rest", template.ID, maxTemplateVariants)
+			break
+		}
+		count++
+
+		variant := &database.TemplateVariant{
+			TemplateID: template.ID,
+			Content:    content,
+			CreatedBy:  author,
+		}
+		if err := s.repository.CreateTemplateVariant(variant); err != nil {
+			s.logger.Errorf("Failed to import variant for template %d: %v", template.ID, err)
+		}
+	}
+}
+
+// bundleTitleKey adalah kunci pencocokan judul template saat impor
+func bundleTitleKey(title string) string {
+	return strings.ToLower(strings.TrimSpace(title))
+}
@@ -0,0 +1,143 @@
+// Package services - Langganan kategori template per grup
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// maxCategoryLength sama dengan batas panjang kategori template
+const maxCategoryLength = 50
+
+// GetGroupCategories mengambil kategori yang diikuti grup (kosong = semua kategori)
+func (s *AutoPromoteService) GetGroupCategories(groupJID string) ([]string, error) {
+	subscriptions, err := s.repository.GetGroupCategories(groupJID)
+	if err != nil {
+		return nil, err
+	}
+	return categoryNames(subscriptions), nil
+}
+
+// GetAllGroupCategories mengambil kategori semua grup, dikelompokkan per JID grup
+func (s *AutoPromoteService) GetAllGroupCategories() (map[string][]string, error) {
+	subscriptions, err := s.repository.GetAllGroupCategories()
+	if err != nil {
+		return nil, err
+	}
+	return groupCategoriesByJID(subscriptions), nil
+}
+
+// SetGroupCategories mengganti kategori yang diikuti grup. Daftar kosong membuat
+// grup kembali menerima template dari semua kategori.
+func (s *AutoPromoteService) SetGroupCategories(groupJID string, categories []string, author string) ([]string, error) {
+	normalized := normalizeCategories(categories)
+	for _, category := range normalized {
+		if len(category) > maxCategoryLength {
+			return nil, fmt.Errorf("kategori '%s' terlalu panjang (maksimal %d karakter)", category, maxCategoryLength)
+		}
+	}
+
+	if err := s.repository.SetGroupCategories(groupJID, normalized, author); err != nil {
+		s.logger.Errorf("Failed to set categories for group %s: %v", groupJID, err)
+		return nil, fmt.Errorf("gagal menyimpan kategori grup: %v", err)
+	}
+
+	if len(normalized) == 0 {
+		s.logger.Infof("Group %s now receives all template categories", groupJID)
+	} else {
+		s.logger.Infof("Group %s subscribed to categories: %s", groupJID, strings.Join(normalized, ", "))
+	}
+	return normalized, nil
+}
+
+// GetTemplatesForGroup mengambil template aktif yang boleh dikirim ke grup saat ini
+// beserta kategori yang diikuti grup tersebut
+func (s *AutoPromoteService) GetTemplatesForGroup(groupJID string) ([]database.PromoteTemplate, []string, error) {
+	return activeTemplatesForGroup(s.repository, groupJID, s.now())
+}
+
+// activeTemplatesForGroup mengambil template aktif yang sedang dalam masa tayang dan
+// sesuai kategori grup. now harus sudah dalam timezone scheduler, sama seperti rotasi.
+func activeTemplatesForGroup(repo database.Repository, groupJID string, now time.Time) ([]database.PromoteTemplate, []string, error) {
+	templates, err := repo.GetActiveTemplates()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get templates: %v", err)
+	}
+	templates = filterValidTemplates(templates, now)
+
+	subscriptions, err := repo.GetGroupCategories(groupJID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get group categories: %v", err)
+	}
+
+	categories := categoryNames(subscriptions)
+	return filterTemplatesByCategories(templates, categories), categories, nil
+}
+
+// filterTemplatesByCategories menyaring template sesuai kategori.
+// Daftar kategori kosong berarti semua template boleh dipakai.
+func filterTemplatesByCategories(templates []database.PromoteTemplate, categories []string) []database.PromoteTemplate {
+	if len(categories) == 0 {
+		return templates
+	}
+
+	allowed := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		allowed[category] = true
+	}
+
+	var filtered []database.PromoteTemplate
+	for _, template := range templates {
+		if allowed[template.Category] {
+			filtered = append(filtered, template)
+		}
+	}
+	return filtered
+}
+
+// noTemplatesForCategoriesError menjelaskan kenapa grup tidak punya template untuk dikirim
+func noTemplatesForCategoriesError(categories []string) error {
+	if len(categories) == 0 {
+		return fmt.Errorf("no active templates available")
+	}
+	return fmt.Errorf("tidak ada template aktif yang sedang berlaku di kategori grup (%s)", strings.Join(categories, ", "))
+}
+
+// normalizeCategories menyamakan penulisan kategori dengan PromoteTemplate.Category
+// (huruf kecil, tanpa spasi di ujung), membuang duplikat dan mengurutkannya
+func normalizeCategories(categories []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, category := range categories {
+		category = strings.ToLower(strings.TrimSpace(category))
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		normalized = append(normalized, category)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// categoryNames mengambil nama kategori dari daftar GroupCategory
+func categoryNames(subscriptions []database.GroupCategory) []string {
+	var names []string
+	for _, subscription := range subscriptions {
+		names = append(names, subscription.Category)
+	}
+	return names
+}
+
+// groupCategoriesByJID mengelompokkan GroupCategory per JID grup
+func groupCategoriesByJID(subscriptions []database.GroupCategory) map[string][]string {
+	byGroup := make(map[string][]string)
+	for _, subscription := range subscriptions {
+		byGroup[subscription.GroupJID] = append(byGroup[subscription.GroupJID], subscription.Category)
+	}
+	return byGroup
+}
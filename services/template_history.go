@@ -0,0 +1,315 @@
+// Package services - Riwayat revisi template, diff dan rollback
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// TemplateDiff adalah perbandingan satu revisi dengan kondisi template sekarang
+type TemplateDiff struct {
+	From    *database.TemplateRevision // Revisi yang diminta
+	To      *database.TemplateRevision // Kondisi sekarang (Revision 0) atau revisi terakhir jika template sudah dihapus
+	Deleted bool                       // Template sudah dihapus
+	Lines   []DiffLine                 // Perbedaan konten per baris
+}
+
+// TemplateRollback adalah hasil rollback atau pemulihan template
+type TemplateRollback struct {
+	Template *database.PromoteTemplate
+	Restored bool     // true jika template yang terhapus dipulihkan
+	Notes    []string // Bagian revisi yang tidak bisa dikembalikan apa adanya
+}
+
+// DiffLine adalah satu baris hasil diff. Op bernilai ' ' (sama), '-' (dihapus) atau '+' (ditambah).
+type DiffLine struct {
+	Op   byte
+	Text string
+}
+
+// GetTemplateHistory mengambil semua revisi template (terbaru dulu)
+func (s *TemplateService) GetTemplateHistory(id int) ([]database.TemplateRevision, error) {
+	revisions, err := s.repository.GetTemplateRevisions(id)
+	if err != nil {
+		s.logger.Errorf("Failed to get revisions of template %d: %v", id, err)
+		return nil, fmt.Errorf("gagal mengambil riwayat template: %v", err)
+	}
+
+	if len(revisions) == 0 {
+		template, err := s.repository.GetTemplateByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if template == nil {
+			return nil, fmt.Errorf("template dengan ID %d tidak ditemukan", id)
+		}
+	}
+
+	return revisions, nil
+}
+
+// GetTemplateRevision mengambil satu revisi template
+func (s *TemplateService) GetTemplateRevision(id, revision int) (*database.TemplateRevision, error) {
+	rev, err := s.repository.GetTemplateRevision(id, revision)
+	if err != nil {
+		s.logger.Errorf("Failed to get revision %d of template %d: %v", revision, id, err)
+		return nil, fmt.Errorf("gagal mengambil revisi template: %v", err)
+	}
+
+	if rev == nil {
+		return nil, fmt.Errorf("revisi %d untuk template ID %d tidak ditemukan", revision, id)
+	}
+
+	return rev, nil
+}
+
+// DiffTemplateRevision membandingkan revisi dengan kondisi template sekarang.
+// Untuk template yang sudah dihapus, pembandingnya adalah isi terakhir sebelum dihapus.
+func (s *TemplateService) DiffTemplateRevision(id, revision int) (*TemplateDiff, error) {
+	from, err := s.GetTemplateRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &TemplateDiff{From: from}
+
+	current, err := s.repository.GetTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil {
+		diff.To = revisionFromTemplate(current, "", "")
+		diff.To.CreatedAt = current.UpdatedAt
+	} else {
+		revisions, err := s.repository.GetTemplateRevisions(id)
+		if err != nil {
+			return nil, fmt.Errorf("gagal mengambil riwayat template: %v", err)
+		}
+		latest := revisions[0]
+		diff.To = &latest
+		diff.Deleted = true
+	}
+
+	diff.Lines = diffLines(splitLines(from.Content), splitLines(diff.To.Content))
+	return diff, nil
+}
+
+// RollbackTemplate mengembalikan template ke isi revisi tertentu (judul, konten,
+// kategori, status, bobot dan masa berlaku). Template yang sudah dihapus dipulihkan
+// dengan ID yang sama beserta medianya. Template yang masa berlakunya sudah lewat
+// dikembalikan dalam keadaan nonaktif.
+func (s *TemplateService) RollbackTemplate(id, revision int, author string) (*TemplateRollback, error) {
+	rev, err := s.GetTemplateRevision(id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variabel yang dipakai revisi lama mungkin sudah dihapus
+	if err := s.validateTemplate(rev.Title, rev.Content, rev.Category); err != nil {
+		return nil, fmt.Errorf("revisi %d tidak bisa dipakai lagi: %v", revision, err)
+	}
+
+	existing, err := s.repository.GetTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TemplateRollback{}
+	if !rev.HasDetails {
+		result.Notes = append(result.Notes, "Revisi ini dibuat sebelum media dan masa berlaku ikut dicatat, keduanya tidak ikut dikembalikan")
+	}
+
+	if existing == nil {
+		restored := &database.PromoteTemplate{
+			ID:       id,
+			Title:    rev.Title,
+			Content:  rev.Content,
+			Category: rev.Category,
+			IsActive: rev.IsActive,
+			Weight:   rev.Weight,
+		}
+		applyRevisionValidity(restored, rev)
+
+		// File media sengaja tidak dihapus saat template dihapus, jadi bisa dipasang lagi
+		if rev.MediaType != "" {
+			if s.media != nil && s.media.Exists(rev.MediaPath) {
+				restored.MediaType = rev.MediaType
+				restored.MediaPath = rev.MediaPath
+				restored.MediaMimetype = rev.MediaMimetype
+				restored.MediaName = rev.MediaName
+			} else {
+				result.Notes = append(result.Notes, "File media sudah tidak ada, template dipulihkan tanpa media")
+			}
+		}
+		result.Notes = append(result.Notes, deactivateIfExpired(restored)...)
+
+		if err := s.repository.RestoreTemplate(restored); err != nil {
+			s.logger.Errorf("Failed to restore template %d: %v", id, err)
+			return nil, fmt.Errorf("gagal memulihkan template: %v", err)
+		}
+
+		s.recordRevision(restored, database.RevisionRestore, author)
+		s.logger.Successf("Template restored from revision %d: %s (ID: %d)", revision, restored.Title, restored.ID)
+		result.Template = restored
+		result.Restored = true
+		return result, nil
+	}
+
+	s.ensureBaselineRevision(existing)
+
+	existing.Title = rev.Title
+	existing.Content = rev.Content
+	existing.Category = rev.Category
+	existing.IsActive = rev.IsActive
+	existing.Weight = rev.Weight
+	applyRevisionValidity(existing, rev)
+	result.Notes = append(result.Notes, deactivateIfExpired(existing)...)
+
+	// Media lama sudah dihapus saat diganti, jadi media template yang ada dipertahankan
+	if rev.HasDetails && rev.MediaPath != existing.MediaPath {
+		result.Notes = append(result.Notes, fmt.Sprintf("Media tidak ikut dikembalikan, gunakan .attachmedia %d untuk menggantinya", id))
+	}
+
+	if err := s.repository.UpdateTemplate(existing); err != nil {
+		s.logger.Errorf("Failed to roll back template %d: %v", id, err)
+		return nil, fmt.Errorf("gagal mengembalikan template: %v", err)
+	}
+
+	s.recordRevision(existing, database.RevisionRollback, author)
+	s.logger.Successf("Template rolled back to revision %d: %s (ID: %d)", revision, existing.Title, existing.ID)
+	result.Template = existing
+	return result, nil
+}
+
+// applyRevisionValidity menyalin masa berlaku revisi (jika revisi menyimpannya) ke template
+func applyRevisionValidity(template *database.PromoteTemplate, rev *database.TemplateRevision) {
+	if !rev.HasDetails {
+		return
+	}
+
+	template.ValidFrom = rev.ValidFrom
+	template.ValidUntil = rev.ValidUntil
+	template.ValidDays = rev.ValidDays
+	template.ValidHours = rev.ValidHours
+}
+
+// deactivateIfExpired menonaktifkan template yang masa berlakunya sudah lewat,
+// agar rollback tidak menghidupkan lagi promo yang sudah berakhir
+func deactivateIfExpired(template *database.PromoteTemplate) []string {
+	if !template.IsActive || !TemplateExpired(template, time.Now()) {
+		return nil
+	}
+
+	template.IsActive = false
+	return []string{"Masa berlaku sudah berakhir, template dibiarkan nonaktif (perpanjang dengan .validity)"}
+}
+
+// saveRevision menyimpan salinan template ke riwayat
+func (s *TemplateService) saveRevision(template *database.PromoteTemplate, action, author string) error {
+	return s.repository.CreateTemplateRevision(revisionFromTemplate(template, action, author))
+}
+
+// recordRevision seperti saveRevision, tapi kegagalan hanya dicatat di log
+// karena perubahan template-nya sendiri sudah tersimpan
+func (s *TemplateService) recordRevision(template *database.PromoteTemplate, action, author string) {
+	if err := s.saveRevision(template, action, author); err != nil {
+		s.logger.Errorf("Failed to save revision of template %d: %v", template.ID, err)
+	}
+}
+
+// ensureBaselineRevision menyimpan kondisi template lama (dibuat sebelum ada
+// riwayat) sebagai revisi pertama, agar perubahan pertamanya tetap bisa di-rollback
+func (s *TemplateService) ensureBaselineRevision(template *database.PromoteTemplate) {
+	revisions, err := s.repository.GetTemplateRevisions(template.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to check revisions of template %d: %v", template.ID, err)
+		return
+	}
+	if len(revisions) > 0 {
+		return
+	}
+
+	baseline := revisionFromTemplate(template, database.RevisionBaseline, "")
+	baseline.CreatedAt = template.UpdatedAt
+	if err := s.repository.CreateTemplateRevision(baseline); err != nil {
+		s.logger.Errorf("Failed to save baseline revision of template %d: %v", template.ID, err)
+	}
+}
+
+// revisionFromTemplate membuat salinan revisi dari template
+func revisionFromTemplate(template *database.PromoteTemplate, action, author string) *database.TemplateRevision {
+	return &database.TemplateRevision{
+		TemplateID:    template.ID,
+		Title:         template.Title,
+		Content:       template.Content,
+		Category:      template.Category,
+		IsActive:      template.IsActive,
+		Weight:        template.Weight,
+		MediaType:     template.MediaType,
+		MediaPath:     template.MediaPath,
+		MediaMimetype: template.MediaMimetype,
+		MediaName:     template.MediaName,
+		ValidFrom:     template.ValidFrom,
+		ValidUntil:    template.ValidUntil,
+		ValidDays:     template.ValidDays,
+		ValidHours:    template.ValidHours,
+		Action:        action,
+		Author:        author,
+	}
+}
+
+// splitLines memecah konten per baris (konten kosong = tanpa baris)
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+
+	return strings.Split(content, "\n")
+}
+
+// diffLines membandingkan dua daftar baris dengan longest common subsequence
+func diffLines(a, b []string) []DiffLine {
+	// lcs[i][j] = panjang LCS dari a[i:] dan b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: ' ', Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: '-', Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: '+', Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: '-', Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: '+', Text: b[j]})
+	}
+
+	return lines
+}
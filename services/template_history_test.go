@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string // Satu baris per DiffLine: op diikuti teks
+	}{
+		{"sama", "a\nb", "a\nb", " a\n b"},
+		{"dari kosong", "", "a\nb", "+a\n+b"},
+		{"menjadi kosong", "a\nb", "", "-a\n-b"},
+		{"keduanya kosong", "", "", ""},
+		{"ganti baris tengah", "a\nb\nc", "a\nx\nc", " a\n-b\n+x\n c"},
+		{"sisip di awal", "b\nc", "a\nb\nc", "+a\n b\n c"},
+		{"hapus di akhir", "a\nb\nc", "a\nb", " a\n b\n-c"},
+		{"pindah baris", "a\nb\nc", "b\nc\na", "-a\n b\n c\n+a"},
+		{"baris ganda", "x\nx\ny", "x\ny\ny", " x\n-x\n y\n+y"},
+		{"baris kosong ikut dibandingkan", "a\n\nb", "a\nb", " a\n-\n b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, line := range diffLines(splitLines(tt.old), splitLines(tt.new)) {
+				got = append(got, string(line.Op)+line.Text)
+			}
+			if strings.Join(got, "\n") != tt.want {
+				t.Errorf("diffLines = %q, want %q", strings.Join(got, "\n"), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,429 @@
+// Package services - Linter format WhatsApp untuk konten template
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nabilulilalbab/promote/render"
+)
+
+// maxLintLineLength adalah panjang baris (karakter) sebelum dianggap terlalu panjang dibaca di HP
+const maxLintLineLength = 200
+
+// LintIssue adalah satu temuan linter. Line 0 berarti temuan untuk seluruh konten.
+type LintIssue struct {
+	Source  string // Varian atau terjemahan asal temuan (kosong = konten asli template)
+	Line    int
+	Message string
+}
+
+// String memformat temuan beserta asal dan nomor barisnya
+func (i LintIssue) String() string {
+	text := i.Message
+	if i.Line > 0 {
+		text = fmt.Sprintf("Baris %d: %s", i.Line, i.Message)
+	}
+	if i.Source != "" {
+		text = fmt.Sprintf("[%s] %s", i.Source, text)
+	}
+	return text
+}
+
+// TemplateLintResult adalah hasil lint satu template tersimpan
+type TemplateLintResult struct {
+	TemplateID int
+	Title      string
+	IsActive   bool
+	Issues     []LintIssue
+}
+
+var (
+	// lintBraceToken menangkap {...} dalam satu baris tanpa kurung kurawal di dalamnya
+	lintBraceToken = regexp.MustCompile(`\{([^{}\n]*)\}`)
+
+	// lintShortLink menangkap link wa.me dan t.me (dengan atau tanpa http/https)
+	lintShortLink = regexp.MustCompile(`(?i)(^|[^\w.@/])((?:https?://)?(?:www\.)?(wa|t)\.me(/[^\s]*)?)`)
+
+	// lintURL menangkap URL lain yang tidak boleh ikut dihitung sebagai tanda format
+	lintURL = regexp.MustCompile(`(?i)https?://\S+`)
+
+	// lintPlaceholderLike menangkap isi kurung kurawal yang tampak seperti placeholder
+	lintPlaceholderLike = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_ \-]*$`)
+
+	waMePath = regexp.MustCompile(`^(\d{8,15}|c/\d{8,15}|message/[A-Za-z0-9]+|qr/[A-Za-z0-9]+)$`)
+	tMePath  = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9_]{4,31}(/\d+)?|\+[A-Za-z0-9_\-]+|joinchat/[A-Za-z0-9_\-]+|addlist/[A-Za-z0-9_\-]+)$`)
+)
+
+// LintTemplate memeriksa format WhatsApp konten template dengan variabel yang tersedia sekarang
+func (s *TemplateService) LintTemplate(content string) []LintIssue {
+	known, err := s.renderer.KnownVariableNames()
+	if err != nil {
+		s.logger.Warningf("Failed to load variables for lint: %v", err)
+	}
+	return LintContent(content, known)
+}
+
+// LintAllTemplates menjalankan linter untuk semua template tersimpan beserta varian
+// aktif dan terjemahannya (temuannya diberi label varian atau bahasa).
+// Hanya template yang punya temuan yang dikembalikan, beserta jumlah template yang diperiksa.
+func (s *TemplateService) LintAllTemplates() ([]TemplateLintResult, int, error) {
+	templates, err := s.repository.GetAllTemplates()
+	if err != nil {
+		s.logger.Errorf("Failed to get templates for lint: %v", err)
+		return nil, 0, fmt.Errorf("gagal mengambil template: %v", err)
+	}
+
+	known, err := s.renderer.KnownVariableNames()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var results []TemplateLintResult
+	for _, template := range templates {
+		issues := LintContent(template.Content, known)
+
+		variants, err := s.repository.GetTemplateVariants(template.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gagal memuat varian template %d: %v", template.ID, err)
+		}
+		for _, variant := range variants {
+			if variant.IsActive {
+				issues = append(issues, labelLintIssues(LintContent(variant.Content, known), "Varian "+variant.Label)...)
+			}
+		}
+
+		translations, err := s.repository.GetTemplateTranslations(template.ID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("gagal memuat terjemahan template %d: %v", template.ID, err)
+		}
+		for _, translation := range translations {
+			issues = append(issues, labelLintIssues(LintContent(translation.Content, known), "Terjemahan "+translation.Locale)...)
+		}
+
+		if len(issues) == 0 {
+			continue
+		}
+		results = append(results, TemplateLintResult{
+			TemplateID: template.ID,
+			Title:      template.Title,
+			IsActive:   template.IsActive,
+			Issues:     issues,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].TemplateID < results[j].TemplateID
+	})
+	return results, len(templates), nil
+}
+
+// labelLintIssues menandai temuan dengan asal kontennya
+func labelLintIssues(issues []LintIssue, source string) []LintIssue {
+	for i := range issues {
+		issues[i].Source = source
+	}
+	return issues
+}
+
+// KnownVariableNames mengembalikan nama variabel yang bisa dipakai template di semua grup
+// (bawaan dan variabel global) dengan nilai true. Variabel yang hanya diatur di sebagian
+// grup tercantum dengan nilai false karena grup lain tidak punya nilainya.
+func (r *TemplateRenderer) KnownVariableNames() (map[string]bool, error) {
+	known := make(map[string]bool)
+	for _, name := range r.BuiltinVariableNames() {
+		known[name] = true
+	}
+
+	variables, err := r.repository.GetTemplateVariables()
+	if err != nil {
+		return known, fmt.Errorf("gagal memuat variabel global: %v", err)
+	}
+	for _, variable := range variables {
+		known[variable.Name] = true
+	}
+
+	attributes, err := r.repository.GetAllGroupAttributes()
+	if err != nil {
+		return known, fmt.Errorf("gagal memuat variabel grup: %v", err)
+	}
+	for _, attr := range attributes {
+		if _, ok := known[attr.Name]; !ok {
+			known[attr.Name] = false
+		}
+	}
+
+	return known, nil
+}
+
+// LintContent memeriksa tanda format WhatsApp (*, _, ~, ```), placeholder,
+// link wa.me/t.me, karakter tak terlihat dan baris yang terlalu panjang.
+// known berisi nama variabel yang tersedia (false = hanya di sebagian grup);
+// nil berarti variabel tidak diperiksa.
+func LintContent(content string, known map[string]bool) []LintIssue {
+	var issues []LintIssue
+	lines := strings.Split(content, "\n")
+
+	issues = append(issues, lintPlaceholders(lines, known)...)
+
+	inCodeBlock := false
+	codeBlockLine := 0
+	for i, line := range lines {
+		lineNo := i + 1
+
+		if chars := invisibleChars(line); len(chars) > 0 {
+			issues = append(issues, LintIssue{Line: lineNo, Message: fmt.Sprintf("ada karakter tak terlihat (%s), hapus agar teks tidak rusak", strings.Join(chars, ", "))})
+		}
+
+		if length := utf8.RuneCountInString(line); length > maxLintLineLength {
+			issues = append(issues, LintIssue{Line: lineNo, Message: fmt.Sprintf("baris terlalu panjang (%d karakter, maksimal %d), pecah agar mudah dibaca di HP", length, maxLintLineLength)})
+		}
+
+		for _, match := range lintShortLink.FindAllStringSubmatch(line, -1) {
+			if problem := checkShortLink(match[2], strings.ToLower(match[3]), match[4]); problem != "" {
+				issues = append(issues, LintIssue{Line: lineNo, Message: problem})
+			}
+		}
+
+		// Teks di dalam blok ``` tidak diformat WhatsApp
+		fences := strings.Count(line, "```")
+		if inCodeBlock || fences > 0 {
+			if fences%2 == 1 {
+				inCodeBlock = !inCodeBlock
+				codeBlockLine = lineNo
+			}
+			continue
+		}
+
+		for _, marker := range unbalancedMarkers(line) {
+			issues = append(issues, LintIssue{Line: lineNo, Message: fmt.Sprintf("tanda %s tidak berpasangan, format %s tidak akan tampil", marker, markerExample(marker))})
+		}
+	}
+
+	if inCodeBlock {
+		issues = append(issues, LintIssue{Line: codeBlockLine, Message: "``` dibuka tapi tidak ditutup"})
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return issues
+}
+
+// lintPlaceholders memeriksa {VARIABEL} yang tidak dikenal dan kurung kurawal
+// yang tampak seperti placeholder tapi salah tulis (huruf kecil, spasi, tanda hubung)
+func lintPlaceholders(lines []string, known map[string]bool) []LintIssue {
+	var issues []LintIssue
+	var blocks []string // Tumpukan blok IF/EACH yang sedang terbuka
+
+	for i, line := range lines {
+		for _, match := range lintBraceToken.FindAllStringSubmatch(line, -1) {
+			inner := match[1]
+
+			switch {
+			case strings.Contains(inner, "|") || strings.HasPrefix(inner, "?"):
+				continue // Spintax
+			case inner == "END":
+				if len(blocks) > 0 {
+					blocks = blocks[:len(blocks)-1]
+				}
+				continue
+			case inner == "ELSE":
+				continue
+			case strings.HasPrefix(inner, "IF "):
+				blocks = append(blocks, "IF")
+				continue
+			case strings.HasPrefix(inner, "EACH "):
+				blocks = append(blocks, "EACH")
+				continue
+			}
+
+			name, hasDefault := inner, false
+			if idx := strings.IndexByte(inner, ':'); idx >= 0 {
+				name, hasDefault = inner[:idx], true
+			}
+
+			if render.ValidName(name) {
+				// Field item di dalam {EACH} tidak bisa diketahui dari daftar variabel
+				if known == nil || known[name] || inEachBlock(blocks) {
+					continue
+				}
+				if _, perGroup := known[name]; perGroup {
+					if !hasDefault {
+						issues = append(issues, LintIssue{Line: i + 1, Message: fmt.Sprintf("variabel {%s} hanya diatur di sebagian grup, grup lain gagal dikirim; beri cadangan dengan .setvar atau pakai {%s:default}", name, name)})
+					}
+					continue
+				}
+				if hasDefault {
+					issues = append(issues, LintIssue{Line: i + 1, Message: fmt.Sprintf("{%s} belum didefinisikan, selalu memakai nilai default", name)})
+				} else {
+					issues = append(issues, LintIssue{Line: i + 1, Message: fmt.Sprintf("variabel {%s} tidak dikenal, buat dengan .setvar", name)})
+				}
+				continue
+			}
+
+			if lintPlaceholderLike.MatchString(name) {
+				suggestion := strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.TrimSpace(name)))
+				issues = append(issues, LintIssue{Line: i + 1, Message: fmt.Sprintf("{%s} bukan placeholder yang valid (nama variabel harus huruf besar), mungkin maksudnya {%s}", inner, suggestion)})
+			}
+		}
+	}
+
+	return issues
+}
+
+// inEachBlock mengecek apakah ada blok {EACH} yang sedang terbuka
+func inEachBlock(blocks []string) bool {
+	for _, block := range blocks {
+		if block == "EACH" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkShortLink memeriksa satu link wa.me atau t.me, mengembalikan "" jika valid
+func checkShortLink(link, host, path string) string {
+	path = strings.TrimPrefix(path, "/")
+	if idx := strings.IndexByte(path, '?'); idx >= 0 {
+		path = path[:idx]
+	}
+	path = strings.TrimRight(path, ".,;:!)]*_~'\"")
+
+	// Nomor/username dari variabel baru diketahui saat dikirim
+	if strings.Contains(path, "{") {
+		return ""
+	}
+
+	switch host {
+	case "wa":
+		switch {
+		case path == "":
+			return fmt.Sprintf("link %s tanpa nomor tujuan", link)
+		case strings.HasPrefix(path, "+"):
+			return fmt.Sprintf("link %s: hapus tanda +, contoh wa.me/6281234567890", link)
+		case strings.HasPrefix(path, "0"):
+			return fmt.Sprintf("link %s: nomor harus format internasional (62...), bukan 0...", link)
+		case !waMePath.MatchString(path):
+			return fmt.Sprintf("link %s tidak valid, gunakan wa.me/628xxx tanpa spasi atau tanda hubung", link)
+		}
+	case "t":
+		switch {
+		case path == "":
+			return fmt.Sprintf("link %s tanpa username", link)
+		case !tMePath.MatchString(path):
+			return fmt.Sprintf("link %s tidak valid, username Telegram 5-32 karakter huruf/angka/underscore", link)
+		}
+	}
+	return ""
+}
+
+// unbalancedMarkers mengembalikan tanda format (*, _, ~) yang jumlahnya ganjil di satu baris.
+// Placeholder, link, `kode` dan tanda yang berdiri sendiri (misal bullet "* ") tidak dihitung.
+func unbalancedMarkers(line string) []string {
+	line = lintBraceToken.ReplaceAllString(line, " ")
+	line = lintURL.ReplaceAllString(line, " ")
+	line = lintShortLink.ReplaceAllString(line, "$1 ")
+	line = stripInlineCode(line)
+
+	// Baris hiasan seperti "*****" atau "~~~~~" tidak dianggap format
+	if strings.Trim(line, "*_~ \t") == "" {
+		return nil
+	}
+
+	var unbalanced []string
+	runes := []rune(line)
+	for _, marker := range []rune{'*', '_', '~'} {
+		count := 0
+		for i, r := range runes {
+			if r != marker {
+				continue
+			}
+			prevSpace := i == 0 || isLintSpace(runes[i-1])
+			nextSpace := i == len(runes)-1 || isLintSpace(runes[i+1])
+			if prevSpace && nextSpace {
+				continue
+			}
+			// nama_produk di tengah kata tidak diformat miring oleh WhatsApp
+			if marker == '_' && i > 0 && i < len(runes)-1 && isLintWordChar(runes[i-1]) && isLintWordChar(runes[i+1]) {
+				continue
+			}
+			count++
+		}
+		if count%2 == 1 {
+			unbalanced = append(unbalanced, string(marker))
+		}
+	}
+	return unbalanced
+}
+
+// stripInlineCode membuang teks di antara backtick tunggal
+func stripInlineCode(line string) string {
+	parts := strings.Split(line, "`")
+	if len(parts) < 3 {
+		return line
+	}
+
+	var result strings.Builder
+	for i, part := range parts {
+		if i%2 == 0 || i == len(parts)-1 {
+			result.WriteString(part)
+		}
+	}
+	return result.String()
+}
+
+// markerExample menampilkan contoh format untuk tanda tertentu
+func markerExample(marker string) string {
+	switch marker {
+	case "*":
+		return "*tebal*"
+	case "_":
+		return "_miring_"
+	default:
+		return "~coret~"
+	}
+}
+
+// invisibleChars mencari karakter kontrol dan karakter tak terlihat di satu baris.
+// Zero width joiner dan variation selector tidak dihitung karena dipakai emoji.
+func invisibleChars(line string) []string {
+	var found []string
+	seen := make(map[rune]bool)
+	for _, r := range line {
+		if !isInvisibleChar(r) || seen[r] {
+			continue
+		}
+		seen[r] = true
+		found = append(found, fmt.Sprintf("U+%04X", r))
+	}
+	return found
+}
+
+// isInvisibleChar mengecek karakter kontrol, zero width, soft hyphen, BOM dan pengatur arah teks
+func isInvisibleChar(r rune) bool {
+	switch {
+	case r == '\t':
+		return false
+	case r < 0x20, r == 0x7F, r >= 0x80 && r <= 0x9F:
+		return true
+	case r == 0x00AD, r == 0x200B, r == 0x200C, r == 0x200E, r == 0x200F, r == 0xFEFF:
+		return true
+	case r >= 0x202A && r <= 0x202E, r >= 0x2060 && r <= 0x2064, r >= 0x2066 && r <= 0x2069:
+		return true
+	}
+	return false
+}
+
+// isLintSpace mengecek spasi atau tab
+func isLintSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// isLintWordChar mengecek huruf atau angka ASCII
+func isLintWordChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintContent(t *testing.T) {
+	known := map[string]bool{
+		"STORE_WA": true,
+		"DATE":     true,
+		"CITY":     false, // Hanya diatur di sebagian grup
+	}
+
+	type want struct {
+		line int
+		text string
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		known   map[string]bool
+		want    []want
+	}{
+		{"format lengkap", "*Promo* _hemat_ ~mahal~ {DATE}", known, nil},
+		{"tebal tidak ditutup", "*Promo hari ini", known, []want{{1, "tanda * tidak berpasangan"}}},
+		{"miring dan coret tidak ditutup", "ok\n_miring ~coret", known, []want{{2, "tanda _"}, {2, "tanda ~"}}},
+		{"bullet bintang", "* item satu\n* item dua", known, nil},
+		{"garis hiasan", "*****\n~~~~~", known, nil},
+		{"underscore di tengah kata", "kode nama_produk_baru", known, nil},
+		{"inline code", "ketik `a*b` ya", known, nil},
+		{"isi blok kode diabaikan", "```\n*x _y\n```", known, nil},
+		{"blok kode tidak ditutup", "halo\n```\n*x", known, []want{{2, "``` dibuka tapi tidak ditutup"}}},
+		{"url dengan tanda format", "https://toko.id/a_b*c", known, nil},
+		{"placeholder huruf kecil", "WA {store_wa}", known, []want{{1, "mungkin maksudnya {STORE_WA}"}}},
+		{"placeholder dengan spasi", "{store wa}", known, []want{{1, "mungkin maksudnya {STORE_WA}"}}},
+		{"variabel tidak dikenal", "{PROMO_CODE}", known, []want{{1, "variabel {PROMO_CODE} tidak dikenal"}}},
+		{"variabel tidak dikenal dengan default", "{PROMO_CODE:HEMAT}", known, []want{{1, "selalu memakai nilai default"}}},
+		{"variabel sebagian grup", "Kota {CITY}", known, []want{{1, "hanya diatur di sebagian grup"}}},
+		{"variabel sebagian grup dengan default", "Kota {CITY:Jakarta}", known, nil},
+		{"field each tidak diperiksa", "{EACH PRODUCTS}\n{NAME} {PRICE}\n{END}\n{IF PROMO}x{END}", known, nil},
+		{"field setelah each ditutup diperiksa", "{EACH PRODUCTS}{NAME}{END}\n{NAME}", known, []want{{2, "variabel {NAME} tidak dikenal"}}},
+		{"spintax diabaikan", "{Halo|hai} {?kak}", known, nil},
+		{"variabel tidak diperiksa tanpa daftar", "{PROMO_CODE}", nil, nil},
+		{"wa.me valid", "wa.me/6281234567890 dan https://wa.me/{STORE_WA}", known, nil},
+		{"wa.me dengan plus", "wa.me/+6281234567890", known, []want{{1, "hapus tanda +"}}},
+		{"wa.me dengan nol", "https://wa.me/081234567890", known, []want{{1, "format internasional"}}},
+		{"wa.me tanpa nomor", "chat wa.me", known, []want{{1, "tanpa nomor tujuan"}}},
+		{"wa.me dengan tanda hubung", "wa.me/62812-3456-7890", known, []want{{1, "tidak valid"}}},
+		{"t.me valid", "t.me/toko_kita dan t.me/+AbCdEf", known, nil},
+		{"t.me username pendek", "t.me/abc", known, []want{{1, "username Telegram"}}},
+		{"bukan link t.me", "chat.me/abc dan email a@t.me", known, nil},
+		{"zero width space", "Halo\u200bkak", known, []want{{1, "U+200B"}}},
+		{"bom dan karakter kontrol", "\ufeffHalo\x07", known, []want{{1, "U+FEFF, U+0007"}}},
+		{"emoji dengan zwj", "\U0001F468\u200d\U0001F469\u200d\U0001F467 keluarga \u2764\ufe0f", known, nil},
+		{"baris 200 karakter", strings.Repeat("a", 200), known, nil},
+		{"baris terlalu panjang", "ok\n" + strings.Repeat("é", 201), known, []want{{2, "baris terlalu panjang (201 karakter"}}},
+		{
+			"urut per baris",
+			"{PROMO_CODE}\n*tebal\nwa.me/+62812345678",
+			known,
+			[]want{{1, "tidak dikenal"}, {2, "tanda *"}, {3, "hapus tanda +"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := LintContent(tt.content, tt.known)
+			if len(issues) != len(tt.want) {
+				t.Fatalf("LintContent(%q) = %v, want %d temuan", tt.content, issues, len(tt.want))
+			}
+			for i, w := range tt.want {
+				if issues[i].Line != w.line || !strings.Contains(issues[i].Message, w.text) {
+					t.Errorf("temuan %d = %q, want baris %d mengandung %q", i, issues[i].String(), w.line, w.text)
+				}
+			}
+		})
+	}
+}
+
+func TestLintIssueString(t *testing.T) {
+	tests := []struct {
+		issue LintIssue
+		want  string
+	}{
+		{LintIssue{Message: "pesan"}, "pesan"},
+		{LintIssue{Line: 3, Message: "pesan"}, "Baris 3: pesan"},
+		{LintIssue{Source: "Varian B", Line: 2, Message: "pesan"}, "[Varian B] Baris 2: pesan"},
+		{LintIssue{Source: "Terjemahan en", Message: "pesan"}, "[Terjemahan en] pesan"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.issue.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
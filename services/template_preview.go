@@ -0,0 +1,100 @@
+// Package services - Preview template untuk grup tertentu, sama persis dengan yang dikirim
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/render"
+)
+
+// GroupPreview adalah hasil render template untuk satu grup
+type GroupPreview struct {
+	Template     *database.PromoteTemplate
+	GroupJID     string
+	Content      string   // Konten yang akan dikirim (variabel, blok dan spintax sudah dirender)
+	Variant      string   // Label varian A/B yang terpilih ("A" = konten asli)
+	Locale       string   // Bahasa grup
+	Translated   bool     // true jika konten diambil dari terjemahan
+	HasSpintax   bool     // true jika kiriman lain bisa berbeda karena spintax
+	MediaMissing bool     // true jika template punya media tapi filenya tidak tersedia
+	Notes        []string // Alasan template tidak ikut rotasi grup saat ini
+}
+
+// PreviewForGroup merender template persis seperti saat dikirim ke grup:
+// terjemahan bahasa grup, pemilihan varian A/B, variabel grup dan spintax.
+// now harus sudah dalam timezone scheduler.
+func (s *TemplateService) PreviewForGroup(templateID int, groupJID string, now time.Time) (*GroupPreview, error) {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	content, variantID, err := s.renderer.RenderTemplate(template, groupJID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &GroupPreview{
+		Template: template,
+		GroupJID: groupJID,
+		Content:  content,
+		Variant:  originalVariantLabel,
+		Locale:   s.renderer.GroupLocale(groupJID),
+	}
+
+	translation, err := s.repository.GetTemplateTranslation(template.ID, preview.Locale)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat terjemahan template: %v", err)
+	}
+	source := template.Content
+	if translation != nil {
+		preview.Translated = true
+		source = translation.Content
+	}
+
+	if variantID > 0 {
+		variants, err := s.repository.GetTemplateVariants(template.ID)
+		if err != nil {
+			return nil, fmt.Errorf("gagal memuat varian template: %v", err)
+		}
+		for _, variant := range variants {
+			if variant.ID == variantID {
+				preview.Variant = variant.Label
+				source = variant.Content
+			}
+		}
+	}
+	preview.HasSpintax = render.HasSpintax(source)
+
+	if template.MediaType != "" && (s.media == nil || !s.media.Exists(template.MediaPath)) {
+		preview.MediaMissing = true
+	}
+
+	if !template.IsActive {
+		preview.Notes = append(preview.Notes, "Template nonaktif, tidak ikut rotasi")
+	} else if !TemplateValidAt(template, now) {
+		preview.Notes = append(preview.Notes, "Di luar masa berlaku, dilewati rotasi saat ini")
+	}
+
+	subscriptions, err := s.repository.GetGroupCategories(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat kategori grup: %v", err)
+	}
+	categories := categoryNames(subscriptions)
+	if len(filterTemplatesByCategories([]database.PromoteTemplate{*template}, categories)) == 0 {
+		preview.Notes = append(preview.Notes, fmt.Sprintf("Kategori '%s' tidak diikuti grup ini", template.Category))
+	}
+
+	return preview, nil
+}
+
+// BuildPreviewMessage membuat pesan WhatsApp dari preview, sama dengan pesan di antrian kirim
+func (s *TemplateService) BuildPreviewMessage(client *whatsmeow.Client, preview *GroupPreview) (*waProto.Message, error) {
+	return buildTemplateMessage(context.Background(), client, s.media, preview.Template, preview.Content, s.logger)
+}
@@ -0,0 +1,488 @@
+// Package services - Renderer template yang dipakai bersama oleh pengiriman, preview, dan test
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/render"
+	"github.com/nabilulilalbab/promote/utils"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// previewGroupID adalah contoh ID grup untuk preview dan validasi template
+const previewGroupID = "120363000000000000"
+
+// maxVariableValueLength adalah panjang maksimal nilai variabel global
+const maxVariableValueLength = 1000
+
+// loopVariableNames adalah variabel yang otomatis tersedia di dalam {EACH}
+var loopVariableNames = []string{"INDEX", "LAST"}
+
+// groupInfoVariableNames adalah variabel bawaan yang diambil dari info grup WhatsApp
+var groupInfoVariableNames = []string{"GROUP_NAME", "MEMBER_COUNT", "GROUP_DESC"}
+
+// TemplateRenderer menyiapkan variabel dan merender konten template.
+// Semua jalur (auto promote, campaign, jadwal, test, preview) memakai renderer
+// yang sama agar variabel yang tersedia selalu konsisten.
+//
+// Urutan prioritas variabel: bawaan > variabel grup (.setgroupvar) > variabel global (.setvar).
+type TemplateRenderer struct {
+	repository database.Repository
+	groups     *GroupInfoCache
+	locale     string // Bahasa default untuk grup tanpa .setlocale
+	logger     *utils.Logger
+}
+
+// NewTemplateRenderer membuat renderer template baru
+func NewTemplateRenderer(repo database.Repository, groups *GroupInfoCache, logger *utils.Logger) *TemplateRenderer {
+	return &TemplateRenderer{
+		repository: repo,
+		groups:     groups,
+		locale:     render.DefaultLocale,
+		logger:     logger,
+	}
+}
+
+// SetDefaultLocale mengatur bahasa default untuk grup yang belum diatur dengan .setlocale
+func (r *TemplateRenderer) SetDefaultLocale(value string) error {
+	locale, err := render.ParseLocale(value)
+	if err != nil {
+		return err
+	}
+	r.locale = locale
+	return nil
+}
+
+// DefaultLocale mengembalikan bahasa default renderer
+func (r *TemplateRenderer) DefaultLocale() string {
+	return r.locale
+}
+
+// GroupLocale mengembalikan bahasa grup, atau bahasa default jika belum diatur
+func (r *TemplateRenderer) GroupLocale(groupJID string) string {
+	group, err := r.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		r.logger.Warningf("Failed to get locale of group %s, using default: %v", groupJID, err)
+		return r.locale
+	}
+	if group == nil || group.Locale == "" {
+		return r.locale
+	}
+	return group.Locale
+}
+
+// globalData menyiapkan Data berisi variabel global.
+// Variabel dibaca langsung dari database agar perubahan .setvar
+// langsung berlaku di semua instance tanpa restart.
+func (r *TemplateRenderer) globalData() (*render.Data, error) {
+	data := render.NewData()
+
+	variables, err := r.repository.GetTemplateVariables()
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat variabel global: %v", err)
+	}
+	for _, variable := range variables {
+		data.Set(variable.Name, variable.Value)
+	}
+	return data, nil
+}
+
+// setBuiltins mengisi variabel bawaan yang tidak bisa ditimpa .setvar.
+// {DATE}, {DAY} dan {MONTH} mengikuti bahasa locale.
+func (r *TemplateRenderer) setBuiltins(data *render.Data, groupJID string, now time.Time, locale string) {
+	groupID := groupJID
+	if jid, err := types.ParseJID(groupJID); err == nil {
+		groupID = jid.User
+	}
+
+	standard := render.StandardData(now, locale)
+	for _, name := range standard.Names() {
+		value, _ := standard.Get(name)
+		data.Set(name, value)
+	}
+	data.Set("GROUP_ID", groupID)
+}
+
+// setGroupInfo mengisi {GROUP_NAME}, {MEMBER_COUNT}, dan {GROUP_DESC}
+func setGroupInfo(data *render.Data, name string, memberCount int, description string) {
+	data.Set("GROUP_NAME", name)
+	data.Set("MEMBER_COUNT", strconv.Itoa(memberCount))
+	data.Set("GROUP_DESC", description)
+}
+
+// usesGroupInfo mengecek apakah template memakai variabel dari info grup,
+// agar info grup hanya diambil dari WhatsApp jika memang dibutuhkan
+func usesGroupInfo(tmpl *render.Template) bool {
+	for _, name := range tmpl.Variables() {
+		for _, groupName := range groupInfoVariableNames {
+			if name == groupName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Render merender konten template untuk grup tujuan dalam bahasa grup tersebut.
+// Spintax dipilih acak setiap kali dipanggil, sehingga tiap grup mendapat variasi sendiri.
+func (r *TemplateRenderer) Render(content, groupJID string, now time.Time) (string, error) {
+	return r.renderLocale(content, groupJID, r.GroupLocale(groupJID), now)
+}
+
+// renderLocale merender konten untuk grup dengan bahasa yang sudah ditentukan
+func (r *TemplateRenderer) renderLocale(content, groupJID, locale string, now time.Time) (string, error) {
+	tmpl, err := render.Parse(render.Spin(content, rand.Intn))
+	if err != nil {
+		return "", fmt.Errorf("template tidak valid: %v", err)
+	}
+
+	data, err := r.globalData()
+	if err != nil {
+		return "", err
+	}
+
+	attributes, err := r.repository.GetGroupAttributes(groupJID)
+	if err != nil {
+		return "", fmt.Errorf("gagal memuat variabel grup: %v", err)
+	}
+	for _, attr := range attributes {
+		data.Set(attr.Name, attr.Value)
+	}
+
+	r.setBuiltins(data, groupJID, now, locale)
+
+	if usesGroupInfo(tmpl) {
+		info, err := r.groups.Get(groupJID)
+		if err != nil {
+			return "", fmt.Errorf("gagal mengambil info grup untuk {GROUP_NAME}/{MEMBER_COUNT}/{GROUP_DESC}: %v", err)
+		}
+		setGroupInfo(data, info.Name, len(info.Participants), info.Topic)
+	}
+
+	result, err := tmpl.Execute(data)
+	if err != nil {
+		return "", fmt.Errorf("template tidak valid: %v", err)
+	}
+	return result, nil
+}
+
+// Preview merender konten dengan contoh grup, dipakai untuk preview dan validasi.
+// Hanya variabel bawaan dan global yang dianggap tersedia: variabel grup tanpa nilai
+// global akan gagal di grup lain, jadi harus punya cadangan .setvar atau {VAR:default}.
+func (r *TemplateRenderer) Preview(content string) (string, error) {
+	return r.preview(render.Spin(content, rand.Intn))
+}
+
+// PreviewSamples merender beberapa contoh variasi spintax.
+// Variasi yang sama persis hanya ditampilkan sekali.
+func (r *TemplateRenderer) PreviewSamples(content string, count int) ([]string, error) {
+	var samples []string
+	seen := make(map[string]bool)
+
+	// Beberapa percobaan tambahan agar variasi yang sedikit tetap bisa terkumpul
+	for attempt := 0; attempt < count*3 && len(samples) < count; attempt++ {
+		sample, err := r.Preview(content)
+		if err != nil {
+			return nil, err
+		}
+		if seen[sample] {
+			continue
+		}
+		seen[sample] = true
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// Validate memastikan template bisa dirender di semua variasi spintax:
+// blok lengkap, opsi berdiri sendiri, dan semua variabel dikenal
+func (r *TemplateRenderer) Validate(content string) error {
+	if err := render.CheckSpintax(content); err != nil {
+		return fmt.Errorf("template tidak valid: %v", err)
+	}
+	_, err := r.preview(render.SpinAll(content))
+	return err
+}
+
+// preview merender konten yang spintax-nya sudah diekspansi dengan data contoh
+func (r *TemplateRenderer) preview(content string) (string, error) {
+	data, err := r.globalData()
+	if err != nil {
+		return "", err
+	}
+
+	r.setBuiltins(data, previewGroupID+"@g.us", time.Now(), r.locale)
+	setGroupInfo(data, "Contoh Grup", 100, "Deskripsi grup")
+
+	result, err := render.Render(content, data)
+	if err != nil {
+		if names := r.groupOnlyVariables(content, data); len(names) > 0 {
+			return "", fmt.Errorf("template tidak valid: %v (variabel {%s} hanya diatur di sebagian grup, beri nilai cadangan dengan .setvar atau pakai {%s:default})",
+				err, strings.Join(names, "}, {"), names[0])
+		}
+		return "", fmt.Errorf("template tidak valid: %v", err)
+	}
+	return result, nil
+}
+
+// groupOnlyVariables mengembalikan variabel yang dipakai konten dan hanya diatur
+// sebagai variabel grup (tidak ada di data global)
+func (r *TemplateRenderer) groupOnlyVariables(content string, data *render.Data) []string {
+	tmpl, err := render.Parse(content)
+	if err != nil {
+		return nil
+	}
+
+	attributes, err := r.repository.GetAllGroupAttributes()
+	if err != nil {
+		return nil
+	}
+	perGroup := make(map[string]bool)
+	for _, attr := range attributes {
+		perGroup[attr.Name] = true
+	}
+
+	var names []string
+	for _, name := range tmpl.Variables() {
+		if _, ok := data.Get(name); !ok && perGroup[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// CacheGroupInfo menyimpan info grup yang baru diambil agar render berikutnya tidak perlu memanggil WhatsApp
+func (r *TemplateRenderer) CacheGroupInfo(info *types.GroupInfo) {
+	r.groups.Store(info)
+}
+
+// BuiltinVariableNames mengembalikan nama variabel bawaan (tidak bisa diubah dengan .setvar)
+func (r *TemplateRenderer) BuiltinVariableNames() []string {
+	data := render.NewData()
+	r.setBuiltins(data, previewGroupID+"@g.us", time.Now(), r.locale)
+	names := append(data.Names(), groupInfoVariableNames...)
+	return append(names, loopVariableNames...)
+}
+
+// GetVariables mengembalikan semua variabel global
+func (r *TemplateRenderer) GetVariables() ([]database.TemplateVariable, error) {
+	return r.repository.GetTemplateVariables()
+}
+
+// SetVariable membuat atau mengubah variabel global. Nama otomatis diubah ke huruf besar.
+func (r *TemplateRenderer) SetVariable(name, value, updatedBy string) (string, error) {
+	name, value, err := r.validateVariable(name, value)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.repository.SetTemplateVariable(name, value, updatedBy); err != nil {
+		r.logger.Errorf("Failed to save template variable %s: %v", name, err)
+		return "", fmt.Errorf("gagal menyimpan variabel: %v", err)
+	}
+
+	r.logger.Successf("Template variable {%s} updated by %s", name, updatedBy)
+	return name, nil
+}
+
+// EnsureVariable membuat variabel global jika belum ada, tanpa menimpa nilai dari admin
+func (r *TemplateRenderer) EnsureVariable(name, value, updatedBy string) (bool, error) {
+	variables, err := r.repository.GetTemplateVariables()
+	if err != nil {
+		return false, err
+	}
+	for _, variable := range variables {
+		if variable.Name == strings.ToUpper(name) {
+			return false, nil
+		}
+	}
+
+	if _, err := r.SetVariable(name, value, updatedBy); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// validateVariable menormalkan dan memvalidasi nama serta nilai variabel
+func (r *TemplateRenderer) validateVariable(name, value string) (string, string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+
+	if !render.ValidName(name) {
+		return "", "", fmt.Errorf("nama variabel harus diawali huruf dan hanya berisi huruf, angka, atau underscore")
+	}
+	for _, builtin := range r.BuiltinVariableNames() {
+		if name == builtin {
+			return "", "", fmt.Errorf("{%s} adalah variabel bawaan dan tidak bisa diubah", name)
+		}
+	}
+	if value == "" {
+		return "", "", fmt.Errorf("nilai variabel tidak boleh kosong")
+	}
+	if len(value) > maxVariableValueLength {
+		return "", "", fmt.Errorf("nilai variabel maksimal %d karakter", maxVariableValueLength)
+	}
+
+	return name, value, nil
+}
+
+// DeleteVariable menghapus variabel global.
+// Variabel yang masih dipakai template ditolak agar pengiriman tidak gagal.
+func (r *TemplateRenderer) DeleteVariable(name string) (string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	using, err := r.TemplatesUsingVariable(name)
+	if err != nil {
+		return "", fmt.Errorf("gagal memeriksa pemakaian variabel: %v", err)
+	}
+	if len(using) > 0 {
+		ids := make([]string, 0, len(using))
+		for _, template := range using {
+			ids = append(ids, fmt.Sprintf("#%d", template.ID))
+		}
+		return "", fmt.Errorf("variabel {%s} masih dipakai template %s, edit konten, varian atau terjemahan template tersebut terlebih dahulu",
+			name, strings.Join(ids, ", "))
+	}
+
+	deleted, err := r.repository.DeleteTemplateVariable(name)
+	if err != nil {
+		r.logger.Errorf("Failed to delete template variable %s: %v", name, err)
+		return "", fmt.Errorf("gagal menghapus variabel: %v", err)
+	}
+	if !deleted {
+		return "", fmt.Errorf("variabel {%s} tidak ditemukan", name)
+	}
+
+	r.logger.Infof("Template variable {%s} deleted", name)
+	return name, nil
+}
+
+// TemplatesUsingVariable mengembalikan template yang memakai variabel tertentu,
+// baik di konten asli, varian A/B maupun terjemahannya
+func (r *TemplateRenderer) TemplatesUsingVariable(name string) ([]database.PromoteTemplate, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	templates, err := r.repository.GetAllTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var using []database.PromoteTemplate
+	for _, template := range templates {
+		contents, err := r.templateContents(&template)
+		if err != nil {
+			return nil, err
+		}
+		if contentsUseVariable(contents, name) {
+			using = append(using, template)
+		}
+	}
+	return using, nil
+}
+
+// templateContents mengumpulkan semua konten yang bisa dikirim dari satu template:
+// konten asli, varian A/B (termasuk yang nonaktif karena bisa diaktifkan lagi) dan terjemahan
+func (r *TemplateRenderer) templateContents(template *database.PromoteTemplate) ([]string, error) {
+	contents := []string{template.Content}
+
+	variants, err := r.repository.GetTemplateVariants(template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat varian template %d: %v", template.ID, err)
+	}
+	for _, variant := range variants {
+		contents = append(contents, variant.Content)
+	}
+
+	translations, err := r.repository.GetTemplateTranslations(template.ID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat terjemahan template %d: %v", template.ID, err)
+	}
+	for _, translation := range translations {
+		contents = append(contents, translation.Content)
+	}
+
+	return contents, nil
+}
+
+// contentsUseVariable mengecek apakah salah satu konten memakai variabel name
+func contentsUseVariable(contents []string, name string) bool {
+	for _, content := range contents {
+		tmpl, err := render.Parse(content)
+		if err != nil {
+			continue
+		}
+		for _, used := range tmpl.Variables() {
+			if used == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetGroupVariables mengembalikan variabel khusus satu grup
+func (r *TemplateRenderer) GetGroupVariables(groupJID string) ([]database.GroupAttribute, error) {
+	return r.repository.GetGroupAttributes(groupJID)
+}
+
+// SetGroupVariable membuat atau mengubah variabel khusus satu grup
+func (r *TemplateRenderer) SetGroupVariable(groupJID, name, value, updatedBy string) (string, error) {
+	name, value, err := r.validateVariable(name, value)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.repository.SetGroupAttribute(groupJID, name, value, updatedBy); err != nil {
+		r.logger.Errorf("Failed to save group variable %s for %s: %v", name, groupJID, err)
+		return "", fmt.Errorf("gagal menyimpan variabel grup: %v", err)
+	}
+
+	r.logger.Successf("Group variable {%s} for %s updated by %s", name, groupJID, updatedBy)
+	return name, nil
+}
+
+// DeleteGroupVariable menghapus variabel khusus satu grup.
+// Ditolak jika template masih memakainya dan tidak ada variabel global sebagai cadangan.
+func (r *TemplateRenderer) DeleteGroupVariable(groupJID, name string) (string, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	variables, err := r.repository.GetTemplateVariables()
+	if err != nil {
+		return "", fmt.Errorf("gagal memuat variabel global: %v", err)
+	}
+	hasGlobal := false
+	for _, variable := range variables {
+		if variable.Name == name {
+			hasGlobal = true
+			break
+		}
+	}
+
+	if !hasGlobal {
+		using, err := r.TemplatesUsingVariable(name)
+		if err != nil {
+			return "", fmt.Errorf("gagal memeriksa pemakaian variabel: %v", err)
+		}
+		if len(using) > 0 {
+			return "", fmt.Errorf("variabel {%s} masih dipakai %d template dan tidak ada nilai global sebagai cadangan, atur dulu dengan .setvar %s [nilai]",
+				name, len(using), name)
+		}
+	}
+
+	deleted, err := r.repository.DeleteGroupAttribute(groupJID, name)
+	if err != nil {
+		r.logger.Errorf("Failed to delete group variable %s for %s: %v", name, groupJID, err)
+		return "", fmt.Errorf("gagal menghapus variabel grup: %v", err)
+	}
+	if !deleted {
+		return "", fmt.Errorf("variabel {%s} tidak ditemukan di grup ini", name)
+	}
+
+	r.logger.Infof("Group variable {%s} for %s deleted", name, groupJID)
+	return name, nil
+}
@@ -0,0 +1,432 @@
+// Package services - Strategi pemilihan template promosi (global dan per grup)
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/utils"
+)
+
+// settingTemplateStrategy adalah key promote_settings untuk strategi global yang diatur admin
+const settingTemplateStrategy = "template_strategy"
+
+// defaultNoRepeatWindow adalah jumlah kiriman terakhir yang dihindari oleh strategi norepeat
+const defaultNoRepeatWindow = 3
+
+// TemplateHistory menyediakan riwayat kiriman template per grup untuk strategi pemilihan
+type TemplateHistory interface {
+	// RecentTemplateIDs mengembalikan template yang terakhir dikirim ke grup (terbaru dulu)
+	RecentTemplateIDs(groupJID string, limit int) ([]int, error)
+
+	// LastSentTimes mengembalikan waktu terakhir setiap template dikirim ke grup
+	LastSentTimes(groupJID string) (map[int]time.Time, error)
+}
+
+// TemplateStrategy memilih satu template dari pool untuk grup tertentu
+type TemplateStrategy interface {
+	// Select memilih template; templates dijamin tidak kosong
+	Select(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) (database.PromoteTemplate, error)
+
+	// String mengembalikan ekspresi strategi dalam format yang bisa diparse ulang
+	String() string
+}
+
+// ParseTemplateStrategy memparse nama strategi pemilihan template.
+// Format yang didukung:
+//   - "random" (weighted random berdasarkan bobot template)
+//   - "roundrobin" (bergiliran berdasarkan urutan ID per grup)
+//   - "leastrecent" (template yang paling lama tidak dikirim ke grup)
+//   - "norepeat" atau "norepeat:5" (acak, tapi tidak mengulang N kiriman terakhir)
+func ParseTemplateStrategy(expr string) (TemplateStrategy, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	switch expr {
+	case "", "random", "weighted":
+		return weightedRandomStrategy{}, nil
+	case "roundrobin", "round-robin":
+		return roundRobinStrategy{}, nil
+	case "leastrecent", "least-recent":
+		return leastRecentStrategy{}, nil
+	case "norepeat", "no-repeat":
+		return noRepeatStrategy{window: defaultNoRepeatWindow}, nil
+	}
+
+	if strings.HasPrefix(expr, "norepeat:") {
+		window, err := strconv.Atoi(strings.TrimPrefix(expr, "norepeat:"))
+		if err != nil || window < 1 || window > 50 {
+			return nil, fmt.Errorf("window norepeat harus angka 1-50: %s", expr)
+		}
+		return noRepeatStrategy{window: window}, nil
+	}
+
+	return nil, fmt.Errorf("strategi tidak dikenal: %s (gunakan random, roundrobin, leastrecent, atau norepeat:N)", expr)
+}
+
+// weightedRandomStrategy memilih template acak dengan peluang sebanding bobotnya
+type weightedRandomStrategy struct{}
+
+func (weightedRandomStrategy) Select(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) (database.PromoteTemplate, error) {
+	return pickWeighted(templates), nil
+}
+
+func (weightedRandomStrategy) String() string {
+	return "random"
+}
+
+// roundRobinStrategy mengirim template bergiliran (urut ID) per grup
+type roundRobinStrategy struct{}
+
+func (roundRobinStrategy) Select(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) (database.PromoteTemplate, error) {
+	sorted := sortedByID(templates)
+
+	recent, err := history.RecentTemplateIDs(groupJID, 1)
+	if err != nil {
+		return database.PromoteTemplate{}, err
+	}
+	if len(recent) == 0 {
+		return sorted[0], nil
+	}
+
+	// Template berikutnya setelah yang terakhir dikirim, kembali ke awal jika sudah habis
+	for _, template := range sorted {
+		if template.ID > recent[0] {
+			return template, nil
+		}
+	}
+	return sorted[0], nil
+}
+
+func (roundRobinStrategy) String() string {
+	return "roundrobin"
+}
+
+// leastRecentStrategy memilih template yang paling lama tidak dikirim ke grup
+type leastRecentStrategy struct{}
+
+func (leastRecentStrategy) Select(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) (database.PromoteTemplate, error) {
+	lastSent, err := history.LastSentTimes(groupJID)
+	if err != nil {
+		return database.PromoteTemplate{}, err
+	}
+	return pickLeastRecent(templates, lastSent), nil
+}
+
+func (leastRecentStrategy) String() string {
+	return "leastrecent"
+}
+
+// noRepeatStrategy memilih acak (berbobot) tanpa mengulang N kiriman terakhir ke grup
+type noRepeatStrategy struct {
+	window int
+}
+
+func (s noRepeatStrategy) Select(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) (database.PromoteTemplate, error) {
+	recent, err := history.RecentTemplateIDs(groupJID, s.window)
+	if err != nil {
+		return database.PromoteTemplate{}, err
+	}
+
+	excluded := make(map[int]bool)
+	for _, id := range recent {
+		excluded[id] = true
+	}
+
+	var candidates []database.PromoteTemplate
+	for _, template := range templates {
+		if !excluded[template.ID] {
+			candidates = append(candidates, template)
+		}
+	}
+
+	// Pool lebih kecil dari window: pakai yang paling lama tidak dikirim
+	if len(candidates) == 0 {
+		lastSent, err := history.LastSentTimes(groupJID)
+		if err != nil {
+			return database.PromoteTemplate{}, err
+		}
+		return pickLeastRecent(templates, lastSent), nil
+	}
+
+	return pickWeighted(candidates), nil
+}
+
+func (s noRepeatStrategy) String() string {
+	if s.window == defaultNoRepeatWindow {
+		return "norepeat"
+	}
+	return fmt.Sprintf("norepeat:%d", s.window)
+}
+
+// pickWeighted memilih template acak dengan peluang sebanding bobotnya (bobot < 1 dianggap 1)
+func pickWeighted(templates []database.PromoteTemplate) database.PromoteTemplate {
+	total := 0
+	for _, template := range templates {
+		total += templateWeight(template)
+	}
+
+	n := rand.Intn(total)
+	for _, template := range templates {
+		n -= templateWeight(template)
+		if n < 0 {
+			return template
+		}
+	}
+	return templates[len(templates)-1]
+}
+
+// pickLeastRecent memilih template yang belum pernah dikirim (acak berbobot), atau yang paling lama
+func pickLeastRecent(templates []database.PromoteTemplate, lastSent map[int]time.Time) database.PromoteTemplate {
+	var neverSent []database.PromoteTemplate
+	for _, template := range templates {
+		if _, ok := lastSent[template.ID]; !ok {
+			neverSent = append(neverSent, template)
+		}
+	}
+	if len(neverSent) > 0 {
+		return pickWeighted(neverSent)
+	}
+
+	oldest := templates[0]
+	for _, template := range templates[1:] {
+		if lastSent[template.ID].Before(lastSent[oldest.ID]) {
+			oldest = template
+		}
+	}
+	return oldest
+}
+
+func templateWeight(template database.PromoteTemplate) int {
+	if template.Weight < 1 {
+		return 1
+	}
+	return template.Weight
+}
+
+func sortedByID(templates []database.PromoteTemplate) []database.PromoteTemplate {
+	sorted := make([]database.PromoteTemplate, len(templates))
+	copy(sorted, templates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+// repositoryHistory membaca riwayat kiriman dari promote_logs
+type repositoryHistory struct {
+	repository database.Repository
+}
+
+func (h repositoryHistory) RecentTemplateIDs(groupJID string, limit int) ([]int, error) {
+	return h.repository.GetRecentTemplateIDs(groupJID, limit)
+}
+
+func (h repositoryHistory) LastSentTimes(groupJID string) (map[int]time.Time, error) {
+	return h.repository.GetTemplateLastSent(groupJID)
+}
+
+// TemplateSelector memilih template untuk grup sesuai strategi grup atau strategi global
+type TemplateSelector struct {
+	repository database.Repository
+	logger     *utils.Logger
+	history    TemplateHistory
+
+	mutex          sync.RWMutex
+	strategy       TemplateStrategy // Strategi global yang berlaku
+	configStrategy TemplateStrategy // Strategi dari konfigurasi (env)
+	fromDB         bool             // Strategi global berasal dari promote_settings
+}
+
+// NewTemplateSelector membuat selector dengan strategi global weighted random
+func NewTemplateSelector(repo database.Repository, logger *utils.Logger) *TemplateSelector {
+	return &TemplateSelector{
+		repository:     repo,
+		logger:         logger,
+		history:        repositoryHistory{repository: repo},
+		strategy:       weightedRandomStrategy{},
+		configStrategy: weightedRandomStrategy{},
+	}
+}
+
+// SetDefaultStrategy mengatur strategi global dari konfigurasi
+func (t *TemplateSelector) SetDefaultStrategy(expr string) error {
+	strategy, err := ParseTemplateStrategy(expr)
+	if err != nil {
+		return err
+	}
+
+	t.mutex.Lock()
+	t.configStrategy = strategy
+	if !t.fromDB {
+		t.strategy = strategy
+	}
+	t.mutex.Unlock()
+
+	t.logger.Infof("Template selection strategy set to %s", strategy.String())
+	return nil
+}
+
+// LoadSettings memuat strategi global yang disimpan admin di database
+func (t *TemplateSelector) LoadSettings() error {
+	setting, err := t.repository.GetSetting(settingTemplateStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to get template strategy setting: %v", err)
+	}
+	if setting == nil {
+		return nil
+	}
+
+	strategy, err := ParseTemplateStrategy(setting.Value)
+	if err != nil {
+		return fmt.Errorf("invalid stored template strategy %q: %v", setting.Value, err)
+	}
+
+	t.mutex.Lock()
+	t.strategy = strategy
+	t.fromDB = true
+	t.mutex.Unlock()
+
+	t.logger.Infof("Template selection strategy loaded from database: %s", strategy.String())
+	return nil
+}
+
+// UpdateGlobalStrategy mengubah strategi global dan menyimpannya ke database.
+// Ekspresi "default" menghapus override dan kembali ke konfigurasi.
+func (t *TemplateSelector) UpdateGlobalStrategy(expr string) error {
+	if strings.EqualFold(strings.TrimSpace(expr), "default") {
+		if err := t.repository.DeleteSetting(settingTemplateStrategy); err != nil {
+			return fmt.Errorf("failed to delete template strategy setting: %v", err)
+		}
+
+		t.mutex.Lock()
+		t.strategy = t.configStrategy
+		t.fromDB = false
+		t.mutex.Unlock()
+		return nil
+	}
+
+	strategy, err := ParseTemplateStrategy(expr)
+	if err != nil {
+		return err
+	}
+
+	if err := t.repository.SetSetting(settingTemplateStrategy, strategy.String()); err != nil {
+		return fmt.Errorf("failed to save template strategy setting: %v", err)
+	}
+
+	t.mutex.Lock()
+	t.strategy = strategy
+	t.fromDB = true
+	t.mutex.Unlock()
+
+	t.logger.Infof("Template selection strategy updated to %s", strategy.String())
+	return nil
+}
+
+// GetGlobalStrategy mengembalikan strategi global yang sedang berlaku
+func (t *TemplateSelector) GetGlobalStrategy() TemplateStrategy {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.strategy
+}
+
+// GetStrategySource mengembalikan asal strategi global (database atau konfigurasi)
+func (t *TemplateSelector) GetStrategySource() string {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if t.fromDB {
+		return "database (.setstrategy)"
+	}
+	return "konfigurasi (PROMOTE_TEMPLATE_STRATEGY)"
+}
+
+// StrategyForGroup mengembalikan strategi grup, atau strategi global jika tidak diatur
+func (t *TemplateSelector) StrategyForGroup(group *database.AutoPromoteGroup) TemplateStrategy {
+	if group != nil && group.TemplateStrategy != "" {
+		strategy, err := ParseTemplateStrategy(group.TemplateStrategy)
+		if err == nil {
+			return strategy
+		}
+		t.logger.Warningf("Invalid template strategy %q for group %s, using global strategy: %v", group.TemplateStrategy, group.GroupJID, err)
+	}
+
+	return t.GetGlobalStrategy()
+}
+
+// GetStrategyDescription mengembalikan strategi efektif grup dalam bentuk teks
+func (t *TemplateSelector) GetStrategyDescription(group *database.AutoPromoteGroup) string {
+	if group == nil || group.TemplateStrategy == "" {
+		return fmt.Sprintf("%s (global)", t.GetGlobalStrategy().String())
+	}
+	return t.StrategyForGroup(group).String()
+}
+
+// SetGroupStrategy mengatur strategi pemilihan template untuk grup tertentu.
+// Ekspresi kosong atau "default" mengembalikan grup ke strategi global.
+func (t *TemplateSelector) SetGroupStrategy(groupJID, expr string) (*database.AutoPromoteGroup, error) {
+	expr = strings.TrimSpace(expr)
+
+	normalized := ""
+	if expr != "" && strings.ToLower(expr) != "default" {
+		strategy, err := ParseTemplateStrategy(expr)
+		if err != nil {
+			return nil, err
+		}
+		normalized = strategy.String()
+	}
+
+	group, err := t.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %v", err)
+	}
+
+	if group == nil {
+		group, err = t.repository.CreateAutoPromoteGroup(groupJID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group: %v", err)
+		}
+	}
+
+	group.TemplateStrategy = normalized
+	if err := t.repository.UpdateAutoPromoteGroup(group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %v", err)
+	}
+
+	t.logger.Infof("Template strategy for group %s set to %q", groupJID, t.StrategyForGroup(group).String())
+	return group, nil
+}
+
+// Select memilih template untuk grup berdasarkan riwayat di promote_logs
+func (t *TemplateSelector) Select(groupJID string, templates []database.PromoteTemplate) database.PromoteTemplate {
+	return t.SelectWithHistory(groupJID, templates, t.history)
+}
+
+// SelectWithHistory memilih template dengan sumber riwayat tertentu (misal riwayat simulasi forecast).
+// Jika riwayat gagal dibaca, pemilihan jatuh ke weighted random agar pengiriman tetap berjalan.
+func (t *TemplateSelector) SelectWithHistory(groupJID string, templates []database.PromoteTemplate, history TemplateHistory) database.PromoteTemplate {
+	if len(templates) == 0 {
+		// Return empty template jika tidak ada
+		return database.PromoteTemplate{}
+	}
+
+	group, err := t.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		t.logger.Warningf("Failed to get group %s for template selection: %v", groupJID, err)
+	}
+
+	strategy := t.StrategyForGroup(group)
+	template, err := strategy.Select(groupJID, templates, history)
+	if err != nil {
+		t.logger.Warningf("Template strategy %s failed for group %s, using random: %v", strategy.String(), groupJID, err)
+		return pickWeighted(templates)
+	}
+
+	return template
+}
@@ -0,0 +1,113 @@
+// Package services - Terjemahan template dan bahasa promosi per grup
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nabilulilalbab/promote/database"
+	"github.com/nabilulilalbab/promote/render"
+)
+
+// SetGroupLocale mengatur bahasa promosi grup.
+// Nilai kosong atau "default" mengembalikan grup ke bahasa default.
+func (r *TemplateRenderer) SetGroupLocale(groupJID, value string) (*database.AutoPromoteGroup, error) {
+	value = strings.TrimSpace(value)
+
+	locale := ""
+	if value != "" && !strings.EqualFold(value, "default") {
+		parsed, err := render.ParseLocale(value)
+		if err != nil {
+			return nil, err
+		}
+		locale = parsed
+	}
+
+	group, err := r.repository.GetAutoPromoteGroup(groupJID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group: %v", err)
+	}
+
+	if group == nil {
+		group, err = r.repository.CreateAutoPromoteGroup(groupJID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create group: %v", err)
+		}
+	}
+
+	group.Locale = locale
+	if err := r.repository.UpdateAutoPromoteGroup(group); err != nil {
+		return nil, fmt.Errorf("failed to update group: %v", err)
+	}
+
+	r.logger.Infof("Locale for group %s set to %q", groupJID, r.GroupLocale(groupJID))
+	return group, nil
+}
+
+// SetTemplateTranslation membuat atau mengganti terjemahan template untuk satu bahasa
+func (s *TemplateService) SetTemplateTranslation(templateID int, locale, content, author string) (*database.TemplateTranslation, error) {
+	if _, err := s.GetTemplateByID(templateID); err != nil {
+		return nil, err
+	}
+
+	locale, err := render.ParseLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, fmt.Errorf("konten terjemahan tidak boleh kosong")
+	}
+	if err := s.renderer.Validate(content); err != nil {
+		return nil, err
+	}
+
+	translation := &database.TemplateTranslation{
+		TemplateID: templateID,
+		Locale:     locale,
+		Content:    content,
+		UpdatedBy:  author,
+	}
+	if err := s.repository.SetTemplateTranslation(translation); err != nil {
+		s.logger.Errorf("Failed to save %s translation of template %d: %v", locale, templateID, err)
+		return nil, fmt.Errorf("gagal menyimpan terjemahan: %v", err)
+	}
+
+	s.logger.Successf("Template %d translated to %s by %s", templateID, locale, author)
+	return translation, nil
+}
+
+// GetTemplateTranslations mengembalikan template beserta semua terjemahannya
+func (s *TemplateService) GetTemplateTranslations(templateID int) (*database.PromoteTemplate, []database.TemplateTranslation, error) {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	translations, err := s.repository.GetTemplateTranslations(templateID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal memuat terjemahan: %v", err)
+	}
+	return template, translations, nil
+}
+
+// DeleteTemplateTranslation menghapus terjemahan; grup berbahasa tersebut kembali menerima konten asli
+func (s *TemplateService) DeleteTemplateTranslation(templateID int, locale string) (string, error) {
+	locale, err := render.ParseLocale(locale)
+	if err != nil {
+		return "", err
+	}
+
+	deleted, err := s.repository.DeleteTemplateTranslation(templateID, locale)
+	if err != nil {
+		s.logger.Errorf("Failed to delete %s translation of template %d: %v", locale, templateID, err)
+		return "", fmt.Errorf("gagal menghapus terjemahan: %v", err)
+	}
+	if !deleted {
+		return "", fmt.Errorf("template ID %d belum punya terjemahan %s", templateID, locale)
+	}
+
+	s.logger.Infof("Translation %s of template %d deleted", locale, templateID)
+	return locale, nil
+}
@@ -0,0 +1,336 @@
+// Package services - Masa berlaku template (tanggal, hari dan jam tayang)
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// Bagian masa berlaku yang bisa diatur lewat .validity
+const (
+	ValidityFrom  = "from"
+	ValidityUntil = "until"
+	ValidityDays  = "days"
+	ValidityHours = "hours"
+	ValidityClear = "clear"
+)
+
+// expiredRevisionAuthor dicatat di riwayat saat template dinonaktifkan karena kedaluwarsa
+const expiredRevisionAuthor = "auto-expire"
+
+// adminDateLayout adalah format tanggal tanpa jam untuk masa berlaku template
+const adminDateLayout = "2006-01-02"
+
+// weekdayLabels adalah singkatan hari yang disimpan di valid_days, urut Senin-Minggu
+var weekdayLabels = []struct {
+	day   time.Weekday
+	label string
+}{
+	{time.Monday, "sen"},
+	{time.Tuesday, "sel"},
+	{time.Wednesday, "rab"},
+	{time.Thursday, "kam"},
+	{time.Friday, "jum"},
+	{time.Saturday, "sab"},
+	{time.Sunday, "min"},
+}
+
+// weekdayNumbers memetakan nama hari ke nomor hari cron (Minggu = 7 agar "sab-min" valid)
+var weekdayNumbers = map[string]int{
+	"sen": 1, "senin": 1, "mon": 1,
+	"sel": 2, "selasa": 2, "tue": 2,
+	"rab": 3, "rabu": 3, "wed": 3,
+	"kam": 4, "kamis": 4, "thu": 4,
+	"jum": 5, "jumat": 5, "fri": 5,
+	"sab": 6, "sabtu": 6, "sat": 6,
+	"min": 7, "minggu": 7, "sun": 7,
+}
+
+var weekdayNamePattern = regexp.MustCompile(`[a-z]+`)
+
+// ParseWeekdays memparse hari tayang seperti "sen-jum", "sab,min" atau "1-5".
+// Hasilnya dinormalisasi menjadi daftar singkatan ("sen,sel,rab"), kosong berarti setiap hari.
+func ParseWeekdays(expr string) (string, error) {
+	expr = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(expr), " ", ""))
+	if expr == "" || expr == "all" || expr == "*" {
+		return "", nil
+	}
+
+	var unknown string
+	numeric := weekdayNamePattern.ReplaceAllStringFunc(expr, func(name string) string {
+		number, ok := weekdayNumbers[name]
+		if !ok {
+			unknown = name
+			return name
+		}
+		return strconv.Itoa(number)
+	})
+	if unknown != "" {
+		return "", fmt.Errorf("nama hari tidak dikenal: %s (gunakan sen, sel, rab, kam, jum, sab, min)", unknown)
+	}
+
+	days, err := parseCronField(numeric, 0, 7)
+	if err != nil {
+		return "", fmt.Errorf("hari tidak valid: %v", err)
+	}
+	if days[7] {
+		days[0] = true
+	}
+
+	var labels []string
+	for _, weekday := range weekdayLabels {
+		if days[int(weekday.day)] {
+			labels = append(labels, weekday.label)
+		}
+	}
+	if len(labels) == len(weekdayLabels) {
+		return "", nil
+	}
+	return strings.Join(labels, ","), nil
+}
+
+// weekdayAllowed mengecek hari terhadap valid_days yang sudah dinormalisasi
+func weekdayAllowed(validDays string, day time.Weekday) bool {
+	if validDays == "" {
+		return true
+	}
+
+	for _, weekday := range weekdayLabels {
+		if weekday.day == day {
+			for _, label := range strings.Split(validDays, ",") {
+				if label == weekday.label {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+// TemplateExpired mengecek apakah masa berlaku template sudah berakhir
+func TemplateExpired(template *database.PromoteTemplate, now time.Time) bool {
+	return template.ValidUntil != nil && !now.Before(*template.ValidUntil)
+}
+
+// TemplateValidAt mengecek apakah template boleh dikirim pada waktu now
+// (now harus sudah dalam timezone scheduler agar hari dan jam tayang sesuai)
+func TemplateValidAt(template *database.PromoteTemplate, now time.Time) bool {
+	if template.ValidFrom != nil && now.Before(*template.ValidFrom) {
+		return false
+	}
+	if TemplateExpired(template, now) {
+		return false
+	}
+	if !weekdayAllowed(template.ValidDays, now.Weekday()) {
+		return false
+	}
+	if template.ValidHours != "" {
+		window, err := ParseSendWindow(template.ValidHours)
+		if err == nil && !window.IsOpen(now) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasValidity mengecek apakah template punya batasan masa berlaku
+func HasValidity(template *database.PromoteTemplate) bool {
+	return template.ValidFrom != nil || template.ValidUntil != nil ||
+		template.ValidDays != "" || template.ValidHours != ""
+}
+
+// filterValidTemplates menyaring template yang sedang dalam masa tayang
+func filterValidTemplates(templates []database.PromoteTemplate, now time.Time) []database.PromoteTemplate {
+	var valid []database.PromoteTemplate
+	for _, template := range templates {
+		if TemplateValidAt(&template, now) {
+			valid = append(valid, template)
+		}
+	}
+	return valid
+}
+
+// DescribeValidity menampilkan masa berlaku template dalam satu baris (kosong jika tanpa batasan)
+func DescribeValidity(template *database.PromoteTemplate, location *time.Location) string {
+	var parts []string
+
+	switch {
+	case template.ValidFrom != nil && template.ValidUntil != nil:
+		parts = append(parts, fmt.Sprintf("%s s/d %s",
+			template.ValidFrom.In(location).Format(adminTimeLayout), template.ValidUntil.In(location).Format(adminTimeLayout)))
+	case template.ValidFrom != nil:
+		parts = append(parts, "mulai "+template.ValidFrom.In(location).Format(adminTimeLayout))
+	case template.ValidUntil != nil:
+		parts = append(parts, "sampai "+template.ValidUntil.In(location).Format(adminTimeLayout))
+	}
+
+	if template.ValidDays != "" {
+		parts = append(parts, template.ValidDays)
+	}
+	if template.ValidHours != "" {
+		parts = append(parts, template.ValidHours)
+	}
+
+	return strings.Join(parts, " • ")
+}
+
+// parseValidityTime memparse "YYYY-MM-DD HH:MM" atau "YYYY-MM-DD".
+// Tanggal tanpa jam berarti awal hari untuk from dan akhir hari (23:59) untuk until.
+func parseValidityTime(value string, endOfDay bool, location *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.ParseInLocation(adminTimeLayout, value, location); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(adminDateLayout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("format waktu tidak valid: %s (gunakan YYYY-MM-DD atau YYYY-MM-DD HH:MM)", value)
+	}
+	if endOfDay {
+		t = t.Add(23*time.Hour + 59*time.Minute)
+	}
+	return t, nil
+}
+
+// isValidityOff mengecek nilai yang berarti menghapus batasan
+func isValidityOff(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "off", "-", "none":
+		return true
+	}
+	return false
+}
+
+// SetTemplateValidity mengubah satu bagian masa berlaku template (from, until, days, hours)
+// atau menghapus semuanya (clear). Waktu dibaca dalam timezone location.
+// Template yang dinonaktifkan karena kedaluwarsa diaktifkan kembali jika masa berlakunya diperpanjang.
+func (s *TemplateService) SetTemplateValidity(id int, field, value string, location *time.Location, author string) (*database.PromoteTemplate, bool, error) {
+	template, err := s.GetTemplateByID(id)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now().In(location)
+	wasExpired := TemplateExpired(template, now)
+	from, until := template.ValidFrom, template.ValidUntil
+	days, hours := template.ValidDays, template.ValidHours
+
+	switch field {
+	case ValidityFrom, ValidityUntil:
+		var t *time.Time
+		if !isValidityOff(value) {
+			parsed, err := parseValidityTime(value, field == ValidityUntil, location)
+			if err != nil {
+				return nil, false, err
+			}
+			t = &parsed
+		}
+		if field == ValidityFrom {
+			from = t
+		} else {
+			if t != nil && !t.After(now) {
+				return nil, false, fmt.Errorf("waktu berakhir %s sudah lewat", t.Format(adminTimeLayout))
+			}
+			until = t
+		}
+	case ValidityDays:
+		if days, err = ParseWeekdays(value); err != nil {
+			return nil, false, err
+		}
+	case ValidityHours:
+		window, err := ParseSendWindow(value)
+		if err != nil {
+			return nil, false, err
+		}
+		hours = ""
+		if !window.IsAlwaysOpen() {
+			hours = window.String()
+		}
+	case ValidityClear:
+		from, until, days, hours = nil, nil, "", ""
+	default:
+		return nil, false, fmt.Errorf("bagian '%s' tidak dikenal (gunakan from, until, days, hours atau clear)", field)
+	}
+
+	if from != nil && until != nil && !until.After(*from) {
+		return nil, false, fmt.Errorf("waktu berakhir harus setelah waktu mulai")
+	}
+
+	s.ensureBaselineRevision(template)
+	if _, err := s.repository.SetTemplateValidity(id, from, until, days, hours); err != nil {
+		s.logger.Errorf("Failed to set validity of template %d: %v", id, err)
+		return nil, false, fmt.Errorf("gagal menyimpan masa berlaku: %v", err)
+	}
+
+	template.ValidFrom, template.ValidUntil = from, until
+	template.ValidDays, template.ValidHours = days, hours
+
+	reactivated := false
+	if wasExpired && !template.IsActive && !TemplateExpired(template, now) {
+		template.IsActive = true
+		if err := s.repository.UpdateTemplate(template); err != nil {
+			s.logger.Errorf("Failed to reactivate template %d: %v", id, err)
+			template.IsActive = false
+		} else {
+			reactivated = true
+		}
+	}
+	s.recordRevision(template, database.RevisionValidity, author)
+
+	s.logger.Successf("Template validity set: %s (ID: %d) %s", template.Title, template.ID, field)
+	return template, reactivated, nil
+}
+
+// ExpireTemplates menonaktifkan template aktif yang masa berlakunya sudah berakhir.
+// Perubahan dicatat di riwayat sehingga bisa dilihat dan di-rollback.
+func (s *TemplateService) ExpireTemplates(now time.Time) ([]database.PromoteTemplate, error) {
+	templates, err := s.repository.GetActiveTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []database.PromoteTemplate
+	for _, template := range templates {
+		if !TemplateExpired(&template, now) {
+			continue
+		}
+
+		s.ensureBaselineRevision(&template)
+		template.IsActive = false
+		if err := s.repository.UpdateTemplate(&template); err != nil {
+			s.logger.Errorf("Failed to deactivate expired template %d: %v", template.ID, err)
+			continue
+		}
+		s.recordRevision(&template, database.RevisionStatus, expiredRevisionAuthor)
+
+		s.logger.Infof("Template expired and deactivated: %s (ID: %d)", template.Title, template.ID)
+		expired = append(expired, template)
+	}
+
+	sort.Slice(expired, func(i, j int) bool { return expired[i].ID < expired[j].ID })
+	return expired, nil
+}
+
+// FormatExpiredNotice menyusun pemberitahuan admin untuk template yang baru kedaluwarsa
+func FormatExpiredNotice(expired []database.PromoteTemplate, location *time.Location) string {
+	var notice strings.Builder
+	notice.WriteString("⏰ *TEMPLATE KEDALUWARSA*\n")
+	notice.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
+	notice.WriteString("Template berikut sudah melewati masa berlaku dan dinonaktifkan otomatis:\n\n")
+
+	for _, template := range expired {
+		notice.WriteString(fmt.Sprintf("• *ID %d* - %s (berakhir %s)\n",
+			template.ID, template.Title, template.ValidUntil.In(location).Format(adminTimeLayout)))
+	}
+
+	notice.WriteString("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n")
+	notice.WriteString("💡 Perpanjang dengan *.validity [ID] until [YYYY-MM-DD HH:MM]*, template akan aktif kembali.")
+	return notice.String()
+}
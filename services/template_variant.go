@@ -0,0 +1,236 @@
+// Package services - Varian template untuk A/B test dan laporannya
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/nabilulilalbab/promote/database"
+)
+
+// maxTemplateVariants adalah jumlah varian (termasuk yang nonaktif) per template, yaitu label B sampai K
+const maxTemplateVariants = 10
+
+// originalVariantLabel adalah label konten asli template di laporan A/B
+const originalVariantLabel = "A"
+
+// ABVariantReport adalah hasil satu varian di laporan A/B
+type ABVariantReport struct {
+	VariantID int    // 0 = konten asli template
+	Label     string // A untuk konten asli, B, C, ... untuk varian
+	Content   string
+	IsActive  bool
+	Stats     database.VariantStats
+}
+
+// ABReport adalah perbandingan semua varian satu template
+type ABReport struct {
+	Template   *database.PromoteTemplate
+	Variants   []ABVariantReport
+	Translated database.VariantStats // Kiriman terjemahan, tidak ikut perbandingan varian
+}
+
+// RenderTemplate memilih varian template untuk grup tujuan lalu merendernya.
+// Grup yang punya terjemahan untuk bahasanya menerima terjemahan tersebut
+// (A/B test hanya berlaku untuk konten asli).
+// Mengembalikan konten dan ID varian yang dipakai: 0 untuk konten asli template
+// dan database.TranslatedVariantID untuk terjemahan.
+func (r *TemplateRenderer) RenderTemplate(template *database.PromoteTemplate, groupJID string, now time.Time) (string, int, error) {
+	locale := r.GroupLocale(groupJID)
+	translation, err := r.repository.GetTemplateTranslation(template.ID, locale)
+	if err != nil {
+		return "", 0, fmt.Errorf("gagal memuat terjemahan template: %v", err)
+	}
+	if translation != nil {
+		result, err := r.renderLocale(translation.Content, groupJID, locale, now)
+		if err != nil {
+			return "", 0, err
+		}
+		return result, database.TranslatedVariantID, nil
+	}
+
+	variants, err := r.repository.GetTemplateVariants(template.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("gagal memuat varian template: %v", err)
+	}
+
+	content, variantID := pickVariant(template, variants, groupJID)
+	result, err := r.renderLocale(content, groupJID, locale, now)
+	if err != nil {
+		return "", 0, err
+	}
+	return result, variantID, nil
+}
+
+// pickVariant membagi kiriman rata antara konten asli dan varian aktif.
+// Dengan pembagian per grup, grup yang sama selalu mendapat varian yang sama
+// selama daftar varian aktif tidak berubah.
+func pickVariant(template *database.PromoteTemplate, variants []database.TemplateVariant, groupJID string) (string, int) {
+	var active []database.TemplateVariant
+	for _, variant := range variants {
+		if variant.IsActive {
+			active = append(active, variant)
+		}
+	}
+	if len(active) == 0 {
+		return template.Content, 0
+	}
+
+	var index int
+	if template.ABSplit == database.ABSplitGroup {
+		hash := fnv.New32a()
+		hash.Write([]byte(groupJID))
+		index = int(hash.Sum32() % uint32(len(active)+1))
+	} else {
+		index = rand.Intn(len(active) + 1)
+	}
+
+	if index == 0 {
+		return template.Content, 0
+	}
+	return active[index-1].Content, active[index-1].ID
+}
+
+// AddTemplateVariant menambahkan varian konten baru ke template
+func (s *TemplateService) AddTemplateVariant(templateID int, content, author string) (*database.TemplateVariant, error) {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if err := s.validateTemplate(template.Title, content, template.Category); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repository.GetTemplateVariants(templateID)
+	if err != nil {
+		return nil, fmt.Errorf("gagal memuat varian template: %v", err)
+	}
+
+	if len(variants) >= maxTemplateVariants {
+		return nil, fmt.Errorf("template ID %d sudah punya %d varian (maksimal)", templateID, maxTemplateVariants)
+	}
+
+	for _, variant := range variants {
+		if variant.IsActive && variant.Content == content {
+			return nil, fmt.Errorf("konten sama dengan varian %s", variant.Label)
+		}
+	}
+	if template.Content == content {
+		return nil, fmt.Errorf("konten sama dengan template asli (varian %s)", originalVariantLabel)
+	}
+
+	variant := &database.TemplateVariant{
+		TemplateID: templateID,
+		Content:    content,
+		CreatedBy:  author,
+	}
+
+	if err := s.repository.CreateTemplateVariant(variant); err != nil {
+		s.logger.Errorf("Failed to create variant for template %d: %v", templateID, err)
+		return nil, fmt.Errorf("gagal menyimpan varian: %v", err)
+	}
+
+	s.logger.Successf("Variant %s added to template %d", variant.Label, templateID)
+	return variant, nil
+}
+
+// GetTemplateVariants mengambil semua varian template (termasuk yang nonaktif)
+func (s *TemplateService) GetTemplateVariants(templateID int) (*database.PromoteTemplate, []database.TemplateVariant, error) {
+	template, err := s.GetTemplateByID(templateID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	variants, err := s.repository.GetTemplateVariants(templateID)
+	if err != nil {
+		s.logger.Errorf("Failed to get variants of template %d: %v", templateID, err)
+		return nil, nil, fmt.Errorf("gagal memuat varian template: %v", err)
+	}
+
+	return template, variants, nil
+}
+
+// DisableTemplateVariant menghentikan pengiriman varian. Hasilnya tetap muncul di laporan A/B.
+func (s *TemplateService) DisableTemplateVariant(templateID int, label string) error {
+	label = strings.ToUpper(strings.TrimSpace(label))
+	if label == originalVariantLabel {
+		return fmt.Errorf("varian %s adalah konten asli template, gunakan .toggletemplate atau .edittemplate", originalVariantLabel)
+	}
+
+	updated, err := s.repository.SetTemplateVariantActive(templateID, label, false)
+	if err != nil {
+		s.logger.Errorf("Failed to disable variant %s of template %d: %v", label, templateID, err)
+		return fmt.Errorf("gagal menonaktifkan varian: %v", err)
+	}
+
+	if !updated {
+		return fmt.Errorf("varian %s untuk template ID %d tidak ditemukan", label, templateID)
+	}
+
+	s.logger.Infof("Variant %s of template %d disabled", label, templateID)
+	return nil
+}
+
+// SetABSplit mengatur pembagian varian template: per kiriman atau per grup
+func (s *TemplateService) SetABSplit(templateID int, split string) error {
+	split = strings.ToLower(strings.TrimSpace(split))
+	if split != database.ABSplitSend && split != database.ABSplitGroup {
+		return fmt.Errorf("pembagian '%s' tidak dikenal, gunakan %s atau %s", split, database.ABSplitSend, database.ABSplitGroup)
+	}
+
+	if _, err := s.GetTemplateByID(templateID); err != nil {
+		return err
+	}
+
+	if err := s.repository.SetTemplateABSplit(templateID, split); err != nil {
+		s.logger.Errorf("Failed to set A/B split of template %d: %v", templateID, err)
+		return fmt.Errorf("gagal menyimpan pembagian varian: %v", err)
+	}
+
+	s.logger.Infof("Template %d A/B split set to %s", templateID, split)
+	return nil
+}
+
+// GetABReport membandingkan kiriman, read receipt dan balasan per varian template
+func (s *TemplateService) GetABReport(templateID int) (*ABReport, error) {
+	template, variants, err := s.GetTemplateVariants(templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repository.GetVariantStats(templateID)
+	if err != nil {
+		s.logger.Errorf("Failed to get variant stats of template %d: %v", templateID, err)
+		return nil, fmt.Errorf("gagal mengambil statistik varian: %v", err)
+	}
+
+	statsByVariant := make(map[int]database.VariantStats, len(stats))
+	for _, stat := range stats {
+		statsByVariant[stat.VariantID] = stat
+	}
+
+	report := &ABReport{Template: template, Translated: statsByVariant[database.TranslatedVariantID]}
+	report.Variants = append(report.Variants, ABVariantReport{
+		Label:    originalVariantLabel,
+		Content:  template.Content,
+		IsActive: template.IsActive,
+		Stats:    statsByVariant[0],
+	})
+
+	for _, variant := range variants {
+		report.Variants = append(report.Variants, ABVariantReport{
+			VariantID: variant.ID,
+			Label:     variant.Label,
+			Content:   variant.Content,
+			IsActive:  variant.IsActive,
+			Stats:     statsByVariant[variant.ID],
+		})
+	}
+
+	return report, nil
+}